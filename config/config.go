@@ -16,6 +16,18 @@ func InitDB() *gorm.DB {
 		fmt.Println("Failed to connect to database!")
 		panic("Failed to connect to database!")
 	}
-	db.AutoMigrate(&model.Book{}, &model.User{})
+	db.AutoMigrate(&model.Book{}, &model.User{}, &model.Copy{}, &model.Loan{}, &model.LoanEvent{}, &model.Hold{}, &model.LoanPolicy{}, &model.FineEntry{}, &model.Review{},
+		&model.ReviewReport{}, &model.ModerationLog{}, &model.ReviewBan{},
+		&model.ReadingList{}, &model.ListEntry{},
+		&model.ReadingProgress{}, &model.ProgressUpdate{}, &model.ReadingGoal{},
+		&model.BookRevision{}, &model.UserRevision{},
+		&model.ImportJob{}, &model.ImportRow{}, &model.BookMARC{}, &model.Cover{}, &model.Ebook{},
+		&model.Work{}, &model.Series{})
 	return db
 }
+
+// RequireIfMatch reports whether updates and deletes must carry an If-Match
+// header. It is on unless REQUIRE_IF_MATCH is set to false.
+func RequireIfMatch() bool {
+	return os.Getenv("REQUIRE_IF_MATCH") != "false"
+}
@@ -0,0 +1,8 @@
+package config
+
+// ImportSyncLimit returns the size, in bytes, up to which a book import runs
+// while the client waits, taken from IMPORT_SYNC_MAX_BYTES and defaulting
+// to 1 MiB. Larger uploads are imported in the background.
+func ImportSyncLimit() int64 {
+	return int64(envInt("IMPORT_SYNC_MAX_BYTES", 1<<20))
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"strconv"
+	"time"
+
+	"go.test/model"
+)
+
+// DefaultLoanPolicy is applied when no loan rule matches a checkout. Each
+// limit can be overridden through the environment.
+func DefaultLoanPolicy() model.LoanPolicy {
+	return model.LoanPolicy{
+		LoanPeriodDays: envInt("LOAN_PERIOD_DAYS", 14),
+		MaxRenewals:    envInt("LOAN_MAX_RENEWALS", 2),
+		MaxLoans:       envInt("LOAN_MAX_LOANS", 10),
+		GraceDays:      envInt("LOAN_GRACE_DAYS", 0),
+		DailyFine:      int64(envInt("FINE_DAILY_RATE", 25)),
+		MaxFine:        int64(envInt("FINE_MAX", 1000)),
+	}
+}
+
+// FineBlockThreshold returns the outstanding balance, in cents, above which
+// a user can no longer borrow, taken from FINE_BLOCK_THRESHOLD and
+// defaulting to 500.
+func FineBlockThreshold() int64 {
+	return int64(envInt("FINE_BLOCK_THRESHOLD", 500))
+}
+
+// LoanPolicies loads the loan rule table from the JSON file named by
+// LOAN_POLICY_FILE. Rules stored in the database take precedence over these.
+func LoanPolicies() ([]model.LoanPolicy, error) {
+	path := os.Getenv("LOAN_POLICY_FILE")
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var policies []model.LoanPolicy
+	if err := json.Unmarshal(data, &policies); err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+// HoldPickupPeriod returns how long a copy set aside for a hold waits to be
+// picked up, taken from HOLD_PICKUP_DAYS and defaulting to three days.
+func HoldPickupPeriod() time.Duration {
+	return time.Duration(envInt("HOLD_PICKUP_DAYS", 3)) * 24 * time.Hour
+}
+
+// HoldSweepInterval returns how often expired holds are cleaned up, taken
+// from HOLD_SWEEP_MINUTES and defaulting to fifteen minutes. Zero disables
+// the sweep.
+func HoldSweepInterval() time.Duration {
+	return time.Duration(envInt("HOLD_SWEEP_MINUTES", 15)) * time.Minute
+}
+
+func envInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
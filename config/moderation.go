@@ -0,0 +1,31 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+)
+
+// ReviewFilterRules returns the words and patterns that hold a review for
+// moderation. Words are read from the comma-separated REVIEW_FILTER_WORDS,
+// and REVIEW_FILTER_FILE can name a JSON file with "words" and "patterns"
+// lists.
+func ReviewFilterRules() ([]string, []string, error) {
+	var rules struct {
+		Words    []string `json:"words"`
+		Patterns []string `json:"patterns"`
+	}
+	if path := os.Getenv("REVIEW_FILTER_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := json.Unmarshal(data, &rules); err != nil {
+			return nil, nil, err
+		}
+	}
+	if words := os.Getenv("REVIEW_FILTER_WORDS"); words != "" {
+		rules.Words = append(rules.Words, strings.Split(words, ",")...)
+	}
+	return rules.Words, rules.Patterns, nil
+}
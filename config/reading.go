@@ -0,0 +1,8 @@
+package config
+
+// ReadingReportMinReaders returns how many different readers must have
+// finished a book before the reading report names it, taken from
+// READING_REPORT_MIN_READERS and defaulting to 5.
+func ReadingReportMinReaders() int {
+	return envInt("READING_REPORT_MIN_READERS", 5)
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"crypto/rand"
+	"fmt"
+	"os"
+	"time"
+
+	"go.test/storage"
+)
+
+// StorageDir returns the directory that uploaded files are kept in, taken
+// from STORAGE_DIR and defaulting to "data".
+func StorageDir() string {
+	if dir := os.Getenv("STORAGE_DIR"); dir != "" {
+		return dir
+	}
+	return "data"
+}
+
+// CoverMaxBytes returns the largest cover image that can be uploaded, taken
+// from COVER_MAX_BYTES and defaulting to 5 MiB.
+func CoverMaxBytes() int64 {
+	return int64(envInt("COVER_MAX_BYTES", 5<<20))
+}
+
+// URLSigner returns the signer of addresses that work without a token for a
+// while, such as those of files in the local filesystem store. Addresses
+// start with PUBLIC_URL, if set. The secret is taken from URL_SECRET;
+// without one a random secret is used, and the addresses stop working when
+// the service restarts.
+func URLSigner() *storage.URLSigner {
+	secret := []byte(os.Getenv("URL_SECRET"))
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		rand.Read(secret)
+	}
+	return storage.NewURLSigner(os.Getenv("PUBLIC_URL")+"/api", secret)
+}
+
+// BlobStore returns the store that uploaded files are kept in. BLOB_BACKEND
+// chooses between the local directory of StorageDir, the default, and an
+// S3-compatible bucket given by S3_ENDPOINT, S3_REGION, S3_BUCKET,
+// S3_ACCESS_KEY_ID and S3_SECRET_ACCESS_KEY. S3_PATH_STYLE=true puts the
+// bucket in the path, as MinIO needs.
+func BlobStore(signer *storage.URLSigner) (storage.BlobStore, error) {
+	switch backend := os.Getenv("BLOB_BACKEND"); backend {
+	case "", "fs":
+		return storage.NewFSStore(StorageDir(), signer)
+	case "s3":
+		return storage.NewS3Store(storage.S3Config{
+			Endpoint:        os.Getenv("S3_ENDPOINT"),
+			Region:          os.Getenv("S3_REGION"),
+			Bucket:          os.Getenv("S3_BUCKET"),
+			AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+			PathStyle:       os.Getenv("S3_PATH_STYLE") == "true",
+		})
+	default:
+		return nil, fmt.Errorf("BLOB_BACKEND must be fs or s3, not %q", backend)
+	}
+}
+
+// EbookMaxBytes returns the largest ebook file that can be uploaded, taken
+// from EBOOK_MAX_BYTES and defaulting to 200 MiB.
+func EbookMaxBytes() int64 {
+	return int64(envInt("EBOOK_MAX_BYTES", 200<<20))
+}
+
+// EbookURLLifetime returns how long ebook download addresses work, taken
+// from EBOOK_URL_MINUTES and defaulting to ten minutes.
+func EbookURLLifetime() time.Duration {
+	return time.Duration(envInt("EBOOK_URL_MINUTES", 10)) * time.Minute
+}
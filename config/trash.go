@@ -0,0 +1,17 @@
+package config
+
+import "time"
+
+// TrashRetention returns how long deleted books and users stay in the trash
+// before they are purged, taken from TRASH_RETENTION_DAYS and defaulting to
+// 30 days. Zero disables automatic purging.
+func TrashRetention() time.Duration {
+	return time.Duration(envInt("TRASH_RETENTION_DAYS", 30)) * 24 * time.Hour
+}
+
+// TrashSweepInterval returns how often the trash is checked for records past
+// their retention, taken from TRASH_SWEEP_MINUTES and defaulting to an hour.
+// Zero disables the sweep.
+func TrashSweepInterval() time.Duration {
+	return time.Duration(envInt("TRASH_SWEEP_MINUTES", 60)) * time.Minute
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"go.test/storage"
+	"go.test/usecase"
+
+	"github.com/labstack/echo/v4"
+)
+
+type BlobHandler struct {
+	BlobUsecase usecase.BlobUsecase
+}
+
+func NewBlobHandler(blobUsecase usecase.BlobUsecase) *BlobHandler {
+	return &BlobHandler{blobUsecase}
+}
+
+// GetBlob serves a stored file at a presigned address, which takes the
+// place of authentication. Files that can seek are served with support for
+// ranges and conditional requests.
+func (h *BlobHandler) GetBlob(c echo.Context) error {
+	// The decoded path is used rather than the route parameter, which may or
+	// may not be escaped depending on the characters in the key.
+	key := strings.TrimPrefix(c.Request().URL.Path, "/api/blobs/")
+	file, info, err := h.BlobUsecase.OpenSigned(key, c.QueryParam("expires"), c.QueryParam("signature"))
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrInvalidSignature), errors.Is(err, storage.ErrExpiredURL):
+			return c.JSON(http.StatusForbidden, map[string]string{"message": err.Error()})
+		case errors.Is(err, storage.ErrNotFound), errors.Is(err, storage.ErrInvalidKey):
+			return c.JSON(http.StatusNotFound, map[string]string{"message": "Not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, err)
+	}
+	defer file.Close()
+	if seeker, ok := file.(io.ReadSeeker); ok {
+		http.ServeContent(c.Response(), c.Request(), key, info.UpdatedAt, seeker)
+		return nil
+	}
+	return c.Stream(http.StatusOK, echo.MIMEOctetStream, file)
+}
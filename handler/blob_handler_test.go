@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.test/storage"
+	"go.test/usecase/mocks"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type readSeekCloser struct {
+	io.ReadSeeker
+}
+
+func (readSeekCloser) Close() error { return nil }
+
+func TestGetBlob(t *testing.T) {
+	e := echo.New()
+	blobUsecase := new(mocks.BlobUsecase)
+	h := NewBlobHandler(blobUsecase)
+	e.GET("/api/blobs/*", h.GetBlob)
+
+	info := &storage.BlobInfo{Key: "ebooks/1/a+b.txt", Size: 10, UpdatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	blobUsecase.On("OpenSigned", "ebooks/1/a+b.txt", "1700000000", "good").
+		Return(readSeekCloser{strings.NewReader("0123456789")}, info, nil).Once()
+	blobUsecase.On("OpenSigned", "ebooks/1/a+b.txt", "1700000000", "bad").
+		Return(nil, nil, storage.ErrInvalidSignature).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/blobs/ebooks/1/a%2Bb.txt?expires=1700000000&signature=good", nil)
+	req.Header.Set("Range", "bytes=2-4")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusPartialContent, rec.Code)
+	assert.Equal(t, "234", rec.Body.String())
+	assert.Equal(t, "bytes 2-4/10", rec.Header().Get("Content-Range"))
+
+	req = httptest.NewRequest(http.MethodGet, "/api/blobs/ebooks/1/a+b.txt?expires=1700000000&signature=bad", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	blobUsecase.AssertExpectations(t)
+}
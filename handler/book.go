@@ -1,14 +1,19 @@
 package handler
 
 import (
+	"errors"
+	"io"
+	"mime"
 	"net/http"
 	"strconv"
 
 	"go.test/middleware"
 	"go.test/model"
 	"go.test/usecase"
+	util "go.test/utils"
 
 	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
 )
 
 type BookHandler struct {
@@ -20,19 +25,25 @@ func NewBookHandler(bookUsecase usecase.BookUsecase) *BookHandler {
 }
 
 func (h *BookHandler) GetBooks(c echo.Context) error {
-	books, err := h.BookUsecase.GetAllBooks()
+	filter := new(model.BookFilter)
+	if err := c.Bind(filter); err != nil {
+		return c.JSON(http.StatusBadRequest, err)
+	}
+	books, err := h.BookUsecase.GetAllBooks(filter)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, err)
+		return bookError(c, err)
 	}
 	return c.JSON(http.StatusOK, books)
 }
 
 func (h *BookHandler) GetBook(c echo.Context) error {
 	id, _ := strconv.Atoi(c.Param("id"))
-	book, err := h.BookUsecase.GetBookByID(uint(id))
+	username, _ := c.Get("username").(string)
+	book, err := h.BookUsecase.GetBookByID(uint(id), username)
 	if err != nil {
 		return c.JSON(http.StatusNotFound, err)
 	}
+	c.Response().Header().Set("ETag", util.ETag(book.Version))
 	return c.JSON(http.StatusOK, book)
 }
 
@@ -41,7 +52,7 @@ func (h *BookHandler) CreateBook(c echo.Context) error {
 	if err := c.Bind(book); err != nil {
 		return c.JSON(http.StatusBadRequest, err)
 	}
-	if err := h.BookUsecase.CreateBook(book); err != nil {
+	if err := h.BookUsecase.CreateBook(book, c.Get("username").(string)); err != nil {
 		return c.JSON(http.StatusInternalServerError, err)
 	}
 	return c.JSON(http.StatusCreated, book)
@@ -58,9 +69,37 @@ func (h *BookHandler) updateBookHandler(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, err)
 	}
 	book.ID = uint(id)
-	if err := h.BookUsecase.UpdateBook(book); err != nil {
-		return c.JSON(http.StatusInternalServerError, err)
+	version, ok := ifMatch(c)
+	if !ok {
+		return c.JSON(http.StatusPreconditionFailed, map[string]string{"message": usecase.ErrVersionMismatch.Error()})
+	}
+	book.Version = version
+	if err := h.BookUsecase.UpdateBook(book, c.Get("username").(string)); err != nil {
+		return bookError(c, err)
 	}
+	c.Response().Header().Set("ETag", util.ETag(book.Version))
+	return c.JSON(http.StatusOK, book)
+}
+
+func (h *BookHandler) PatchBook(c echo.Context) error {
+	return middleware.RoleBasedAccess(h.patchBookHandler, "supervisor")(c)
+}
+
+func (h *BookHandler) patchBookHandler(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	version, ok := ifMatch(c)
+	if !ok {
+		return c.JSON(http.StatusPreconditionFailed, map[string]string{"message": usecase.ErrVersionMismatch.Error()})
+	}
+	mediaType, patch, err := readPatch(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err)
+	}
+	book, err := h.BookUsecase.PatchBook(uint(id), version, mediaType, patch, c.Get("username").(string))
+	if err != nil {
+		return bookError(c, err)
+	}
+	c.Response().Header().Set("ETag", util.ETag(book.Version))
 	return c.JSON(http.StatusOK, book)
 }
 
@@ -70,8 +109,82 @@ func (h *BookHandler) DeleteBook(c echo.Context) error {
 
 func (h *BookHandler) deleteBookHandler(c echo.Context) error {
 	id, _ := strconv.Atoi(c.Param("id"))
-	if err := h.BookUsecase.DeleteBook(uint(id)); err != nil {
-		return c.JSON(http.StatusInternalServerError, err)
+	version, ok := ifMatch(c)
+	if !ok {
+		return c.JSON(http.StatusPreconditionFailed, map[string]string{"message": usecase.ErrVersionMismatch.Error()})
+	}
+	if err := h.BookUsecase.DeleteBook(uint(id), version, c.Get("username").(string)); err != nil {
+		return bookError(c, err)
 	}
 	return c.NoContent(http.StatusNoContent)
 }
+
+func (h *BookHandler) GetHistory(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	revisions, err := h.BookUsecase.GetHistory(uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"message": "Not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, err)
+	}
+	return c.JSON(http.StatusOK, revisions)
+}
+
+func (h *BookHandler) RevertBook(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	revision, _ := strconv.Atoi(c.Param("rev"))
+	book, err := h.BookUsecase.RevertBook(uint(id), revision, c.Get("username").(string))
+	if err != nil {
+		return bookError(c, err)
+	}
+	c.Response().Header().Set("ETag", util.ETag(book.Version))
+	return c.JSON(http.StatusOK, book)
+}
+
+// ifMatch returns the version named by the request's If-Match header, or 0
+// when any version will do. ok is false when the header cannot match any
+// version.
+func ifMatch(c echo.Context) (version int, ok bool) {
+	return util.ParseIfMatch(c.Request().Header.Get("If-Match"))
+}
+
+// readPatch returns the media type and body of a PATCH request, and lists
+// the patch formats that are accepted in the response.
+func readPatch(c echo.Context) (string, []byte, error) {
+	c.Response().Header().Set("Accept-Patch", util.MergePatchType+", "+util.JSONPatchType)
+	mediaType, _, _ := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType))
+	patch, err := io.ReadAll(c.Request().Body)
+	return mediaType, patch, err
+}
+
+// patchStatus returns the status code RFC 5789 suggests for an error from
+// applying a patch, or 0 for other errors.
+func patchStatus(err error) int {
+	switch {
+	case errors.Is(err, usecase.ErrMalformedPatch):
+		return http.StatusBadRequest
+	case errors.Is(err, usecase.ErrUnsupportedPatch):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, usecase.ErrPatchConflict):
+		return http.StatusConflict
+	case errors.Is(err, usecase.ErrInvalidPatch):
+		return http.StatusUnprocessableEntity
+	}
+	return 0
+}
+
+func bookError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Not found"})
+	case errors.Is(err, usecase.ErrVersionMismatch):
+		return c.JSON(http.StatusPreconditionFailed, map[string]string{"message": err.Error()})
+	case errors.Is(err, usecase.ErrInvalidCollapse):
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	}
+	if status := patchStatus(err); status != 0 {
+		return c.JSON(status, map[string]string{"message": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, err)
+}
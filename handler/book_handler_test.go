@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"go.test/config"
@@ -18,7 +19,9 @@ import (
 
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
 )
 
 type BookHandlerTestSuite struct {
@@ -30,7 +33,12 @@ type BookHandlerTestSuite struct {
 func (suite *BookHandlerTestSuite) SetupSuite() {
 	db := config.InitDB()
 	bookRepo := repository.NewBookRepository(db)
-	bookUsecase := usecase.NewBookUsecase(bookRepo)
+	copyRepo := repository.NewCopyRepository(db)
+	reviewRepo := repository.NewReviewRepository(db)
+	listRepo := repository.NewListRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	coverRepo := repository.NewCoverRepository(db)
+	bookUsecase := usecase.NewBookUsecase(bookRepo, copyRepo, reviewRepo, listRepo, userRepo, coverRepo)
 	suite.BookHandler = NewBookHandler(bookUsecase)
 	suite.Echo = echo.New()
 }
@@ -59,16 +67,17 @@ func TestCreateBook(t *testing.T) {
 
 	h := NewBookHandler(bookUsecase)
 
-	book := &model.Book{Title: "New Book", Author: "New Author", ISBN: "0987654321", PublishedDate: "2023-01-01"}
+	book := &model.Book{Title: "New Book", Author: "New Author", ISBN: "0987654321", PublishedDate: model.PartialDate{Year: 2023, Month: 1, Day: 1}}
 
 	bookJSON, _ := json.Marshal(book)
 
-	bookUsecase.On("CreateBook", book).Return(nil)
+	bookUsecase.On("CreateBook", book, "zai").Return(nil)
 
 	req := httptest.NewRequest(http.MethodPost, "/api/books", bytes.NewReader(bookJSON))
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
+	c.Set("username", "zai")
 
 	err := h.CreateBook(c)
 
@@ -98,14 +107,14 @@ func TestUpdateBook(t *testing.T) {
 		Title:         "Updated Book",
 		Author:        "Updated Author",
 		ISBN:          "0987654321",
-		PublishedDate: "2023-01-01",
+		PublishedDate: model.PartialDate{Year: 2023, Month: 1, Day: 1},
 	}
 
 	// Convert book to JSON
 	bookJSON, _ := json.Marshal(book)
 
 	// Mock UpdateBook method to return nil error
-	bookUsecase.On("UpdateBook", book).Return(nil)
+	bookUsecase.On("UpdateBook", book, "zai").Return(nil)
 
 	// Create a request to update a book
 	req := httptest.NewRequest(http.MethodPut, "/api/books/1", bytes.NewReader(bookJSON))
@@ -115,6 +124,7 @@ func TestUpdateBook(t *testing.T) {
 	c := e.NewContext(req, rec)
 	c.SetParamNames("id")
 	c.SetParamValues("1")
+	c.Set("username", "zai")
 	c.Set("role", "supervisor")
 
 	// Call the UpdateBook handler
@@ -143,14 +153,16 @@ func TestDeleteBook(t *testing.T) {
 	h := NewBookHandler(bookUsecase)
 
 	// Mock DeleteBook method to return nil error
-	bookUsecase.On("DeleteBook", uint(1)).Return(nil)
+	bookUsecase.On("DeleteBook", uint(1), 3, "zai").Return(nil)
 
 	// Create a request to delete a book
 	req := httptest.NewRequest(http.MethodDelete, "/api/books/1", nil)
+	req.Header.Set("If-Match", `"3"`)
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
 	c.SetParamNames("id")
 	c.SetParamValues("1")
+	c.Set("username", "zai")
 	c.Set("role", "manager")
 
 	// Call the DeleteBook handler
@@ -169,3 +181,195 @@ func TestDeleteBook(t *testing.T) {
 func TestBookHandlerTestSuite(t *testing.T) {
 	suite.Run(t, new(BookHandlerTestSuite))
 }
+
+func TestRevertBook(t *testing.T) {
+	e := echo.New()
+	bookUsecase := new(mocks.BookUsecase)
+	h := NewBookHandler(bookUsecase)
+
+	bookUsecase.On("RevertBook", uint(1), 2, "zai").Return(&model.Book{ID: 1, Title: "Old Title"}, nil).Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/books/1/revert/2", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id", "rev")
+	c.SetParamValues("1", "2")
+	c.Set("username", "zai")
+
+	assert.NoError(t, h.RevertBook(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	bookUsecase.AssertExpectations(t)
+}
+
+func TestGetHistoryOfMissingBook(t *testing.T) {
+	e := echo.New()
+	bookUsecase := new(mocks.BookUsecase)
+	h := NewBookHandler(bookUsecase)
+
+	bookUsecase.On("GetHistory", uint(9)).Return(nil, gorm.ErrRecordNotFound).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/books/9/history", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("9")
+
+	assert.NoError(t, h.GetHistory(c))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	bookUsecase.AssertExpectations(t)
+}
+
+func TestUpdateBookWithStaleETag(t *testing.T) {
+	e := echo.New()
+	bookUsecase := new(mocks.BookUsecase)
+	h := NewBookHandler(bookUsecase)
+
+	bookUsecase.On("UpdateBook", mock.MatchedBy(func(book *model.Book) bool {
+		return book.ID == 1 && book.Version == 2
+	}), "zai").Return(usecase.ErrVersionMismatch).Once()
+
+	req := httptest.NewRequest(http.MethodPut, "/api/books/1", strings.NewReader(`{"title":"Stale"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("If-Match", `"2"`)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	c.Set("username", "zai")
+	c.Set("role", "supervisor")
+
+	assert.NoError(t, h.UpdateBook(c))
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+
+	bookUsecase.AssertExpectations(t)
+}
+
+func TestDeleteBookWithWeakETag(t *testing.T) {
+	e := echo.New()
+	bookUsecase := new(mocks.BookUsecase)
+	h := NewBookHandler(bookUsecase)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/books/1", nil)
+	req.Header.Set("If-Match", `W/"2"`)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	c.Set("username", "zai")
+	c.Set("role", "manager")
+
+	assert.NoError(t, h.DeleteBook(c))
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+
+	bookUsecase.AssertNotCalled(t, "DeleteBook", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetBookSetsETag(t *testing.T) {
+	e := echo.New()
+	bookUsecase := new(mocks.BookUsecase)
+	h := NewBookHandler(bookUsecase)
+
+	bookUsecase.On("GetBookByID", uint(1), "zai").Return(&model.Book{ID: 1, Version: 4}, nil).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/books/1", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	c.Set("username", "zai")
+
+	assert.NoError(t, h.GetBook(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"4"`, rec.Header().Get("ETag"))
+
+	bookUsecase.AssertExpectations(t)
+}
+
+func TestPatchBook(t *testing.T) {
+	e := echo.New()
+	bookUsecase := new(mocks.BookUsecase)
+	h := NewBookHandler(bookUsecase)
+
+	patch := `{"author":"Frank Herbert"}`
+	bookUsecase.On("PatchBook", uint(1), 3, util.MergePatchType, []byte(patch), "zai").
+		Return(&model.Book{ID: 1, Title: "Dune", Author: "Frank Herbert", Version: 4}, nil).Once()
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/books/1", strings.NewReader(patch))
+	req.Header.Set(echo.HeaderContentType, util.MergePatchType+"; charset=utf-8")
+	req.Header.Set("If-Match", `"3"`)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	c.Set("username", "zai")
+	c.Set("role", "supervisor")
+
+	assert.NoError(t, h.PatchBook(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"4"`, rec.Header().Get("ETag"))
+
+	bookUsecase.AssertExpectations(t)
+}
+
+func TestPatchBookErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		expectedCode int
+	}{
+		{"Unsupported format", usecase.ErrUnsupportedPatch, http.StatusUnsupportedMediaType},
+		{"Malformed patch", usecase.ErrMalformedPatch, http.StatusBadRequest},
+		{"Failed test operation", usecase.ErrPatchConflict, http.StatusConflict},
+		{"Invalid result", usecase.ErrInvalidPatch, http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			bookUsecase := new(mocks.BookUsecase)
+			h := NewBookHandler(bookUsecase)
+
+			bookUsecase.On("PatchBook", uint(1), 0, mock.Anything, mock.Anything, "zai").Return(nil, tt.err).Once()
+
+			req := httptest.NewRequest(http.MethodPatch, "/api/books/1", strings.NewReader(`[]`))
+			req.Header.Set(echo.HeaderContentType, util.JSONPatchType)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues("1")
+			c.Set("username", "zai")
+			c.Set("role", "supervisor")
+
+			assert.NoError(t, h.PatchBook(c))
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			assert.Contains(t, rec.Header().Get("Accept-Patch"), util.JSONPatchType)
+
+			bookUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetBooksCollapsedByWork(t *testing.T) {
+	e := echo.New()
+	bookUsecase := new(mocks.BookUsecase)
+	h := NewBookHandler(bookUsecase)
+
+	books := []model.Book{{ID: 2, Title: "Dune", EditionCount: 3}}
+	bookUsecase.On("GetAllBooks", &model.BookFilter{Collapse: model.BookCollapseWork}).Return(books, nil).Once()
+	bookUsecase.On("GetAllBooks", &model.BookFilter{Collapse: "author"}).Return(nil, usecase.ErrInvalidCollapse).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/books?collapse=work", nil)
+	rec := httptest.NewRecorder()
+	assert.NoError(t, h.GetBooks(e.NewContext(req, rec)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"edition_count":3`)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/books?collapse=author", nil)
+	rec = httptest.NewRecorder()
+	assert.NoError(t, h.GetBooks(e.NewContext(req, rec)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	bookUsecase.AssertExpectations(t)
+}
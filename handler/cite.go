@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go.test/usecase"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type CitationHandler struct {
+	CitationUsecase usecase.CitationUsecase
+}
+
+func NewCitationHandler(citationUsecase usecase.CitationUsecase) *CitationHandler {
+	return &CitationHandler{citationUsecase}
+}
+
+func (h *CitationHandler) CiteBook(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	return h.cite(c, []uint{uint(id)})
+}
+
+// CiteBooks cites the books listed in the ids query parameter, as in
+// ids=1,2,3.
+func (h *CitationHandler) CiteBooks(c echo.Context) error {
+	var ids []uint
+	for _, field := range strings.Split(c.QueryParam("ids"), ",") {
+		id, err := strconv.ParseUint(strings.TrimSpace(field), 10, 0)
+		if err != nil || id == 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": "ids must be a comma-separated list of book IDs"})
+		}
+		ids = append(ids, uint(id))
+	}
+	return h.cite(c, ids)
+}
+
+// cite answers with citations of books. Citation styles are HTML for
+// clients that accept it.
+func (h *CitationHandler) cite(c echo.Context, ids []uint) error {
+	html := strings.Contains(c.Request().Header.Get(echo.HeaderAccept), echo.MIMETextHTML)
+	text, contentType, err := h.CitationUsecase.CiteBooks(ids, c.QueryParam("format"), html)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"message": "Not found"})
+		case errors.Is(err, usecase.ErrUnknownCitationFormat), errors.Is(err, usecase.ErrTooManyCitations):
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, err)
+	}
+	c.Response().Header().Add(echo.HeaderVary, echo.HeaderAccept)
+	return c.Blob(http.StatusOK, contentType, []byte(text))
+}
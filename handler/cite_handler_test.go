@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.test/usecase"
+	"go.test/usecase/mocks"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestCiteBook(t *testing.T) {
+	e := echo.New()
+	citationUsecase := new(mocks.CitationUsecase)
+	h := NewCitationHandler(citationUsecase)
+
+	citationUsecase.On("CiteBooks", []uint{1}, "mla", true).
+		Return("<p>Herbert, Frank. <i>Dune</i>. 1965.</p>\n", "text/html; charset=utf-8", nil).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/books/1/cite?format=mla", nil)
+	req.Header.Set(echo.HeaderAccept, "text/html,application/xhtml+xml")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	assert.NoError(t, h.CiteBook(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/html; charset=utf-8", rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, "<p>Herbert, Frank. <i>Dune</i>. 1965.</p>\n", rec.Body.String())
+
+	citationUsecase.AssertExpectations(t)
+}
+
+func TestCiteBooks(t *testing.T) {
+	e := echo.New()
+	citationUsecase := new(mocks.CitationUsecase)
+	h := NewCitationHandler(citationUsecase)
+
+	citationUsecase.On("CiteBooks", []uint{3, 1}, "bibtex", false).
+		Return("", "", gorm.ErrRecordNotFound).Once()
+	citationUsecase.On("CiteBooks", []uint{1}, "xml", false).
+		Return("", "", usecase.ErrUnknownCitationFormat).Once()
+
+	for _, tc := range []struct {
+		query  string
+		status int
+	}{
+		{"ids=3,%201&format=bibtex", http.StatusNotFound},
+		{"ids=1&format=xml", http.StatusBadRequest},
+		{"ids=1,x", http.StatusBadRequest},
+		{"", http.StatusBadRequest},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/api/books/cite?"+tc.query, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		assert.NoError(t, h.CiteBooks(c))
+		assert.Equal(t, tc.status, rec.Code, tc.query)
+	}
+
+	citationUsecase.AssertExpectations(t)
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go.test/model"
+	"go.test/usecase"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type CopyHandler struct {
+	CopyUsecase usecase.CopyUsecase
+}
+
+func NewCopyHandler(copyUsecase usecase.CopyUsecase) *CopyHandler {
+	return &CopyHandler{copyUsecase}
+}
+
+func (h *CopyHandler) GetCopies(c echo.Context) error {
+	bookID, _ := strconv.Atoi(c.Param("id"))
+	copies, err := h.CopyUsecase.GetCopiesByBook(uint(bookID))
+	if err != nil {
+		return copyError(c, err)
+	}
+	return c.JSON(http.StatusOK, copies)
+}
+
+func (h *CopyHandler) GetAvailability(c echo.Context) error {
+	bookID, _ := strconv.Atoi(c.Param("id"))
+	availability, err := h.CopyUsecase.GetAvailability(uint(bookID))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, err)
+	}
+	return c.JSON(http.StatusOK, availability)
+}
+
+func (h *CopyHandler) CreateCopy(c echo.Context) error {
+	bookID, _ := strconv.Atoi(c.Param("id"))
+	bookCopy := new(model.Copy)
+	if err := c.Bind(bookCopy); err != nil {
+		return c.JSON(http.StatusBadRequest, err)
+	}
+	bookCopy.ID = 0
+	bookCopy.BookID = uint(bookID)
+	if err := h.CopyUsecase.CreateCopy(bookCopy); err != nil {
+		return copyError(c, err)
+	}
+	return c.JSON(http.StatusCreated, bookCopy)
+}
+
+func (h *CopyHandler) GetCopy(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	bookCopy, err := h.CopyUsecase.GetCopyByID(uint(id))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, err)
+	}
+	return c.JSON(http.StatusOK, bookCopy)
+}
+
+func (h *CopyHandler) UpdateCopy(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	bookCopy := new(model.Copy)
+	if err := c.Bind(bookCopy); err != nil {
+		return c.JSON(http.StatusBadRequest, err)
+	}
+	bookCopy.ID = uint(id)
+	if err := h.CopyUsecase.UpdateCopy(bookCopy); err != nil {
+		return copyError(c, err)
+	}
+	return c.JSON(http.StatusOK, bookCopy)
+}
+
+func (h *CopyHandler) DeleteCopy(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	if err := h.CopyUsecase.DeleteCopy(uint(id)); err != nil {
+		return copyError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func copyError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Not found"})
+	case errors.Is(err, usecase.ErrInvalidCopyStatus), errors.Is(err, usecase.ErrBarcodeRequired):
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	case errors.Is(err, usecase.ErrCopyStatusManaged), errors.Is(err, usecase.ErrCopyInUse), errors.Is(err, usecase.ErrBarcodeTaken):
+		return c.JSON(http.StatusConflict, map[string]string{"message": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, err)
+}
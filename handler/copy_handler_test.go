@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.test/model"
+	"go.test/usecase"
+	"go.test/usecase/mocks"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestCreateCopy(t *testing.T) {
+	e := echo.New()
+	copyUsecase := new(mocks.CopyUsecase)
+	h := NewCopyHandler(copyUsecase)
+
+	body, _ := json.Marshal(model.Copy{Barcode: "B-0001", Condition: "good", Location: "Main 2F"})
+
+	copyUsecase.On("CreateCopy", mock.MatchedBy(func(c *model.Copy) bool {
+		return c.BookID == 7 && c.Barcode == "B-0001"
+	})).Return(nil).Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/books/7/copies", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("7")
+
+	err := h.CreateCopy(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	var created model.Copy
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, uint(7), created.BookID)
+
+	copyUsecase.AssertExpectations(t)
+}
+
+func TestCreateCopyInvalidStatus(t *testing.T) {
+	e := echo.New()
+	copyUsecase := new(mocks.CopyUsecase)
+	h := NewCopyHandler(copyUsecase)
+
+	body, _ := json.Marshal(model.Copy{Barcode: "B-0002", Status: "borrowed"})
+
+	copyUsecase.On("CreateCopy", mock.Anything).Return(usecase.ErrInvalidCopyStatus).Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/books/7/copies", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("7")
+
+	assert.NoError(t, h.CreateCopy(c))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	copyUsecase.AssertExpectations(t)
+}
+
+func TestCreateCopyBarcode(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		code int
+	}{
+		{usecase.ErrBarcodeRequired, http.StatusBadRequest},
+		{usecase.ErrBarcodeTaken, http.StatusConflict},
+	} {
+		e := echo.New()
+		copyUsecase := new(mocks.CopyUsecase)
+		h := NewCopyHandler(copyUsecase)
+
+		body, _ := json.Marshal(model.Copy{Condition: "good"})
+
+		copyUsecase.On("CreateCopy", mock.Anything).Return(tc.err).Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/api/books/7/copies", bytes.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("7")
+
+		assert.NoError(t, h.CreateCopy(c))
+		assert.Equal(t, tc.code, rec.Code)
+		assert.Contains(t, rec.Body.String(), tc.err.Error())
+
+		copyUsecase.AssertExpectations(t)
+	}
+}
+
+func TestGetCopiesUnknownBook(t *testing.T) {
+	e := echo.New()
+	copyUsecase := new(mocks.CopyUsecase)
+	h := NewCopyHandler(copyUsecase)
+
+	copyUsecase.On("GetCopiesByBook", uint(99)).Return(nil, gorm.ErrRecordNotFound).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/books/99/copies", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("99")
+
+	assert.NoError(t, h.GetCopies(c))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	copyUsecase.AssertExpectations(t)
+}
+
+func TestUpdateCopyOnLoan(t *testing.T) {
+	e := echo.New()
+	copyUsecase := new(mocks.CopyUsecase)
+	h := NewCopyHandler(copyUsecase)
+
+	copyUsecase.On("UpdateCopy", mock.MatchedBy(func(c *model.Copy) bool {
+		return c.ID == 3 && c.Status == model.CopyStatusAvailable
+	})).Return(usecase.ErrCopyStatusManaged).Once()
+	copyUsecase.On("DeleteCopy", uint(3)).Return(usecase.ErrCopyInUse).Once()
+
+	body, _ := json.Marshal(model.Copy{Barcode: "B-0003", Status: model.CopyStatusAvailable})
+	req := httptest.NewRequest(http.MethodPut, "/api/copies/3", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("3")
+
+	assert.NoError(t, h.UpdateCopy(c))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/copies/3", nil)
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("3")
+
+	assert.NoError(t, h.DeleteCopy(c))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	copyUsecase.AssertExpectations(t)
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go.test/model"
+	"go.test/storage"
+	"go.test/usecase"
+	util "go.test/utils"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type CoverHandler struct {
+	CoverUsecase usecase.CoverUsecase
+}
+
+func NewCoverHandler(coverUsecase usecase.CoverUsecase) *CoverHandler {
+	return &CoverHandler{coverUsecase}
+}
+
+// SetCover replaces the cover of a book with the image in the request body,
+// or in the "file" part of a multipart upload.
+func (h *CoverHandler) SetCover(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	data, _, _, err := upload(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	}
+	defer data.Close()
+	cover, err := h.CoverUsecase.SetCover(uint(id), data)
+	if err != nil {
+		return coverError(c, err)
+	}
+	return c.JSON(http.StatusOK, cover)
+}
+
+// GetCover sends the cover of a book in the size asked for. Addresses that
+// name the current version, as cover URLs do, can be cached for good; others
+// have to be revalidated, which the ETag makes cheap.
+func (h *CoverHandler) GetCover(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	size := c.QueryParam("size")
+	if size == "" {
+		size = model.CoverSizeOriginal
+	}
+	cover, err := h.CoverUsecase.GetCover(uint(id))
+	if err != nil {
+		return coverError(c, err)
+	}
+	file, err := h.CoverUsecase.OpenCover(cover, size)
+	if err != nil {
+		return coverError(c, err)
+	}
+	defer file.Close()
+
+	etag := `"` + cover.Version + "-" + size + `"`
+	header := c.Response().Header()
+	header.Set("ETag", etag)
+	if c.QueryParam("v") == cover.Version {
+		header.Set("Cache-Control", "public, max-age=31536000, immutable")
+	} else {
+		header.Set("Cache-Control", "no-cache")
+	}
+	if util.MatchesETag(c.Request().Header.Get("If-None-Match"), etag) {
+		return c.NoContent(http.StatusNotModified)
+	}
+	return c.Stream(http.StatusOK, cover.MediaType(size), file)
+}
+
+func (h *CoverHandler) DeleteCover(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	if err := h.CoverUsecase.DeleteCover(uint(id)); err != nil {
+		return coverError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func coverError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound), errors.Is(err, storage.ErrNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Not found"})
+	case errors.Is(err, usecase.ErrCoverTooLarge):
+		return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{"message": err.Error()})
+	case errors.Is(err, usecase.ErrUnsupportedImage):
+		return c.JSON(http.StatusUnsupportedMediaType, map[string]string{"message": err.Error()})
+	case errors.Is(err, usecase.ErrUnknownCoverSize):
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, err)
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.test/model"
+	"go.test/usecase"
+	"go.test/usecase/mocks"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetCover(t *testing.T) {
+	e := echo.New()
+	coverUsecase := new(mocks.CoverUsecase)
+	h := NewCoverHandler(coverUsecase)
+
+	cover := &model.Cover{BookID: 1, Version: "0123456789abcdef", ContentType: "image/png", ThumbnailType: "image/jpeg"}
+	coverUsecase.On("GetCover", uint(1)).Return(cover, nil)
+	coverUsecase.On("OpenCover", cover, "small").Return(io.NopCloser(strings.NewReader("jpeg")), nil)
+	coverUsecase.On("OpenCover", cover, "original").Return(io.NopCloser(strings.NewReader("png")), nil)
+
+	for _, tc := range []struct {
+		query, ifNoneMatch string
+		code               int
+		contentType        string
+		cacheControl       string
+	}{
+		{"size=small&v=0123456789abcdef", "", http.StatusOK, "image/jpeg", "public, max-age=31536000, immutable"},
+		{"", "", http.StatusOK, "image/png", "no-cache"},
+		{"size=small&v=old", `W/"0123456789abcdef-small"`, http.StatusNotModified, "", "no-cache"},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/api/books/1/cover?"+tc.query, nil)
+		req.Header.Set("If-None-Match", tc.ifNoneMatch)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		assert.NoError(t, h.GetCover(c))
+		assert.Equal(t, tc.code, rec.Code, tc.query)
+		assert.Equal(t, tc.contentType, rec.Header().Get(echo.HeaderContentType), tc.query)
+		assert.Equal(t, tc.cacheControl, rec.Header().Get("Cache-Control"), tc.query)
+		assert.NotEmpty(t, rec.Header().Get("ETag"), tc.query)
+	}
+}
+
+func TestSetCoverErrors(t *testing.T) {
+	e := echo.New()
+	coverUsecase := new(mocks.CoverUsecase)
+	h := NewCoverHandler(coverUsecase)
+
+	coverUsecase.On("SetCover", uint(1), mock.Anything).Return(nil, usecase.ErrCoverTooLarge).Once()
+	coverUsecase.On("SetCover", uint(1), mock.Anything).Return(nil, usecase.ErrUnsupportedImage).Once()
+
+	for _, code := range []int{http.StatusRequestEntityTooLarge, http.StatusUnsupportedMediaType} {
+		req := httptest.NewRequest(http.MethodPut, "/api/books/1/cover", strings.NewReader("GIF89a"))
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		assert.NoError(t, h.SetCover(c))
+		assert.Equal(t, code, rec.Code)
+	}
+
+	coverUsecase.AssertExpectations(t)
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"go.test/model"
+	"go.test/usecase"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type DuplicateHandler struct {
+	DuplicateUsecase usecase.DuplicateUsecase
+}
+
+func NewDuplicateHandler(duplicateUsecase usecase.DuplicateUsecase) *DuplicateHandler {
+	return &DuplicateHandler{duplicateUsecase}
+}
+
+func (h *DuplicateHandler) GetDuplicates(c echo.Context) error {
+	filter := new(model.DuplicateFilter)
+	if err := c.Bind(filter); err != nil {
+		return c.JSON(http.StatusBadRequest, err)
+	}
+	pairs, err := h.DuplicateUsecase.FindDuplicates(filter)
+	if err != nil {
+		return duplicateError(c, err)
+	}
+	return c.JSON(http.StatusOK, pairs)
+}
+
+func (h *DuplicateHandler) MergeBooks(c echo.Context) error {
+	req := new(model.MergeRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, err)
+	}
+	result, err := h.DuplicateUsecase.MergeBooks(req, c.Get("username").(string))
+	if err != nil {
+		return duplicateError(c, err)
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+func duplicateError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Not found"})
+	case errors.Is(err, usecase.ErrVersionMismatch):
+		return c.JSON(http.StatusPreconditionFailed, map[string]string{"message": err.Error()})
+	case errors.Is(err, usecase.ErrInvalidDuplicateFilter), errors.Is(err, usecase.ErrMergeSameBook), errors.Is(err, usecase.ErrInvalidMergeField):
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, err)
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.test/model"
+	"go.test/usecase"
+	"go.test/usecase/mocks"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetDuplicates(t *testing.T) {
+	e := echo.New()
+	duplicateUsecase := new(mocks.DuplicateUsecase)
+	h := NewDuplicateHandler(duplicateUsecase)
+
+	pairs := []model.DuplicatePair{{Books: [2]model.Book{{ID: 1}, {ID: 2}}, Score: 0.9}}
+	duplicateUsecase.On("FindDuplicates", &model.DuplicateFilter{MinScore: 0.8, Limit: 10}).Return(pairs, nil).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/books/duplicates?min_score=0.8&limit=10", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	assert.NoError(t, h.GetDuplicates(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var got []model.DuplicatePair
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, uint(2), got[0].Books[1].ID)
+
+	duplicateUsecase.AssertExpectations(t)
+}
+
+func TestMergeBooks(t *testing.T) {
+	e := echo.New()
+	duplicateUsecase := new(mocks.DuplicateUsecase)
+	h := NewDuplicateHandler(duplicateUsecase)
+
+	result := &model.MergeResult{Book: &model.Book{ID: 1}, Moved: map[string]int64{"copies": 2}}
+	duplicateUsecase.On("MergeBooks", mock.MatchedBy(func(req *model.MergeRequest) bool {
+		return req.TargetID == 1 && req.SourceID == 2 && req.Fields["title"] == model.MergeSource
+	}), "ahmad").Return(result, nil).Once()
+
+	body := `{"target_id":1,"source_id":2,"fields":{"title":"source"}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/books/merge", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("username", "ahmad")
+
+	assert.NoError(t, h.MergeBooks(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"copies":2`)
+
+	duplicateUsecase.AssertExpectations(t)
+}
+
+func TestMergeBooksErrors(t *testing.T) {
+	tests := []struct {
+		err  error
+		code int
+	}{
+		{usecase.ErrMergeSameBook, http.StatusBadRequest},
+		{usecase.ErrInvalidMergeField, http.StatusBadRequest},
+		{usecase.ErrVersionMismatch, http.StatusPreconditionFailed},
+	}
+	for _, tt := range tests {
+		e := echo.New()
+		duplicateUsecase := new(mocks.DuplicateUsecase)
+		h := NewDuplicateHandler(duplicateUsecase)
+		duplicateUsecase.On("MergeBooks", mock.Anything, "ahmad").Return(nil, tt.err).Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/api/books/merge", strings.NewReader(`{"target_id":1,"source_id":1}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("username", "ahmad")
+
+		assert.NoError(t, h.MergeBooks(c))
+		assert.Equal(t, tt.code, rec.Code, tt.err.Error())
+	}
+}
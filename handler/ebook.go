@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"go.test/storage"
+	"go.test/usecase"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type EbookHandler struct {
+	EbookUsecase usecase.EbookUsecase
+}
+
+func NewEbookHandler(ebookUsecase usecase.EbookUsecase) *EbookHandler {
+	return &EbookHandler{ebookUsecase}
+}
+
+// AddEbook attaches the EPUB or PDF file in the request body, or in the
+// "file" part of a multipart upload, to a book. The response holds a draft
+// of the book from the file's metadata, for staff to confirm. Ebooks can
+// take longer to upload than the server's timeouts allow, so they do not
+// apply.
+func (h *EbookHandler) AddEbook(c echo.Context) error {
+	clearDeadlines(c)
+	id, _ := strconv.Atoi(c.Param("id"))
+	data, _, filename, err := upload(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	}
+	defer data.Close()
+	if filename == "" {
+		filename = c.QueryParam("filename")
+	}
+	ebook, err := h.EbookUsecase.AddEbook(uint(id), data, filename, c.Get("username").(string))
+	if err != nil {
+		return ebookError(c, err)
+	}
+	return c.JSON(http.StatusCreated, ebook)
+}
+
+func (h *EbookHandler) GetEbooks(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	ebooks, err := h.EbookUsecase.GetEbooks(uint(id))
+	if err != nil {
+		return ebookError(c, err)
+	}
+	return c.JSON(http.StatusOK, ebooks)
+}
+
+func (h *EbookHandler) DeleteEbook(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	if err := h.EbookUsecase.DeleteEbook(uint(id)); err != nil {
+		return ebookError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetDownload hands out a short-lived address the ebook can be downloaded
+// from without a token, so that browsers and reading apps can fetch it.
+func (h *EbookHandler) GetDownload(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	download, err := h.EbookUsecase.GetDownload(uint(id), c.Get("username").(string), c.Get("role").(string))
+	if err != nil {
+		return ebookError(c, err)
+	}
+	return c.JSON(http.StatusOK, download)
+}
+
+// Download serves an ebook at a signed address, with support for ranges so
+// that interrupted downloads can be resumed. The server's timeouts do not
+// apply, as the file can take longer to send.
+func (h *EbookHandler) Download(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	ebook, file, err := h.EbookUsecase.OpenDownload(uint(id), c.QueryParam("expires"), c.QueryParam("signature"))
+	if err != nil {
+		return ebookError(c, err)
+	}
+	defer file.Close()
+	clearDeadlines(c)
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentType, ebook.ContentType())
+	header.Set(echo.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": ebook.Filename}))
+	header.Set("ETag", `"`+ebook.Checksum+`"`)
+	header.Set("Cache-Control", "private")
+	content := &readError{ReadSeeker: file}
+	http.ServeContent(c.Response(), c.Request(), ebook.Filename, ebook.CreatedAt, content)
+	if errors.Is(content.err, storage.ErrChecksumMismatch) {
+		c.Logger().Errorf("ebook %d: %v", ebook.ID, content.err)
+	}
+	return nil
+}
+
+// readError keeps the first error reading a file gives, which
+// http.ServeContent does not report.
+type readError struct {
+	io.ReadSeeker
+	err error
+}
+
+func (r *readError) Read(p []byte) (int, error) {
+	n, err := r.ReadSeeker.Read(p)
+	if err != nil && err != io.EOF && r.err == nil {
+		r.err = err
+	}
+	return n, err
+}
+
+func ebookError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound), errors.Is(err, storage.ErrNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Not found"})
+	case errors.Is(err, usecase.ErrNoActiveLoan), errors.Is(err, storage.ErrInvalidSignature), errors.Is(err, storage.ErrExpiredURL):
+		return c.JSON(http.StatusForbidden, map[string]string{"message": err.Error()})
+	case errors.Is(err, usecase.ErrEbookTooLarge):
+		return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{"message": err.Error()})
+	case errors.Is(err, usecase.ErrUnsupportedEbook):
+		return c.JSON(http.StatusUnsupportedMediaType, map[string]string{"message": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, err)
+}
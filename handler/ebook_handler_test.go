@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.test/model"
+	"go.test/storage"
+	"go.test/usecase"
+	"go.test/usecase/mocks"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDownloadEbook(t *testing.T) {
+	e := echo.New()
+	ebookUsecase := new(mocks.EbookUsecase)
+	h := NewEbookHandler(ebookUsecase)
+	e.GET("/api/ebooks/:id/download", h.Download)
+
+	ebook := &model.Ebook{ID: 3, Format: model.EbookFormatPDF, Filename: "Dune – Herbert.pdf", Size: 10, Checksum: "abc", CreatedAt: time.Now()}
+	ebookUsecase.On("OpenDownload", uint(3), "1700000000", "good").
+		Return(ebook, readSeekCloser{strings.NewReader("%PDF-12345")}, nil).Twice()
+	ebookUsecase.On("OpenDownload", uint(3), "1700000000", "bad").
+		Return(nil, nil, storage.ErrExpiredURL).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/ebooks/3/download?expires=1700000000&signature=good", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/pdf", rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, `attachment; filename*=utf-8''Dune%20%E2%80%93%20Herbert.pdf`, rec.Header().Get(echo.HeaderContentDisposition))
+	assert.Equal(t, "bytes", rec.Header().Get("Accept-Ranges"))
+	assert.Equal(t, "%PDF-12345", rec.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/api/ebooks/3/download?expires=1700000000&signature=good", nil)
+	req.Header.Set("Range", "bytes=5-")
+	req.Header.Set("If-Range", `"abc"`)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusPartialContent, rec.Code)
+	assert.Equal(t, "12345", rec.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/api/ebooks/3/download?expires=1700000000&signature=bad", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	ebookUsecase.AssertExpectations(t)
+}
+
+func TestGetEbookDownloadWithoutLoan(t *testing.T) {
+	e := echo.New()
+	ebookUsecase := new(mocks.EbookUsecase)
+	h := NewEbookHandler(ebookUsecase)
+
+	ebookUsecase.On("GetDownload", uint(3), "reader", "user").Return(nil, usecase.ErrNoActiveLoan)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/ebooks/3/download-url", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("3")
+	c.Set("username", "reader")
+	c.Set("role", "user")
+
+	assert.NoError(t, h.GetDownload(c))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestAddEbookDraft(t *testing.T) {
+	e := echo.New()
+	ebookUsecase := new(mocks.EbookUsecase)
+	h := NewEbookHandler(ebookUsecase)
+	e.POST("/api/books/:id/ebooks", func(c echo.Context) error {
+		c.Set("username", "staff")
+		return h.AddEbook(c)
+	})
+
+	upload := &model.EbookUpload{
+		Ebook:   model.Ebook{ID: 4, BookID: 3, Format: model.EbookFormatEPUB, Filename: "dune.epub"},
+		Draft:   &model.Book{ID: 3, Title: "Dune", Author: "Frank Herbert"},
+		Changes: []model.FieldChange{{Field: "author", Old: "Herbert", New: "Frank Herbert"}},
+	}
+	ebookUsecase.On("AddEbook", uint(3), mock.Anything, "dune.epub", "staff").Return(upload, nil).Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/books/3/ebooks?filename=dune.epub", strings.NewReader("PK"))
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	var body map[string]interface{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "dune.epub", body["filename"])
+	assert.Equal(t, "Frank Herbert", body["draft"].(map[string]interface{})["author"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"field": "author", "old": "Herbert", "new": "Frank Herbert"}}, body["changes"])
+	assert.NotContains(t, body, "cover")
+
+	ebookUsecase.AssertExpectations(t)
+}
+
+func TestAddEbookSlowerThanReadTimeout(t *testing.T) {
+	e := echo.New()
+	ebookUsecase := new(mocks.EbookUsecase)
+	h := NewEbookHandler(ebookUsecase)
+	e.POST("/api/books/:id/ebooks", func(c echo.Context) error {
+		c.Set("username", "staff")
+		return h.AddEbook(c)
+	})
+
+	var received []byte
+	ebookUsecase.On("AddEbook", uint(3), mock.Anything, "dune.pdf", "staff").Run(func(args mock.Arguments) {
+		received, _ = io.ReadAll(args.Get(1).(io.Reader))
+	}).Return(&model.EbookUpload{Ebook: model.Ebook{ID: 4}}, nil).Once()
+
+	server := httptest.NewUnstartedServer(e)
+	server.Config.ReadTimeout = 50 * time.Millisecond
+	server.Start()
+	defer server.Close()
+
+	body, w := io.Pipe()
+	go func() {
+		for i := 0; i < 3; i++ {
+			io.WriteString(w, "%PDF-")
+			time.Sleep(100 * time.Millisecond)
+		}
+		w.Close()
+	}()
+	res, err := http.Post(server.URL+"/api/books/3/ebooks?filename=dune.pdf", "application/pdf", body)
+	if !assert.NoError(t, err) {
+		return
+	}
+	res.Body.Close()
+	assert.Equal(t, http.StatusCreated, res.StatusCode)
+	assert.Equal(t, "%PDF-%PDF-%PDF-", string(received))
+
+	ebookUsecase.AssertExpectations(t)
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.test/model"
+	"go.test/usecase"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ExportHandler struct {
+	ExportUsecase usecase.ExportUsecase
+}
+
+func NewExportHandler(exportUsecase usecase.ExportUsecase) *ExportHandler {
+	return &ExportHandler{exportUsecase}
+}
+
+// ExportBooks streams the catalog as a download. It is gzipped when the
+// client accepts that or asks for it with gzip=true. Large catalogs take
+// longer than the server's write timeout to send, so it does not apply.
+func (h *ExportHandler) ExportBooks(c echo.Context) error {
+	req := new(model.ExportRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, err)
+	}
+	clearDeadlines(c)
+	compress := req.Gzip || acceptsGzip(c.Request().Header.Get(echo.HeaderAcceptEncoding))
+	var gz *gzip.Writer
+	err := h.ExportUsecase.ExportBooks(req, func(format, contentType string) io.Writer {
+		header := c.Response().Header()
+		header.Set(echo.HeaderContentType, contentType)
+		header.Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="books.%s"`, exportExtension(format)))
+		header.Add(echo.HeaderVary, echo.HeaderAcceptEncoding)
+		if compress {
+			header.Set(echo.HeaderContentEncoding, "gzip")
+			c.Response().WriteHeader(http.StatusOK)
+			gz = gzip.NewWriter(c.Response())
+			return gz
+		}
+		c.Response().WriteHeader(http.StatusOK)
+		return c.Response()
+	})
+	if gz != nil {
+		if closeErr := gz.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil && !c.Response().Committed {
+		if errors.Is(err, usecase.ErrUnknownExportFormat) || errors.Is(err, usecase.ErrInvalidSince) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, err)
+	}
+	return err
+}
+
+// clearDeadlines lifts the server's read and write timeouts for a request
+// whose body or response can be too large to move within them. Writers that
+// cannot set deadlines, such as test recorders, are left as they are.
+func clearDeadlines(c echo.Context) {
+	rc := http.NewResponseController(c.Response().Writer)
+	rc.SetReadDeadline(time.Time{})
+	rc.SetWriteDeadline(time.Time{})
+}
+
+// exportExtension returns the file name extension of an export format.
+func exportExtension(format string) string {
+	switch format {
+	case model.ImportFormatMARC:
+		return "mrc"
+	case model.ImportFormatMARCXML:
+		return "xml"
+	}
+	return format
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip.
+func acceptsGzip(acceptEncoding string) bool {
+	for _, coding := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(coding, ";")
+		if strings.TrimSpace(name) != "gzip" {
+			continue
+		}
+		q, ok := strings.CutPrefix(strings.TrimSpace(params), "q=")
+		if !ok {
+			return true
+		}
+		weight, err := strconv.ParseFloat(q, 64)
+		return err == nil && weight > 0
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.test/model"
+	"go.test/usecase"
+	"go.test/usecase/mocks"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func writeExport(format, contentType, body string) func(mock.Arguments) {
+	return func(args mock.Arguments) {
+		open := args.Get(1).(func(format, contentType string) io.Writer)
+		io.WriteString(open(format, contentType), body)
+	}
+}
+
+func TestExportBooks(t *testing.T) {
+	e := echo.New()
+	exportUsecase := new(mocks.ExportUsecase)
+	h := NewExportHandler(exportUsecase)
+
+	want := &model.ExportRequest{BookFilter: model.BookFilter{Sort: "title"}, Format: model.ImportFormatNDJSON, Since: "2024-01-31T00:00:00Z"}
+	exportUsecase.On("ExportBooks", want, mock.Anything).
+		Run(writeExport("ndjson", "application/x-ndjson", `{"id":1}`+"\n")).Return(nil).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/books/export?format=ndjson&sort=title&since=2024-01-31T00:00:00Z", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	assert.NoError(t, h.ExportBooks(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/x-ndjson", rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, `attachment; filename="books.ndjson"`, rec.Header().Get(echo.HeaderContentDisposition))
+	assert.Empty(t, rec.Header().Get(echo.HeaderContentEncoding))
+	assert.Equal(t, `{"id":1}`+"\n", rec.Body.String())
+
+	exportUsecase.AssertExpectations(t)
+}
+
+func TestExportBooksGzipped(t *testing.T) {
+	e := echo.New()
+	exportUsecase := new(mocks.ExportUsecase)
+	h := NewExportHandler(exportUsecase)
+
+	exportUsecase.On("ExportBooks", mock.Anything, mock.Anything).
+		Run(writeExport("csv", "text/csv; charset=utf-8", "id,title\n1,Dune\n")).Return(nil).Twice()
+
+	for _, tc := range []struct{ query, acceptEncoding string }{
+		{"gzip=true", ""},
+		{"", "deflate, gzip;q=0.5"},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/api/books/export?"+tc.query, nil)
+		req.Header.Set(echo.HeaderAcceptEncoding, tc.acceptEncoding)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		assert.NoError(t, h.ExportBooks(c))
+		assert.Equal(t, "gzip", rec.Header().Get(echo.HeaderContentEncoding))
+		reader, err := gzip.NewReader(rec.Body)
+		if assert.NoError(t, err) {
+			body, _ := io.ReadAll(reader)
+			assert.Equal(t, "id,title\n1,Dune\n", string(body))
+		}
+	}
+
+	exportUsecase.AssertExpectations(t)
+}
+
+func TestExportBooksSlowerThanWriteTimeout(t *testing.T) {
+	e := echo.New()
+	exportUsecase := new(mocks.ExportUsecase)
+	h := NewExportHandler(exportUsecase)
+	e.GET("/api/books/export", h.ExportBooks)
+
+	exportUsecase.On("ExportBooks", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		w := args.Get(1).(func(format, contentType string) io.Writer)("ndjson", "application/x-ndjson")
+		for i := 0; i < 3; i++ {
+			io.WriteString(w, `{"id":1}`+"\n")
+			w.(http.Flusher).Flush()
+			time.Sleep(100 * time.Millisecond)
+		}
+	}).Return(nil).Once()
+
+	server := httptest.NewUnstartedServer(e)
+	server.Config.WriteTimeout = 50 * time.Millisecond
+	server.Start()
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/api/books/export?format=ndjson", nil)
+	req.Header.Set(echo.HeaderAcceptEncoding, "identity")
+	res, err := http.DefaultClient.Do(req)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Repeat(`{"id":1}`+"\n", 3), string(body))
+
+	exportUsecase.AssertExpectations(t)
+}
+
+func TestExportBooksWithUnknownFormat(t *testing.T) {
+	e := echo.New()
+	exportUsecase := new(mocks.ExportUsecase)
+	h := NewExportHandler(exportUsecase)
+
+	exportUsecase.On("ExportBooks", &model.ExportRequest{Format: "xml"}, mock.Anything).
+		Return(usecase.ErrUnknownExportFormat).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/books/export?format=xml", nil)
+	req.Header.Set(echo.HeaderAcceptEncoding, "gzip")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	assert.NoError(t, h.ExportBooks(c))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Empty(t, rec.Header().Get(echo.HeaderContentEncoding))
+
+	exportUsecase.AssertExpectations(t)
+}
+
+func TestAcceptsGzip(t *testing.T) {
+	assert.True(t, acceptsGzip("gzip"))
+	assert.True(t, acceptsGzip("br, gzip;q=0.8"))
+	assert.False(t, acceptsGzip(""))
+	assert.False(t, acceptsGzip("deflate"))
+	assert.False(t, acceptsGzip("gzip;q=0"))
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go.test/model"
+	"go.test/usecase"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type FineHandler struct {
+	FineUsecase usecase.FineUsecase
+}
+
+func NewFineHandler(fineUsecase usecase.FineUsecase) *FineHandler {
+	return &FineHandler{fineUsecase}
+}
+
+func (h *FineHandler) GetMyAccount(c echo.Context) error {
+	account, err := h.FineUsecase.GetMyAccount(c.Get("username").(string))
+	if err != nil {
+		return fineError(c, err)
+	}
+	return c.JSON(http.StatusOK, account)
+}
+
+func (h *FineHandler) GetUserAccount(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	account, err := h.FineUsecase.GetAccount(uint(id))
+	if err != nil {
+		return fineError(c, err)
+	}
+	return c.JSON(http.StatusOK, account)
+}
+
+func (h *FineHandler) Pay(c echo.Context) error {
+	req := new(model.PaymentRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, err)
+	}
+	entry, err := h.FineUsecase.Pay(c.Get("username").(string), req)
+	if err != nil {
+		return fineError(c, err)
+	}
+	return c.JSON(http.StatusCreated, entry)
+}
+
+func (h *FineHandler) RecordPayment(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	req := new(model.PaymentRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, err)
+	}
+	entry, err := h.FineUsecase.RecordPayment(uint(id), req, c.Get("username").(string))
+	if err != nil {
+		return fineError(c, err)
+	}
+	return c.JSON(http.StatusCreated, entry)
+}
+
+func (h *FineHandler) Waive(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	req := new(model.WaiverRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, err)
+	}
+	entry, err := h.FineUsecase.Waive(uint(id), req.Reason, c.Get("username").(string))
+	if err != nil {
+		return fineError(c, err)
+	}
+	return c.JSON(http.StatusCreated, entry)
+}
+
+func fineError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Not found"})
+	case errors.Is(err, usecase.ErrInvalidAmount), errors.Is(err, usecase.ErrReasonRequired):
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	case errors.Is(err, usecase.ErrPaymentDeclined):
+		return c.JSON(http.StatusPaymentRequired, map[string]string{"message": err.Error()})
+	case errors.Is(err, usecase.ErrChargeWaived), errors.Is(err, usecase.ErrNothingOwed):
+		return c.JSON(http.StatusConflict, map[string]string{"message": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, err)
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.test/model"
+	"go.test/usecase"
+	"go.test/usecase/mocks"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPayFines(t *testing.T) {
+	e := echo.New()
+	fineUsecase := new(mocks.FineUsecase)
+	h := NewFineHandler(fineUsecase)
+
+	payment := &model.PaymentRequest{Amount: 150, Token: "tok_visa"}
+	body, _ := json.Marshal(payment)
+	entry := &model.FineEntry{ID: 9, UserID: 5, Type: model.FineEntryPayment, Amount: 150, Reference: "local-5-1"}
+
+	fineUsecase.On("Pay", "ahmad", payment).Return(entry, nil).Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/fines/me/payments", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("username", "ahmad")
+
+	assert.NoError(t, h.Pay(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	fineUsecase.AssertExpectations(t)
+}
+
+func TestPayFinesDeclined(t *testing.T) {
+	e := echo.New()
+	fineUsecase := new(mocks.FineUsecase)
+	h := NewFineHandler(fineUsecase)
+
+	payment := &model.PaymentRequest{Amount: 150, Token: "declined"}
+	body, _ := json.Marshal(payment)
+
+	fineUsecase.On("Pay", "ahmad", payment).Return(nil, usecase.ErrPaymentDeclined).Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/fines/me/payments", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("username", "ahmad")
+
+	assert.NoError(t, h.Pay(c))
+	assert.Equal(t, http.StatusPaymentRequired, rec.Code)
+
+	fineUsecase.AssertExpectations(t)
+}
+
+func TestWaiveFineWithoutReason(t *testing.T) {
+	e := echo.New()
+	fineUsecase := new(mocks.FineUsecase)
+	h := NewFineHandler(fineUsecase)
+
+	fineUsecase.On("Waive", uint(3), "", "staff").Return(nil, usecase.ErrReasonRequired).Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/fines/3/waive", bytes.NewReader([]byte(`{}`)))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("3")
+	c.Set("username", "staff")
+
+	assert.NoError(t, h.Waive(c))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	fineUsecase.AssertExpectations(t)
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go.test/usecase"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type HoldHandler struct {
+	HoldUsecase usecase.HoldUsecase
+}
+
+func NewHoldHandler(holdUsecase usecase.HoldUsecase) *HoldHandler {
+	return &HoldHandler{holdUsecase}
+}
+
+func (h *HoldHandler) PlaceHold(c echo.Context) error {
+	bookID, _ := strconv.Atoi(c.Param("id"))
+	hold, err := h.HoldUsecase.PlaceHold(uint(bookID), c.Get("username").(string))
+	if err != nil {
+		return holdError(c, err)
+	}
+	return c.JSON(http.StatusCreated, hold)
+}
+
+func (h *HoldHandler) GetQueue(c echo.Context) error {
+	bookID, _ := strconv.Atoi(c.Param("id"))
+	holds, err := h.HoldUsecase.GetQueue(uint(bookID))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, err)
+	}
+	return c.JSON(http.StatusOK, holds)
+}
+
+func (h *HoldHandler) GetMyHolds(c echo.Context) error {
+	holds, err := h.HoldUsecase.GetMyHolds(c.Get("username").(string))
+	if err != nil {
+		return holdError(c, err)
+	}
+	return c.JSON(http.StatusOK, holds)
+}
+
+func (h *HoldHandler) CancelHold(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	if err := h.HoldUsecase.CancelHold(uint(id), c.Get("username").(string), c.Get("role").(string)); err != nil {
+		return holdError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func holdError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Not found"})
+	case errors.Is(err, usecase.ErrForbidden):
+		return c.JSON(http.StatusForbidden, map[string]string{"message": err.Error()})
+	case errors.Is(err, usecase.ErrHoldExists), errors.Is(err, usecase.ErrCopiesAvailable), errors.Is(err, usecase.ErrHoldClosed):
+		return c.JSON(http.StatusConflict, map[string]string{"message": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, err)
+}
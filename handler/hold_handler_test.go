@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.test/model"
+	"go.test/usecase"
+	"go.test/usecase/mocks"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlaceHold(t *testing.T) {
+	e := echo.New()
+	holdUsecase := new(mocks.HoldUsecase)
+	h := NewHoldHandler(holdUsecase)
+
+	hold := &model.Hold{ID: 4, BookID: 2, UserID: 5, Status: model.HoldStatusWaiting, Position: 3}
+	holdUsecase.On("PlaceHold", uint(2), "ahmad").Return(hold, nil).Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/books/2/holds", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("2")
+	c.Set("username", "ahmad")
+
+	assert.NoError(t, h.PlaceHold(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	var created model.Hold
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, 3, created.Position)
+
+	holdUsecase.AssertExpectations(t)
+}
+
+func TestPlaceHoldWhileAvailable(t *testing.T) {
+	e := echo.New()
+	holdUsecase := new(mocks.HoldUsecase)
+	h := NewHoldHandler(holdUsecase)
+
+	holdUsecase.On("PlaceHold", uint(2), "ahmad").Return(nil, usecase.ErrCopiesAvailable).Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/books/2/holds", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("2")
+	c.Set("username", "ahmad")
+
+	assert.NoError(t, h.PlaceHold(c))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	holdUsecase.AssertExpectations(t)
+}
+
+func TestCancelHold(t *testing.T) {
+	e := echo.New()
+	holdUsecase := new(mocks.HoldUsecase)
+	h := NewHoldHandler(holdUsecase)
+
+	holdUsecase.On("CancelHold", uint(4), "ahmad", "user").Return(nil).Once()
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/holds/4", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("4")
+	c.Set("username", "ahmad")
+	c.Set("role", "user")
+
+	assert.NoError(t, h.CancelHold(c))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	holdUsecase.AssertExpectations(t)
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"go.test/model"
+	"go.test/usecase"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type ImportHandler struct {
+	ImportUsecase usecase.ImportUsecase
+}
+
+func NewImportHandler(importUsecase usecase.ImportUsecase) *ImportHandler {
+	return &ImportHandler{importUsecase}
+}
+
+// ImportBooks imports the books in the request body, or in the "file" part
+// of a multipart upload. Imports that run in the background are answered
+// with 202 and the location of their progress. Large uploads take longer
+// than the server's timeouts allow, so they do not apply.
+func (h *ImportHandler) ImportBooks(c echo.Context) error {
+	clearDeadlines(c)
+	req := new(model.ImportRequest)
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, req); err != nil {
+		return c.JSON(http.StatusBadRequest, err)
+	}
+	data, size, filename, err := upload(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	}
+	defer data.Close()
+	if req.Format == "" {
+		req.Format = importFormat(c.Request().Header.Get(echo.HeaderContentType), filename)
+	}
+	job, err := h.ImportUsecase.ImportBooks(req, data, size, c.Get("username").(string))
+	if err != nil {
+		return importError(c, err)
+	}
+	if job.FinishedAt == nil {
+		c.Response().Header().Set(echo.HeaderLocation, fmt.Sprintf("/api/books/imports/%d", job.ID))
+		return c.JSON(http.StatusAccepted, job)
+	}
+	return c.JSON(http.StatusOK, job)
+}
+
+func (h *ImportHandler) GetJob(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	job, err := h.ImportUsecase.GetJob(uint(id))
+	if err != nil {
+		return importError(c, err)
+	}
+	return c.JSON(http.StatusOK, job)
+}
+
+func (h *ImportHandler) GetRows(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	rows, err := h.ImportUsecase.GetRows(uint(id), c.QueryParam("action"))
+	if err != nil {
+		return importError(c, err)
+	}
+	return c.JSON(http.StatusOK, rows)
+}
+
+// upload returns the file of a multipart upload, or else the request body,
+// with its size and file name where they are known.
+func upload(c echo.Context) (io.ReadCloser, int64, string, error) {
+	if strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+		header, err := c.FormFile("file")
+		if err != nil {
+			return nil, 0, "", err
+		}
+		file, err := header.Open()
+		return file, header.Size, header.Filename, err
+	}
+	return c.Request().Body, c.Request().ContentLength, "", nil
+}
+
+// importFormat guesses the format of an upload from its content type or
+// file name.
+func importFormat(contentType, filename string) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	extension := strings.ToLower(filepath.Ext(filename))
+	switch {
+	case mediaType == "text/csv" || extension == ".csv":
+		return model.ImportFormatCSV
+	case mediaType == "application/x-ndjson" || mediaType == "application/ndjson" || extension == ".ndjson" || extension == ".jsonl":
+		return model.ImportFormatNDJSON
+	case mediaType == echo.MIMEApplicationJSON || extension == ".json":
+		return model.ImportFormatJSON
+	case mediaType == "application/marc" || extension == ".mrc" || extension == ".marc":
+		return model.ImportFormatMARC
+	case mediaType == "application/marcxml+xml" || extension == ".xml":
+		return model.ImportFormatMARCXML
+	}
+	return ""
+}
+
+func importError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Not found"})
+	case errors.Is(err, usecase.ErrUnknownImportFormat), errors.Is(err, usecase.ErrUnknownImportMode),
+		errors.Is(err, usecase.ErrInvalidMapping):
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, err)
+}
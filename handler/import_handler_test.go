@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.test/model"
+	"go.test/usecase"
+	"go.test/usecase/mocks"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestImportBooks(t *testing.T) {
+	e := echo.New()
+	importUsecase := new(mocks.ImportUsecase)
+	h := NewImportHandler(importUsecase)
+
+	body := "title,author\nDune,Frank Herbert\n"
+	finished := time.Now()
+	importUsecase.On("ImportBooks", &model.ImportRequest{Format: model.ImportFormatCSV, Mapping: "Writer:author", DryRun: true}, mock.Anything, int64(len(body)), "zai").
+		Return(&model.ImportJob{ID: 1, Status: model.ImportStatusCompleted, FinishedAt: &finished}, nil).Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/books/import?dry_run=true&mapping=Writer:author", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("username", "zai")
+
+	assert.NoError(t, h.ImportBooks(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	importUsecase.AssertExpectations(t)
+}
+
+func TestImportBooksInBackground(t *testing.T) {
+	e := echo.New()
+	importUsecase := new(mocks.ImportUsecase)
+	h := NewImportHandler(importUsecase)
+
+	importUsecase.On("ImportBooks", &model.ImportRequest{Format: model.ImportFormatNDJSON, Mode: model.ImportModeAtomic}, mock.Anything, mock.Anything, "zai").
+		Return(&model.ImportJob{ID: 7, Status: model.ImportStatusQueued}, nil).Once()
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, _ := form.CreateFormFile("file", "catalog.ndjson")
+	part.Write([]byte(`{"title":"Dune"}` + "\n"))
+	form.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/books/import?mode=atomic", &body)
+	req.Header.Set(echo.HeaderContentType, form.FormDataContentType())
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("username", "zai")
+
+	assert.NoError(t, h.ImportBooks(c))
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Equal(t, "/api/books/imports/7", rec.Header().Get(echo.HeaderLocation))
+
+	importUsecase.AssertExpectations(t)
+}
+
+func TestImportBooksSlowerThanReadTimeout(t *testing.T) {
+	e := echo.New()
+	importUsecase := new(mocks.ImportUsecase)
+	h := NewImportHandler(importUsecase)
+	e.POST("/api/books/import", func(c echo.Context) error {
+		c.Set("username", "zai")
+		return h.ImportBooks(c)
+	})
+
+	var received []byte
+	finished := time.Now()
+	importUsecase.On("ImportBooks", mock.Anything, mock.Anything, mock.Anything, "zai").Run(func(args mock.Arguments) {
+		received, _ = io.ReadAll(args.Get(1).(io.Reader))
+	}).Return(&model.ImportJob{ID: 1, Status: model.ImportStatusCompleted, FinishedAt: &finished}, nil).Once()
+
+	server := httptest.NewUnstartedServer(e)
+	server.Config.ReadTimeout = 50 * time.Millisecond
+	server.Start()
+	defer server.Close()
+
+	body, w := io.Pipe()
+	go func() {
+		io.WriteString(w, "title\n")
+		for i := 0; i < 3; i++ {
+			time.Sleep(100 * time.Millisecond)
+			io.WriteString(w, "Dune\n")
+		}
+		w.Close()
+	}()
+	res, err := http.Post(server.URL+"/api/books/import?format=csv", "text/csv", body)
+	if !assert.NoError(t, err) {
+		return
+	}
+	res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "title\nDune\nDune\nDune\n", string(received))
+
+	importUsecase.AssertExpectations(t)
+}
+
+func TestImportBooksWithUnknownFormat(t *testing.T) {
+	e := echo.New()
+	importUsecase := new(mocks.ImportUsecase)
+	h := NewImportHandler(importUsecase)
+
+	importUsecase.On("ImportBooks", &model.ImportRequest{}, mock.Anything, mock.Anything, "zai").
+		Return(nil, usecase.ErrUnknownImportFormat).Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/books/import", strings.NewReader("<books/>"))
+	req.Header.Set(echo.HeaderContentType, "application/xml")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("username", "zai")
+
+	assert.NoError(t, h.ImportBooks(c))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	importUsecase.AssertExpectations(t)
+}
+
+func TestImportFormat(t *testing.T) {
+	assert.Equal(t, model.ImportFormatCSV, importFormat("text/csv; charset=utf-8", ""))
+	assert.Equal(t, model.ImportFormatNDJSON, importFormat("", "catalog.jsonl"))
+	assert.Equal(t, model.ImportFormatMARC, importFormat("application/octet-stream", "catalog.mrc"))
+	assert.Equal(t, model.ImportFormatMARCXML, importFormat("application/marcxml+xml", ""))
+	assert.Equal(t, model.ImportFormatMARCXML, importFormat("", "catalog.xml"))
+	assert.Equal(t, "", importFormat("application/octet-stream", "catalog.bin"))
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go.test/model"
+	"go.test/usecase"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type ListHandler struct {
+	ListUsecase usecase.ListUsecase
+}
+
+func NewListHandler(listUsecase usecase.ListUsecase) *ListHandler {
+	return &ListHandler{listUsecase}
+}
+
+func (h *ListHandler) GetMyLists(c echo.Context) error {
+	lists, err := h.ListUsecase.GetMyLists(c.Get("username").(string))
+	if err != nil {
+		return listError(c, err)
+	}
+	for i := range lists {
+		setShareURL(c, &lists[i])
+	}
+	return c.JSON(http.StatusOK, lists)
+}
+
+func (h *ListHandler) GetList(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	list, err := h.ListUsecase.GetList(uint(id), c.Get("username").(string))
+	if err != nil {
+		return listError(c, err)
+	}
+	setShareURL(c, list)
+	return c.JSON(http.StatusOK, list)
+}
+
+// GetSharedList serves a public list to anyone with its share link, without
+// authentication.
+func (h *ListHandler) GetSharedList(c echo.Context) error {
+	list, err := h.ListUsecase.GetSharedList(c.Param("token"))
+	if err != nil {
+		return listError(c, err)
+	}
+	setShareURL(c, list)
+	return c.JSON(http.StatusOK, list)
+}
+
+func (h *ListHandler) CreateList(c echo.Context) error {
+	list := new(model.ReadingList)
+	if err := c.Bind(list); err != nil {
+		return c.JSON(http.StatusBadRequest, err)
+	}
+	if err := h.ListUsecase.CreateList(list, c.Get("username").(string)); err != nil {
+		return listError(c, err)
+	}
+	setShareURL(c, list)
+	return c.JSON(http.StatusCreated, list)
+}
+
+func (h *ListHandler) UpdateList(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	list := new(model.ReadingList)
+	if err := c.Bind(list); err != nil {
+		return c.JSON(http.StatusBadRequest, err)
+	}
+	list.ID = uint(id)
+	if err := h.ListUsecase.UpdateList(list, c.Get("username").(string)); err != nil {
+		return listError(c, err)
+	}
+	setShareURL(c, list)
+	return c.JSON(http.StatusOK, list)
+}
+
+func (h *ListHandler) DeleteList(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	if err := h.ListUsecase.DeleteList(uint(id), c.Get("username").(string)); err != nil {
+		return listError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *ListHandler) AddBook(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	req := new(model.ListEntryRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, err)
+	}
+	entry, err := h.ListUsecase.AddBook(uint(id), req, c.Get("username").(string))
+	if err != nil {
+		return listError(c, err)
+	}
+	return c.JSON(http.StatusCreated, entry)
+}
+
+func (h *ListHandler) UpdateEntry(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	bookID, _ := strconv.Atoi(c.Param("book_id"))
+	req := new(model.ListEntryRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, err)
+	}
+	entry, err := h.ListUsecase.UpdateEntry(uint(id), uint(bookID), req, c.Get("username").(string))
+	if err != nil {
+		return listError(c, err)
+	}
+	return c.JSON(http.StatusOK, entry)
+}
+
+func (h *ListHandler) RemoveBook(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	bookID, _ := strconv.Atoi(c.Param("book_id"))
+	if err := h.ListUsecase.RemoveBook(uint(id), uint(bookID), c.Get("username").(string)); err != nil {
+		return listError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// setShareURL fills in the link a public list can be shared with.
+func setShareURL(c echo.Context, list *model.ReadingList) {
+	if list.Public && list.ShareToken != nil {
+		list.ShareURL = c.Scheme() + "://" + c.Request().Host + "/api/shared/lists/" + *list.ShareToken
+	}
+}
+
+func listError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Not found"})
+	case errors.Is(err, usecase.ErrNotOnList):
+		return c.JSON(http.StatusNotFound, map[string]string{"message": err.Error()})
+	case errors.Is(err, usecase.ErrForbidden):
+		return c.JSON(http.StatusForbidden, map[string]string{"message": err.Error()})
+	case errors.Is(err, usecase.ErrListNameRequired):
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	case errors.Is(err, usecase.ErrShelfLocked), errors.Is(err, usecase.ErrAlreadyOnList):
+		return c.JSON(http.StatusConflict, map[string]string{"message": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, err)
+}
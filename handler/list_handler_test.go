@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.test/model"
+	"go.test/usecase"
+	"go.test/usecase/mocks"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestAddBookToList(t *testing.T) {
+	e := echo.New()
+	listUsecase := new(mocks.ListUsecase)
+	h := NewListHandler(listUsecase)
+
+	body, _ := json.Marshal(model.ListEntryRequest{BookID: 2})
+
+	listUsecase.On("AddBook", uint(5), mock.MatchedBy(func(r *model.ListEntryRequest) bool {
+		return r.BookID == 2
+	}), "ahmad").Return(&model.ListEntry{ListID: 5, BookID: 2, Position: 1}, nil).Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/lists/5/books", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("5")
+	c.Set("username", "ahmad")
+
+	assert.NoError(t, h.AddBook(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	listUsecase.AssertExpectations(t)
+}
+
+func TestAddBookTwice(t *testing.T) {
+	e := echo.New()
+	listUsecase := new(mocks.ListUsecase)
+	h := NewListHandler(listUsecase)
+
+	body, _ := json.Marshal(model.ListEntryRequest{BookID: 2})
+
+	listUsecase.On("AddBook", uint(5), mock.Anything, "ahmad").Return(nil, usecase.ErrAlreadyOnList).Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/lists/5/books", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("5")
+	c.Set("username", "ahmad")
+
+	assert.NoError(t, h.AddBook(c))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	listUsecase.AssertExpectations(t)
+}
+
+func TestGetPrivateListOfAnotherUser(t *testing.T) {
+	e := echo.New()
+	listUsecase := new(mocks.ListUsecase)
+	h := NewListHandler(listUsecase)
+
+	listUsecase.On("GetList", uint(5), "ahmad").Return(nil, gorm.ErrRecordNotFound).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/lists/5", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("5")
+	c.Set("username", "ahmad")
+
+	assert.NoError(t, h.GetList(c))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	listUsecase.AssertExpectations(t)
+}
+
+func TestDeleteShelf(t *testing.T) {
+	e := echo.New()
+	listUsecase := new(mocks.ListUsecase)
+	h := NewListHandler(listUsecase)
+
+	listUsecase.On("DeleteList", uint(1), "ahmad").Return(usecase.ErrShelfLocked).Once()
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/lists/1", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	c.Set("username", "ahmad")
+
+	assert.NoError(t, h.DeleteList(c))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	listUsecase.AssertExpectations(t)
+}
+
+func TestGetSharedList(t *testing.T) {
+	e := echo.New()
+	listUsecase := new(mocks.ListUsecase)
+	h := NewListHandler(listUsecase)
+
+	token := "abc123"
+	listUsecase.On("GetSharedList", token).Return(&model.ReadingList{ID: 7, Name: "Summer", Public: true, ShareToken: &token}, nil).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/shared/lists/abc123", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("token")
+	c.SetParamValues(token)
+
+	assert.NoError(t, h.GetSharedList(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var list model.ReadingList
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	assert.Equal(t, "http://example.com/api/shared/lists/abc123", list.ShareURL)
+
+	listUsecase.AssertExpectations(t)
+}
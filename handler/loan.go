@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go.test/model"
+	"go.test/usecase"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type LoanHandler struct {
+	LoanUsecase usecase.LoanUsecase
+}
+
+func NewLoanHandler(loanUsecase usecase.LoanUsecase) *LoanHandler {
+	return &LoanHandler{loanUsecase}
+}
+
+func (h *LoanHandler) Checkout(c echo.Context) error {
+	req := new(model.CheckoutRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, err)
+	}
+	loan, err := h.LoanUsecase.Checkout(req, c.Get("username").(string))
+	if err != nil {
+		return loanError(c, err)
+	}
+	return c.JSON(http.StatusCreated, loan)
+}
+
+func (h *LoanHandler) Return(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	loan, err := h.LoanUsecase.Return(uint(id), c.Get("username").(string))
+	if err != nil {
+		return loanError(c, err)
+	}
+	return c.JSON(http.StatusOK, loan)
+}
+
+func (h *LoanHandler) Renew(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	req := new(model.RenewRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, err)
+	}
+	loan, err := h.LoanUsecase.Renew(uint(id), req, c.Get("username").(string), c.Get("role").(string))
+	if err != nil {
+		return loanError(c, err)
+	}
+	return c.JSON(http.StatusOK, loan)
+}
+
+func (h *LoanHandler) GetLoan(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	loan, err := h.LoanUsecase.GetLoan(uint(id), c.Get("username").(string), c.Get("role").(string))
+	if err != nil {
+		return loanError(c, err)
+	}
+	return c.JSON(http.StatusOK, loan)
+}
+
+func (h *LoanHandler) GetMyLoans(c echo.Context) error {
+	activeOnly := c.QueryParam("active") == "true"
+	loans, err := h.LoanUsecase.GetMyLoans(c.Get("username").(string), activeOnly)
+	if err != nil {
+		return loanError(c, err)
+	}
+	return c.JSON(http.StatusOK, loans)
+}
+
+func (h *LoanHandler) GetUserLoans(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	loans, err := h.LoanUsecase.GetLoansByUser(uint(id))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, err)
+	}
+	return c.JSON(http.StatusOK, loans)
+}
+
+func (h *LoanHandler) GetCopyLoans(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	loans, err := h.LoanUsecase.GetLoansByCopy(uint(id))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, err)
+	}
+	return c.JSON(http.StatusOK, loans)
+}
+
+func loanError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Not found"})
+	case errors.Is(err, usecase.ErrForbidden):
+		return c.JSON(http.StatusForbidden, map[string]string{"message": err.Error()})
+	case errors.Is(err, usecase.ErrCopyUnavailable), errors.Is(err, usecase.ErrLoanReturned), errors.Is(err, usecase.ErrLoanLimitReached),
+		errors.Is(err, usecase.ErrLoanConflict), errors.Is(err, usecase.ErrRenewalLimit), errors.Is(err, usecase.ErrRenewalOnHold),
+		errors.Is(err, usecase.ErrRenewalOverdue), errors.Is(err, usecase.ErrFinesOutstanding):
+		return c.JSON(http.StatusConflict, map[string]string{"message": err.Error()})
+	case errors.Is(err, usecase.ErrInvalidDueDate), errors.Is(err, usecase.ErrReasonRequired):
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, err)
+}
@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.test/model"
+	"go.test/usecase"
+	"go.test/usecase/mocks"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckout(t *testing.T) {
+	e := echo.New()
+	loanUsecase := new(mocks.LoanUsecase)
+	h := NewLoanHandler(loanUsecase)
+
+	req := &model.CheckoutRequest{CopyID: 3, UserID: 5}
+	body, _ := json.Marshal(req)
+	loan := &model.Loan{ID: 1, CopyID: 3, UserID: 5, DueAt: time.Now().Add(14 * 24 * time.Hour)}
+
+	loanUsecase.On("Checkout", req, "staff").Return(loan, nil).Once()
+
+	httpReq := httptest.NewRequest(http.MethodPost, "/api/loans", bytes.NewReader(body))
+	httpReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httpReq, rec)
+	c.Set("username", "staff")
+	c.Set("role", "supervisor")
+
+	assert.NoError(t, h.Checkout(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	var created model.Loan
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, uint(3), created.CopyID)
+
+	loanUsecase.AssertExpectations(t)
+}
+
+func TestCheckoutUnavailableCopy(t *testing.T) {
+	e := echo.New()
+	loanUsecase := new(mocks.LoanUsecase)
+	h := NewLoanHandler(loanUsecase)
+
+	req := &model.CheckoutRequest{Barcode: "B-0001", UserID: 5}
+	body, _ := json.Marshal(req)
+
+	loanUsecase.On("Checkout", req, "staff").Return(nil, usecase.ErrCopyUnavailable).Once()
+
+	httpReq := httptest.NewRequest(http.MethodPost, "/api/loans", bytes.NewReader(body))
+	httpReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httpReq, rec)
+	c.Set("username", "staff")
+	c.Set("role", "supervisor")
+
+	assert.NoError(t, h.Checkout(c))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	loanUsecase.AssertExpectations(t)
+}
+
+func TestGetLoanOfAnotherUser(t *testing.T) {
+	e := echo.New()
+	loanUsecase := new(mocks.LoanUsecase)
+	h := NewLoanHandler(loanUsecase)
+
+	loanUsecase.On("GetLoan", uint(1), "ahmad", "user").Return(nil, usecase.ErrForbidden).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/loans/1", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	c.Set("username", "ahmad")
+	c.Set("role", "user")
+
+	assert.NoError(t, h.GetLoan(c))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	loanUsecase.AssertExpectations(t)
+}
+
+func TestRenewLoan(t *testing.T) {
+	e := echo.New()
+	loanUsecase := new(mocks.LoanUsecase)
+	h := NewLoanHandler(loanUsecase)
+
+	renewed := &model.Loan{ID: 1, CopyID: 3, UserID: 5, RenewalCount: 1, DueAt: time.Now().Add(14 * 24 * time.Hour)}
+	loanUsecase.On("Renew", uint(1), &model.RenewRequest{}, "ahmad", "user").Return(renewed, nil).Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/loans/1/renew", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	c.Set("username", "ahmad")
+	c.Set("role", "user")
+
+	assert.NoError(t, h.Renew(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var got model.Loan
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, 1, got.RenewalCount)
+
+	loanUsecase.AssertExpectations(t)
+}
+
+func TestRenewLoanWithWaitingHold(t *testing.T) {
+	e := echo.New()
+	loanUsecase := new(mocks.LoanUsecase)
+	h := NewLoanHandler(loanUsecase)
+
+	loanUsecase.On("Renew", uint(1), &model.RenewRequest{}, "ahmad", "user").Return(nil, usecase.ErrRenewalOnHold).Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/loans/1/renew", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	c.Set("username", "ahmad")
+	c.Set("role", "user")
+
+	assert.NoError(t, h.Renew(c))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	loanUsecase.AssertExpectations(t)
+}
+
+func TestRenewLoanRefused(t *testing.T) {
+	for _, err := range []error{usecase.ErrRenewalOverdue, usecase.ErrFinesOutstanding} {
+		e := echo.New()
+		loanUsecase := new(mocks.LoanUsecase)
+		h := NewLoanHandler(loanUsecase)
+
+		loanUsecase.On("Renew", uint(1), &model.RenewRequest{}, "ahmad", "user").Return(nil, err).Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/api/loans/1/renew", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+		c.Set("username", "ahmad")
+		c.Set("role", "user")
+
+		assert.NoError(t, h.Renew(c))
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Contains(t, rec.Body.String(), err.Error())
+
+		loanUsecase.AssertExpectations(t)
+	}
+}
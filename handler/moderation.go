@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go.test/model"
+	"go.test/usecase"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type ModerationHandler struct {
+	ModerationUsecase usecase.ModerationUsecase
+}
+
+func NewModerationHandler(moderationUsecase usecase.ModerationUsecase) *ModerationHandler {
+	return &ModerationHandler{moderationUsecase}
+}
+
+func (h *ModerationHandler) Report(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	req := new(model.ReviewReport)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, err)
+	}
+	report, err := h.ModerationUsecase.Report(uint(id), c.Get("username").(string), req.Reason)
+	if err != nil {
+		return moderationError(c, err)
+	}
+	return c.JSON(http.StatusCreated, report)
+}
+
+func (h *ModerationHandler) GetQueue(c echo.Context) error {
+	items, err := h.ModerationUsecase.GetQueue()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, err)
+	}
+	return c.JSON(http.StatusOK, items)
+}
+
+func (h *ModerationHandler) Decide(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	decision := new(model.ModerationDecision)
+	if err := c.Bind(decision); err != nil {
+		return c.JSON(http.StatusBadRequest, err)
+	}
+	return h.decide(c, uint(id), decision)
+}
+
+func (h *ModerationHandler) HideReview(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	return h.decide(c, uint(id), &model.ModerationDecision{Action: model.ModerationActionHide, Reason: c.QueryParam("reason")})
+}
+
+func (h *ModerationHandler) UnhideReview(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	return h.decide(c, uint(id), &model.ModerationDecision{Action: model.ModerationActionApprove, Reason: c.QueryParam("reason")})
+}
+
+func (h *ModerationHandler) decide(c echo.Context, id uint, decision *model.ModerationDecision) error {
+	review, err := h.ModerationUsecase.Decide(id, decision, c.Get("username").(string))
+	if err != nil {
+		return moderationError(c, err)
+	}
+	return c.JSON(http.StatusOK, review)
+}
+
+func (h *ModerationHandler) GetLogs(c echo.Context) error {
+	reviewID, _ := strconv.Atoi(c.QueryParam("review_id"))
+	entries, err := h.ModerationUsecase.GetLogs(uint(reviewID))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, err)
+	}
+	return c.JSON(http.StatusOK, entries)
+}
+
+func moderationError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Not found"})
+	case errors.Is(err, usecase.ErrReasonRequired), errors.Is(err, usecase.ErrInvalidModeration), errors.Is(err, usecase.ErrReportOwnReview):
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	case errors.Is(err, usecase.ErrAlreadyReported):
+		return c.JSON(http.StatusConflict, map[string]string{"message": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, err)
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.test/model"
+	"go.test/usecase"
+	"go.test/usecase/mocks"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReportReview(t *testing.T) {
+	e := echo.New()
+	moderationUsecase := new(mocks.ModerationUsecase)
+	h := NewModerationHandler(moderationUsecase)
+
+	body, _ := json.Marshal(model.ReviewReport{Reason: "spam"})
+
+	moderationUsecase.On("Report", uint(8), "ahmad", "spam").Return(&model.ReviewReport{ID: 1, ReviewID: 8, Reason: "spam"}, nil).Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/reviews/8/report", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("8")
+	c.Set("username", "ahmad")
+
+	assert.NoError(t, h.Report(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	moderationUsecase.AssertExpectations(t)
+}
+
+func TestReportReviewTwice(t *testing.T) {
+	e := echo.New()
+	moderationUsecase := new(mocks.ModerationUsecase)
+	h := NewModerationHandler(moderationUsecase)
+
+	body, _ := json.Marshal(model.ReviewReport{Reason: "spam"})
+
+	moderationUsecase.On("Report", uint(8), "ahmad", "spam").Return(nil, usecase.ErrAlreadyReported).Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/reviews/8/report", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("8")
+	c.Set("username", "ahmad")
+
+	assert.NoError(t, h.Report(c))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	moderationUsecase.AssertExpectations(t)
+}
+
+func TestDecideBanWithoutReason(t *testing.T) {
+	e := echo.New()
+	moderationUsecase := new(mocks.ModerationUsecase)
+	h := NewModerationHandler(moderationUsecase)
+
+	body, _ := json.Marshal(model.ModerationDecision{Action: model.ModerationActionBan})
+
+	moderationUsecase.On("Decide", uint(8), mock.MatchedBy(func(d *model.ModerationDecision) bool {
+		return d.Action == model.ModerationActionBan
+	}), "supervisor1").Return(nil, usecase.ErrReasonRequired).Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/moderation/reviews/8", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("8")
+	c.Set("username", "supervisor1")
+
+	assert.NoError(t, h.Decide(c))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	moderationUsecase.AssertExpectations(t)
+}
+
+func TestHideReviewRecordsDecision(t *testing.T) {
+	e := echo.New()
+	moderationUsecase := new(mocks.ModerationUsecase)
+	h := NewModerationHandler(moderationUsecase)
+
+	moderationUsecase.On("Decide", uint(8), mock.MatchedBy(func(d *model.ModerationDecision) bool {
+		return d.Action == model.ModerationActionHide
+	}), "supervisor1").Return(&model.Review{ID: 8, Status: model.ReviewStatusHidden}, nil).Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/reviews/8/hide", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("8")
+	c.Set("username", "supervisor1")
+
+	assert.NoError(t, h.HideReview(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	moderationUsecase.AssertExpectations(t)
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go.test/model"
+	"go.test/usecase"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type PolicyHandler struct {
+	PolicyUsecase usecase.PolicyUsecase
+}
+
+func NewPolicyHandler(policyUsecase usecase.PolicyUsecase) *PolicyHandler {
+	return &PolicyHandler{policyUsecase}
+}
+
+func (h *PolicyHandler) GetPolicies(c echo.Context) error {
+	policies, err := h.PolicyUsecase.GetPolicies()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, err)
+	}
+	return c.JSON(http.StatusOK, policies)
+}
+
+func (h *PolicyHandler) CreatePolicy(c echo.Context) error {
+	policy := new(model.LoanPolicy)
+	if err := c.Bind(policy); err != nil {
+		return c.JSON(http.StatusBadRequest, err)
+	}
+	policy.ID = 0
+	if err := h.PolicyUsecase.CreatePolicy(policy); err != nil {
+		return policyError(c, err)
+	}
+	return c.JSON(http.StatusCreated, policy)
+}
+
+func (h *PolicyHandler) UpdatePolicy(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	policy := new(model.LoanPolicy)
+	if err := c.Bind(policy); err != nil {
+		return c.JSON(http.StatusBadRequest, err)
+	}
+	policy.ID = uint(id)
+	if err := h.PolicyUsecase.UpdatePolicy(policy); err != nil {
+		return policyError(c, err)
+	}
+	return c.JSON(http.StatusOK, policy)
+}
+
+func (h *PolicyHandler) DeletePolicy(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	if err := h.PolicyUsecase.DeletePolicy(uint(id)); err != nil {
+		return c.JSON(http.StatusInternalServerError, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// DryRun reports the policy that would apply if the user_id borrowed the
+// copy_id now, without checking anything out.
+func (h *PolicyHandler) DryRun(c echo.Context) error {
+	userID, err := strconv.Atoi(c.QueryParam("user_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "user_id is required"})
+	}
+	copyID, err := strconv.Atoi(c.QueryParam("copy_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "copy_id is required"})
+	}
+	result, err := h.PolicyUsecase.DryRun(uint(userID), uint(copyID))
+	if err != nil {
+		return policyError(c, err)
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+func policyError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Not found"})
+	case errors.Is(err, usecase.ErrInvalidPolicy):
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, err)
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.test/model"
+	"go.test/usecase/mocks"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicyDryRun(t *testing.T) {
+	e := echo.New()
+	policyUsecase := new(mocks.PolicyUsecase)
+	h := NewPolicyHandler(policyUsecase)
+
+	result := &model.PolicyDryRun{
+		UserID:  5,
+		CopyID:  3,
+		Policy:  &model.PolicyDecision{Role: "user", MaterialType: "book", LoanPeriodDays: 21, MaxLoans: 5, Source: model.PolicySourceDatabase, RuleID: 2},
+		Allowed: true,
+	}
+	policyUsecase.On("DryRun", uint(5), uint(3)).Return(result, nil).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/policies/evaluate?user_id=5&copy_id=3", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	assert.NoError(t, h.DryRun(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var got model.PolicyDryRun
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, 21, got.Policy.LoanPeriodDays)
+	assert.Equal(t, uint(2), got.Policy.RuleID)
+
+	policyUsecase.AssertExpectations(t)
+}
+
+func TestPolicyDryRunMissingCopy(t *testing.T) {
+	e := echo.New()
+	policyUsecase := new(mocks.PolicyUsecase)
+	h := NewPolicyHandler(policyUsecase)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/policies/evaluate?user_id=5", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	assert.NoError(t, h.DryRun(c))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	policyUsecase.AssertExpectations(t)
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.test/model"
+	"go.test/usecase"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type ProgressHandler struct {
+	ProgressUsecase usecase.ProgressUsecase
+}
+
+func NewProgressHandler(progressUsecase usecase.ProgressUsecase) *ProgressHandler {
+	return &ProgressHandler{progressUsecase}
+}
+
+func (h *ProgressHandler) RecordProgress(c echo.Context) error {
+	bookID, _ := strconv.Atoi(c.Param("id"))
+	req := new(model.ProgressRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, err)
+	}
+	progress, err := h.ProgressUsecase.RecordProgress(uint(bookID), req, c.Get("username").(string))
+	if err != nil {
+		return progressError(c, err)
+	}
+	return c.JSON(http.StatusOK, progress)
+}
+
+func (h *ProgressHandler) GetMyProgress(c echo.Context) error {
+	bookID, _ := strconv.Atoi(c.QueryParam("book_id"))
+	progress, err := h.ProgressUsecase.GetMyProgress(c.Get("username").(string), uint(bookID))
+	if err != nil {
+		return progressError(c, err)
+	}
+	return c.JSON(http.StatusOK, progress)
+}
+
+func (h *ProgressHandler) GetMyGoals(c echo.Context) error {
+	year, _ := strconv.Atoi(c.QueryParam("year"))
+	summaries, err := h.ProgressUsecase.GetMySummaries(c.Get("username").(string), year)
+	if err != nil {
+		return progressError(c, err)
+	}
+	return c.JSON(http.StatusOK, summaries)
+}
+
+func (h *ProgressHandler) SetGoal(c echo.Context) error {
+	year, _ := strconv.Atoi(c.Param("year"))
+	goal := new(model.ReadingGoal)
+	if err := c.Bind(goal); err != nil {
+		return c.JSON(http.StatusBadRequest, err)
+	}
+	summary, err := h.ProgressUsecase.SetGoal(year, goal.Target, c.Get("username").(string))
+	if err != nil {
+		return progressError(c, err)
+	}
+	return c.JSON(http.StatusOK, summary)
+}
+
+// GetReport returns the reading statistics of the year given in the query,
+// or of the current year.
+func (h *ProgressHandler) GetReport(c echo.Context) error {
+	year, _ := strconv.Atoi(c.QueryParam("year"))
+	if year == 0 {
+		year = time.Now().Year()
+	}
+	report, err := h.ProgressUsecase.GetReport(year)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, err)
+	}
+	return c.JSON(http.StatusOK, report)
+}
+
+func progressError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Not found"})
+	case errors.Is(err, usecase.ErrInvalidProgress), errors.Is(err, usecase.ErrInvalidGoal):
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, err)
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.test/model"
+	"go.test/usecase"
+	"go.test/usecase/mocks"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRecordProgress(t *testing.T) {
+	e := echo.New()
+	progressUsecase := new(mocks.ProgressUsecase)
+	h := NewProgressHandler(progressUsecase)
+
+	body := []byte(`{"page":40,"total_pages":160}`)
+
+	progressUsecase.On("RecordProgress", uint(3), mock.MatchedBy(func(r *model.ProgressRequest) bool {
+		return r.Page != nil && *r.Page == 40 && r.TotalPages != nil && *r.TotalPages == 160
+	}), "ahmad").Return(&model.ReadingProgress{ID: 1, BookID: 3, Page: 40, TotalPages: 160, Percent: 25}, nil).Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/books/3/progress", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("3")
+	c.Set("username", "ahmad")
+
+	assert.NoError(t, h.RecordProgress(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	progressUsecase.AssertExpectations(t)
+}
+
+func TestSetGoalInvalid(t *testing.T) {
+	e := echo.New()
+	progressUsecase := new(mocks.ProgressUsecase)
+	h := NewProgressHandler(progressUsecase)
+
+	body, _ := json.Marshal(model.ReadingGoal{Target: 0})
+
+	progressUsecase.On("SetGoal", 2026, 0, "ahmad").Return(nil, usecase.ErrInvalidGoal).Once()
+
+	req := httptest.NewRequest(http.MethodPut, "/api/goals/me/2026", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("year")
+	c.SetParamValues("2026")
+	c.Set("username", "ahmad")
+
+	assert.NoError(t, h.SetGoal(c))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	progressUsecase.AssertExpectations(t)
+}
+
+func TestGetReadingReport(t *testing.T) {
+	e := echo.New()
+	progressUsecase := new(mocks.ProgressUsecase)
+	h := NewProgressHandler(progressUsecase)
+
+	progressUsecase.On("GetReport", 2025).Return(&model.ReadingReport{Year: 2025, Readers: 12, Finished: 40}, nil).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/reports/reading?year=2025", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	assert.NoError(t, h.GetReport(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	progressUsecase.AssertExpectations(t)
+}
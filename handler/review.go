@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go.test/model"
+	"go.test/usecase"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type ReviewHandler struct {
+	ReviewUsecase usecase.ReviewUsecase
+}
+
+func NewReviewHandler(reviewUsecase usecase.ReviewUsecase) *ReviewHandler {
+	return &ReviewHandler{reviewUsecase}
+}
+
+func (h *ReviewHandler) GetReviews(c echo.Context) error {
+	bookID, _ := strconv.Atoi(c.Param("id"))
+	reviews, err := h.ReviewUsecase.GetReviews(uint(bookID), c.Get("role").(string))
+	if err != nil {
+		return reviewError(c, err)
+	}
+	return c.JSON(http.StatusOK, reviews)
+}
+
+func (h *ReviewHandler) CreateReview(c echo.Context) error {
+	bookID, _ := strconv.Atoi(c.Param("id"))
+	review := new(model.Review)
+	if err := c.Bind(review); err != nil {
+		return c.JSON(http.StatusBadRequest, err)
+	}
+	review.ID = 0
+	review.BookID = uint(bookID)
+	if err := h.ReviewUsecase.CreateReview(review, c.Get("username").(string)); err != nil {
+		return reviewError(c, err)
+	}
+	return c.JSON(http.StatusCreated, review)
+}
+
+func (h *ReviewHandler) UpdateReview(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	review := new(model.Review)
+	if err := c.Bind(review); err != nil {
+		return c.JSON(http.StatusBadRequest, err)
+	}
+	review.ID = uint(id)
+	if err := h.ReviewUsecase.UpdateReview(review, c.Get("username").(string)); err != nil {
+		return reviewError(c, err)
+	}
+	return c.JSON(http.StatusOK, review)
+}
+
+func (h *ReviewHandler) DeleteReview(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	if err := h.ReviewUsecase.DeleteReview(uint(id), c.Get("username").(string)); err != nil {
+		return reviewError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func reviewError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Not found"})
+	case errors.Is(err, usecase.ErrForbidden), errors.Is(err, usecase.ErrReviewBanned):
+		return c.JSON(http.StatusForbidden, map[string]string{"message": err.Error()})
+	case errors.Is(err, usecase.ErrInvalidRating):
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	case errors.Is(err, usecase.ErrReviewExists):
+		return c.JSON(http.StatusConflict, map[string]string{"message": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, err)
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.test/model"
+	"go.test/usecase"
+	"go.test/usecase/mocks"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateReview(t *testing.T) {
+	e := echo.New()
+	reviewUsecase := new(mocks.ReviewUsecase)
+	h := NewReviewHandler(reviewUsecase)
+
+	body, _ := json.Marshal(model.Review{Rating: 4, Text: "Loved it"})
+
+	reviewUsecase.On("CreateReview", mock.MatchedBy(func(r *model.Review) bool {
+		return r.BookID == 2 && r.Rating == 4
+	}), "ahmad").Return(nil).Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/books/2/reviews", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("2")
+	c.Set("username", "ahmad")
+
+	assert.NoError(t, h.CreateReview(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	reviewUsecase.AssertExpectations(t)
+}
+
+func TestCreateReviewInvalidRating(t *testing.T) {
+	e := echo.New()
+	reviewUsecase := new(mocks.ReviewUsecase)
+	h := NewReviewHandler(reviewUsecase)
+
+	body, _ := json.Marshal(model.Review{Rating: 9})
+
+	reviewUsecase.On("CreateReview", mock.Anything, "ahmad").Return(usecase.ErrInvalidRating).Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/books/2/reviews", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("2")
+	c.Set("username", "ahmad")
+
+	assert.NoError(t, h.CreateReview(c))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	reviewUsecase.AssertExpectations(t)
+}
+
+func TestUpdateReviewOfAnotherUser(t *testing.T) {
+	e := echo.New()
+	reviewUsecase := new(mocks.ReviewUsecase)
+	h := NewReviewHandler(reviewUsecase)
+
+	body, _ := json.Marshal(model.Review{Rating: 1})
+
+	reviewUsecase.On("UpdateReview", mock.Anything, "ahmad").Return(usecase.ErrForbidden).Once()
+
+	req := httptest.NewRequest(http.MethodPut, "/api/reviews/8", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("8")
+	c.Set("username", "ahmad")
+
+	assert.NoError(t, h.UpdateReview(c))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	reviewUsecase.AssertExpectations(t)
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go.test/usecase"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type TrashHandler struct {
+	TrashUsecase usecase.TrashUsecase
+}
+
+func NewTrashHandler(trashUsecase usecase.TrashUsecase) *TrashHandler {
+	return &TrashHandler{trashUsecase}
+}
+
+func (h *TrashHandler) GetTrash(c echo.Context) error {
+	trash, err := h.TrashUsecase.GetTrash()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, err)
+	}
+	return c.JSON(http.StatusOK, trash)
+}
+
+func (h *TrashHandler) RestoreBook(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	if err := h.TrashUsecase.RestoreBook(uint(id), c.Get("username").(string)); err != nil {
+		return trashError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *TrashHandler) PurgeBook(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	if err := h.TrashUsecase.PurgeBook(uint(id)); err != nil {
+		return trashError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *TrashHandler) RestoreUser(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	if err := h.TrashUsecase.RestoreUser(uint(id)); err != nil {
+		return trashError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *TrashHandler) PurgeUser(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	if err := h.TrashUsecase.PurgeUser(uint(id)); err != nil {
+		return trashError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func trashError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Not found"})
+	case errors.Is(err, usecase.ErrBookHasCopies), errors.Is(err, usecase.ErrUserHasObligations):
+		return c.JSON(http.StatusConflict, map[string]string{"message": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, err)
+}
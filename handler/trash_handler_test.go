@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.test/model"
+	"go.test/usecase"
+	"go.test/usecase/mocks"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestGetTrash(t *testing.T) {
+	e := echo.New()
+	trashUsecase := new(mocks.TrashUsecase)
+	h := NewTrashHandler(trashUsecase)
+
+	trashUsecase.On("GetTrash").Return(&model.Trash{Books: []model.Book{{ID: 1, Title: "Gone"}}, Users: []model.User{}}, nil).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/trash", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	assert.NoError(t, h.GetTrash(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	trashUsecase.AssertExpectations(t)
+}
+
+func TestRestoreBookNotInTrash(t *testing.T) {
+	e := echo.New()
+	trashUsecase := new(mocks.TrashUsecase)
+	h := NewTrashHandler(trashUsecase)
+
+	trashUsecase.On("RestoreBook", uint(4), "manager1").Return(gorm.ErrRecordNotFound).Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/trash/books/4/restore", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("4")
+	c.Set("username", "manager1")
+
+	assert.NoError(t, h.RestoreBook(c))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	trashUsecase.AssertExpectations(t)
+}
+
+func TestPurgeBookWithCopies(t *testing.T) {
+	e := echo.New()
+	trashUsecase := new(mocks.TrashUsecase)
+	h := NewTrashHandler(trashUsecase)
+
+	trashUsecase.On("PurgeBook", uint(4)).Return(usecase.ErrBookHasCopies).Once()
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/trash/books/4", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("4")
+
+	assert.NoError(t, h.PurgeBook(c))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	trashUsecase.AssertExpectations(t)
+}
+
+func TestPurgeUser(t *testing.T) {
+	e := echo.New()
+	trashUsecase := new(mocks.TrashUsecase)
+	h := NewTrashHandler(trashUsecase)
+
+	trashUsecase.On("PurgeUser", uint(9)).Return(nil).Once()
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/trash/users/9", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("9")
+
+	assert.NoError(t, h.PurgeUser(c))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	trashUsecase.AssertExpectations(t)
+}
@@ -1,14 +1,17 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"go.test/middleware"
 	"go.test/model"
 	"go.test/usecase"
+	util "go.test/utils"
 
 	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
 )
 
 type UserHandler struct {
@@ -25,7 +28,7 @@ func (h *UserHandler) RegisterUser(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, err)
 	}
 	if err := h.UserUsecase.RegisterUser(user); err != nil {
-		return c.JSON(http.StatusInternalServerError, err)
+		return userError(c, err)
 	}
 	return c.JSON(http.StatusCreated, user)
 }
@@ -56,6 +59,7 @@ func (h *UserHandler) GetUser(c echo.Context) error {
 	if err != nil {
 		return c.JSON(http.StatusNotFound, err)
 	}
+	c.Response().Header().Set("ETag", util.ETag(user.Version))
 	return c.JSON(http.StatusOK, user)
 }
 
@@ -70,20 +74,76 @@ func (h *UserHandler) updateUserHandler(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, err)
 	}
 	user.ID = uint(id)
-	if err := h.UserUsecase.UpdateUser(user); err != nil {
-		return c.JSON(http.StatusInternalServerError, err)
+	version, ok := ifMatch(c)
+	if !ok {
+		return c.JSON(http.StatusPreconditionFailed, map[string]string{"message": usecase.ErrVersionMismatch.Error()})
+	}
+	user.Version = version
+	if err := h.UserUsecase.UpdateUser(user, c.Get("username").(string)); err != nil {
+		return userError(c, err)
+	}
+	c.Response().Header().Set("ETag", util.ETag(user.Version))
+	return c.JSON(http.StatusOK, user)
+}
+
+func (h *UserHandler) PatchUser(c echo.Context) error {
+	return middleware.RoleBasedAccess(h.patchUserHandler, "supervisor")(c)
+}
+
+func (h *UserHandler) patchUserHandler(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	version, ok := ifMatch(c)
+	if !ok {
+		return c.JSON(http.StatusPreconditionFailed, map[string]string{"message": usecase.ErrVersionMismatch.Error()})
+	}
+	mediaType, patch, err := readPatch(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err)
 	}
+	user, err := h.UserUsecase.PatchUser(uint(id), version, mediaType, patch, c.Get("username").(string))
+	if err != nil {
+		return userError(c, err)
+	}
+	c.Response().Header().Set("ETag", util.ETag(user.Version))
 	return c.JSON(http.StatusOK, user)
 }
 
+func (h *UserHandler) GetHistory(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	revisions, err := h.UserUsecase.GetHistory(uint(id))
+	if err != nil {
+		return userError(c, err)
+	}
+	return c.JSON(http.StatusOK, revisions)
+}
+
 func (h *UserHandler) DeleteUser(c echo.Context) error {
 	return middleware.RoleBasedAccess(h.deleteUserHandler, "manager")(c)
 }
 
 func (h *UserHandler) deleteUserHandler(c echo.Context) error {
 	id, _ := strconv.Atoi(c.Param("id"))
-	if err := h.UserUsecase.DeleteUser(uint(id)); err != nil {
-		return c.JSON(http.StatusInternalServerError, err)
+	version, ok := ifMatch(c)
+	if !ok {
+		return c.JSON(http.StatusPreconditionFailed, map[string]string{"message": usecase.ErrVersionMismatch.Error()})
+	}
+	if err := h.UserUsecase.DeleteUser(uint(id), version); err != nil {
+		return userError(c, err)
 	}
 	return c.NoContent(http.StatusNoContent)
 }
+
+func userError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Not found"})
+	case errors.Is(err, usecase.ErrVersionMismatch):
+		return c.JSON(http.StatusPreconditionFailed, map[string]string{"message": err.Error()})
+	case errors.Is(err, usecase.ErrUsernameTaken), errors.Is(err, usecase.ErrUsernameInTrash):
+		return c.JSON(http.StatusConflict, map[string]string{"message": err.Error()})
+	}
+	if status := patchStatus(err); status != 0 {
+		return c.JSON(status, map[string]string{"message": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, err)
+}
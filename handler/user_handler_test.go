@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"go.test/model"
+	"go.test/usecase"
 	"go.test/usecase/mocks"
 
 	"github.com/labstack/echo/v4"
@@ -62,6 +63,28 @@ func TestRegisterUser(t *testing.T) {
 	userUsecase.AssertExpectations(t)
 }
 
+func TestRegisterUserInTrash(t *testing.T) {
+	e := echo.New()
+
+	userUsecase := new(mocks.UserUsecase)
+	h := NewUserHandler(userUsecase)
+
+	body, _ := json.Marshal(model.User{Username: "ahmad", Password: "123", Role: "user"})
+
+	userUsecase.On("RegisterUser", mock.Anything).Return(usecase.ErrUsernameInTrash).Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/register", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	assert.NoError(t, h.RegisterUser(c))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), "restore or purge")
+
+	userUsecase.AssertExpectations(t)
+}
+
 func (suite *UserHandlerTestSuite) TestLoginUser() {
 
 	loginDetails := map[string]string{
@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go.test/model"
+	"go.test/usecase"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type WorkHandler struct {
+	WorkUsecase usecase.WorkUsecase
+}
+
+func NewWorkHandler(workUsecase usecase.WorkUsecase) *WorkHandler {
+	return &WorkHandler{workUsecase}
+}
+
+func (h *WorkHandler) GetWork(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	work, err := h.WorkUsecase.GetWork(uint(id))
+	if err != nil {
+		return workError(c, err)
+	}
+	return c.JSON(http.StatusOK, work)
+}
+
+func (h *WorkHandler) CreateWork(c echo.Context) error {
+	work := new(model.Work)
+	if err := c.Bind(work); err != nil {
+		return c.JSON(http.StatusBadRequest, err)
+	}
+	if err := h.WorkUsecase.CreateWork(work); err != nil {
+		return workError(c, err)
+	}
+	return c.JSON(http.StatusCreated, work)
+}
+
+func (h *WorkHandler) UpdateWork(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	work := new(model.Work)
+	if err := c.Bind(work); err != nil {
+		return c.JSON(http.StatusBadRequest, err)
+	}
+	work.ID = uint(id)
+	if err := h.WorkUsecase.UpdateWork(work); err != nil {
+		return workError(c, err)
+	}
+	updated, err := h.WorkUsecase.GetWork(work.ID)
+	if err != nil {
+		return workError(c, err)
+	}
+	return c.JSON(http.StatusOK, updated)
+}
+
+func (h *WorkHandler) DeleteWork(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	if err := h.WorkUsecase.DeleteWork(uint(id)); err != nil {
+		return workError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *WorkHandler) AddEdition(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	req := new(model.EditionRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, err)
+	}
+	work, err := h.WorkUsecase.AddEdition(uint(id), req)
+	if err != nil {
+		return workError(c, err)
+	}
+	return c.JSON(http.StatusOK, work)
+}
+
+func (h *WorkHandler) RemoveEdition(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	bookID, _ := strconv.Atoi(c.Param("book_id"))
+	if err := h.WorkUsecase.RemoveEdition(uint(id), uint(bookID)); err != nil {
+		return workError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *WorkHandler) GetAllSeries(c echo.Context) error {
+	series, err := h.WorkUsecase.GetAllSeries()
+	if err != nil {
+		return workError(c, err)
+	}
+	return c.JSON(http.StatusOK, series)
+}
+
+func (h *WorkHandler) GetSeries(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	series, err := h.WorkUsecase.GetSeries(uint(id))
+	if err != nil {
+		return workError(c, err)
+	}
+	return c.JSON(http.StatusOK, series)
+}
+
+func (h *WorkHandler) CreateSeries(c echo.Context) error {
+	series := new(model.Series)
+	if err := c.Bind(series); err != nil {
+		return c.JSON(http.StatusBadRequest, err)
+	}
+	if err := h.WorkUsecase.CreateSeries(series); err != nil {
+		return workError(c, err)
+	}
+	return c.JSON(http.StatusCreated, series)
+}
+
+func (h *WorkHandler) UpdateSeries(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	series := new(model.Series)
+	if err := c.Bind(series); err != nil {
+		return c.JSON(http.StatusBadRequest, err)
+	}
+	series.ID = uint(id)
+	if err := h.WorkUsecase.UpdateSeries(series); err != nil {
+		return workError(c, err)
+	}
+	updated, err := h.WorkUsecase.GetSeries(series.ID)
+	if err != nil {
+		return workError(c, err)
+	}
+	return c.JSON(http.StatusOK, updated)
+}
+
+func (h *WorkHandler) DeleteSeries(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	if err := h.WorkUsecase.DeleteSeries(uint(id)); err != nil {
+		return workError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *WorkHandler) SetSeriesEntry(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	workID, _ := strconv.Atoi(c.Param("work_id"))
+	req := new(model.SeriesEntryRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, err)
+	}
+	series, err := h.WorkUsecase.SetSeriesEntry(uint(id), uint(workID), req)
+	if err != nil {
+		return workError(c, err)
+	}
+	return c.JSON(http.StatusOK, series)
+}
+
+func (h *WorkHandler) RemoveSeriesEntry(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	workID, _ := strconv.Atoi(c.Param("work_id"))
+	if err := h.WorkUsecase.RemoveSeriesEntry(uint(id), uint(workID)); err != nil {
+		return workError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func workError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Not found"})
+	case errors.Is(err, usecase.ErrNotEdition), errors.Is(err, usecase.ErrNotInSeries):
+		return c.JSON(http.StatusNotFound, map[string]string{"message": err.Error()})
+	case errors.Is(err, usecase.ErrTitleRequired), errors.Is(err, usecase.ErrInvalidSeriesPosition):
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, err)
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.test/model"
+	"go.test/usecase"
+	"go.test/usecase/mocks"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetWork(t *testing.T) {
+	e := echo.New()
+	workUsecase := new(mocks.WorkUsecase)
+	h := NewWorkHandler(workUsecase)
+
+	work := &model.Work{ID: 3, Title: "Dune Messiah", Editions: []model.Book{{ID: 4}}, Previous: &model.Work{ID: 1, Title: "Dune"}}
+	workUsecase.On("GetWork", uint(3)).Return(work, nil).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/works/3", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("3")
+
+	assert.NoError(t, h.GetWork(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var got model.Work
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, uint(4), got.Editions[0].ID)
+	assert.Equal(t, "Dune", got.Previous.Title)
+	assert.Nil(t, got.Next)
+
+	workUsecase.AssertExpectations(t)
+}
+
+func TestSetSeriesEntry(t *testing.T) {
+	e := echo.New()
+	workUsecase := new(mocks.WorkUsecase)
+	h := NewWorkHandler(workUsecase)
+
+	series := &model.Series{ID: 2, Works: []model.Work{{ID: 1}, {ID: 3}}}
+	workUsecase.On("SetSeriesEntry", uint(2), uint(3), mock.MatchedBy(func(req *model.SeriesEntryRequest) bool {
+		return req.Position != nil && *req.Position == 1.5
+	})).Return(series, nil).Once()
+	workUsecase.On("SetSeriesEntry", uint(2), uint(3), &model.SeriesEntryRequest{}).Return(nil, usecase.ErrInvalidSeriesPosition).Once()
+
+	for body, code := range map[string]int{`{"position":1.5}`: http.StatusOK, `{}`: http.StatusBadRequest} {
+		req := httptest.NewRequest(http.MethodPut, "/api/series/2/works/3", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id", "work_id")
+		c.SetParamValues("2", "3")
+
+		assert.NoError(t, h.SetSeriesEntry(c))
+		assert.Equal(t, code, rec.Code, body)
+	}
+
+	workUsecase.AssertExpectations(t)
+}
+
+func TestRemoveEditionOfAnotherWork(t *testing.T) {
+	e := echo.New()
+	workUsecase := new(mocks.WorkUsecase)
+	h := NewWorkHandler(workUsecase)
+
+	workUsecase.On("RemoveEdition", uint(1), uint(4)).Return(usecase.ErrNotEdition).Once()
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/works/1/editions/4", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id", "book_id")
+	c.SetParamValues("1", "4")
+
+	assert.NoError(t, h.RemoveEdition(c))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	workUsecase.AssertExpectations(t)
+}
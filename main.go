@@ -10,6 +10,7 @@ import (
 	"go.test/handler"
 	"go.test/middleware"
 	"go.test/repository"
+	"go.test/storage"
 	"go.test/usecase"
 
 	"github.com/joho/godotenv"
@@ -29,29 +30,257 @@ func main() {
 	db := config.InitDB()
 
 	bookRepo := repository.NewBookRepository(db)
-	bookUsecase := usecase.NewBookUsecase(bookRepo)
+	copyRepo := repository.NewCopyRepository(db)
+	reviewRepo := repository.NewReviewRepository(db)
+	listRepo := repository.NewListRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	coverRepo := repository.NewCoverRepository(db)
+	bookUsecase := usecase.NewBookUsecase(bookRepo, copyRepo, reviewRepo, listRepo, userRepo, coverRepo)
 	bookHandler := handler.NewBookHandler(bookUsecase)
 
-	userRepo := repository.NewUserRepository(db)
+	unparsed, err := bookUsecase.MigratePublishedDates()
+	if err != nil {
+		e.Logger.Fatal("migrating published dates: ", err)
+	}
+	for _, date := range unparsed {
+		e.Logger.Warnf("book %d: published date %q is not a date; set it by hand", date.BookID, date.Value)
+	}
+	if err := bookUsecase.MigrateISBNKeys(); err != nil {
+		e.Logger.Fatal("migrating ISBN keys: ", err)
+	}
+
+	urlSigner := config.URLSigner()
+	store, err := config.BlobStore(urlSigner)
+	if err != nil {
+		e.Logger.Fatal("opening blob store: ", err)
+	}
+	blobUsecase := usecase.NewBlobUsecase(store, urlSigner)
+	blobHandler := handler.NewBlobHandler(blobUsecase)
+
+	coverUsecase := usecase.NewCoverUsecase(coverRepo, bookRepo, store, config.CoverMaxBytes())
+	coverHandler := handler.NewCoverHandler(coverUsecase)
+
+	duplicateUsecase := usecase.NewDuplicateUsecase(bookRepo, coverUsecase)
+	duplicateHandler := handler.NewDuplicateHandler(duplicateUsecase)
+
+	importRepo := repository.NewImportRepository(db)
+	importUsecase := usecase.NewImportUsecase(importRepo, config.ImportSyncLimit())
+	importHandler := handler.NewImportHandler(importUsecase)
+
+	exportUsecase := usecase.NewExportUsecase(bookRepo)
+	exportHandler := handler.NewExportHandler(exportUsecase)
+
+	citationUsecase := usecase.NewCitationUsecase(bookRepo)
+	citationHandler := handler.NewCitationHandler(citationUsecase)
+
+	copyUsecase := usecase.NewCopyUsecase(copyRepo, bookRepo)
+	copyHandler := handler.NewCopyHandler(copyUsecase)
+
 	userUsecase := usecase.NewUserUsecase(userRepo)
 	userHandler := handler.NewUserHandler(userUsecase)
 
+	listUsecase := usecase.NewListUsecase(listRepo, bookRepo, userRepo)
+	listHandler := handler.NewListHandler(listUsecase)
+
+	progressRepo := repository.NewProgressRepository(db)
+	progressUsecase := usecase.NewProgressUsecase(progressRepo, bookRepo, userRepo, config.ReadingReportMinReaders())
+	progressHandler := handler.NewProgressHandler(progressUsecase)
+
+	moderationRepo := repository.NewModerationRepository(db)
+	filterWords, filterPatterns, err := config.ReviewFilterRules()
+	if err != nil {
+		e.Logger.Fatal("loading review filter: ", err)
+	}
+	reviewFilter, err := usecase.NewContentFilter(filterWords, filterPatterns)
+	if err != nil {
+		e.Logger.Fatal("compiling review filter: ", err)
+	}
+	reviewUsecase := usecase.NewReviewUsecase(reviewRepo, bookRepo, userRepo, moderationRepo, reviewFilter)
+	reviewHandler := handler.NewReviewHandler(reviewUsecase)
+
+	moderationUsecase := usecase.NewModerationUsecase(moderationRepo, reviewRepo, userRepo)
+	moderationHandler := handler.NewModerationHandler(moderationUsecase)
+
+	loanRepo := repository.NewLoanRepository(db)
+	policyRepo := repository.NewPolicyRepository(db)
+	loanPolicies, err := config.LoanPolicies()
+	if err != nil {
+		e.Logger.Fatal("loading loan policies: ", err)
+	}
+	defaultPolicy := config.DefaultLoanPolicy()
+	if err := usecase.ValidatePolicies(defaultPolicy, loanPolicies); err != nil {
+		e.Logger.Fatal("loading loan policies: ", err)
+	}
+	policyUsecase := usecase.NewPolicyUsecase(policyRepo, userRepo, copyRepo, bookRepo, loanRepo, loanPolicies, defaultPolicy)
+	policyHandler := handler.NewPolicyHandler(policyUsecase)
+
+	fineRepo := repository.NewFineRepository(db)
+	fineUsecase := usecase.NewFineUsecase(fineRepo, loanRepo, copyRepo, userRepo, policyUsecase, usecase.NewLocalPaymentProvider(), config.FineBlockThreshold())
+	fineHandler := handler.NewFineHandler(fineUsecase)
+
+	holdRepo := repository.NewHoldRepository(db)
+	loanUsecase := usecase.NewLoanUsecase(loanRepo, copyRepo, userRepo, holdRepo, policyUsecase, fineUsecase, config.HoldPickupPeriod())
+	loanHandler := handler.NewLoanHandler(loanUsecase)
+
+	holdUsecase := usecase.NewHoldUsecase(holdRepo, copyRepo, bookRepo, userRepo, config.HoldPickupPeriod())
+	holdHandler := handler.NewHoldHandler(holdUsecase)
+
+	ebookRepo := repository.NewEbookRepository(db)
+	ebookFiles := storage.NewContentStore(store)
+	ebookUsecase := usecase.NewEbookUsecase(ebookRepo, bookRepo, loanRepo, userRepo, coverUsecase, ebookFiles, urlSigner, config.EbookMaxBytes(), config.EbookURLLifetime())
+	ebookHandler := handler.NewEbookHandler(ebookUsecase)
+
+	go every(config.HoldSweepInterval(), func() {
+		expired, assigned, err := holdUsecase.ProcessHolds()
+		if err != nil {
+			e.Logger.Error("processing holds: ", err)
+			return
+		}
+		if expired > 0 || assigned > 0 {
+			e.Logger.Infof("holds: %d expired, %d ready for pickup", expired, assigned)
+		}
+	})
+
+	workUsecase := usecase.NewWorkUsecase(repository.NewWorkRepository(db))
+	workHandler := handler.NewWorkHandler(workUsecase)
+
+	trashUsecase := usecase.NewTrashUsecase(bookRepo, userRepo, ebookRepo, store, ebookFiles, config.TrashRetention())
+	trashHandler := handler.NewTrashHandler(trashUsecase)
+
+	go every(config.TrashSweepInterval(), func() {
+		books, users, err := trashUsecase.PurgeExpired()
+		if err != nil {
+			e.Logger.Error("purging trash: ", err)
+			return
+		}
+		if books > 0 || users > 0 {
+			e.Logger.Infof("trash: purged %d books and %d users", books, users)
+		}
+	})
+
 	e.POST("/api/register", userHandler.RegisterUser)
 	e.POST("/api/login", userHandler.LoginUser)
+	e.GET("/api/shared/lists/:token", listHandler.GetSharedList)
+	e.GET("/api/books/:id/cover", coverHandler.GetCover)
+	e.GET("/api/blobs/*", blobHandler.GetBlob)
+	e.GET("/api/ebooks/:id/download", ebookHandler.Download)
 
 	restricted := e.Group("/api")
 	restricted.Use(middleware.JWTMiddleware)
 
+	var ifMatch []echo.MiddlewareFunc
+	if config.RequireIfMatch() {
+		ifMatch = append(ifMatch, middleware.RequireIfMatch)
+	}
+
 	restricted.GET("/books", bookHandler.GetBooks)
 	restricted.GET("/books/:id", bookHandler.GetBook)
+	restricted.GET("/books/cite", citationHandler.CiteBooks)
+	restricted.GET("/books/:id/cite", citationHandler.CiteBook)
+	restricted.GET("/books/export", middleware.RoleBasedAccess(exportHandler.ExportBooks, "supervisor"))
+	restricted.POST("/books/import", middleware.RoleBasedAccess(importHandler.ImportBooks, "supervisor"))
+	restricted.GET("/books/imports/:id", middleware.RoleBasedAccess(importHandler.GetJob, "supervisor"))
+	restricted.GET("/books/imports/:id/rows", middleware.RoleBasedAccess(importHandler.GetRows, "supervisor"))
+	restricted.GET("/books/duplicates", middleware.RoleBasedAccess(duplicateHandler.GetDuplicates, "supervisor"))
+	restricted.POST("/books/merge", middleware.RoleBasedAccess(duplicateHandler.MergeBooks, "supervisor"))
 	restricted.POST("/books", middleware.RoleBasedAccess(bookHandler.CreateBook, "supervisor"))
-	restricted.PUT("/books/:id", middleware.RoleBasedAccess(bookHandler.UpdateBook, "supervisor"))
-	restricted.DELETE("/books/:id", middleware.RoleBasedAccess(bookHandler.DeleteBook, "manager"))
+	restricted.PUT("/books/:id", middleware.RoleBasedAccess(bookHandler.UpdateBook, "supervisor"), ifMatch...)
+	restricted.PATCH("/books/:id", middleware.RoleBasedAccess(bookHandler.PatchBook, "supervisor"), ifMatch...)
+	restricted.DELETE("/books/:id", middleware.RoleBasedAccess(bookHandler.DeleteBook, "manager"), ifMatch...)
+	restricted.GET("/books/:id/history", bookHandler.GetHistory)
+	restricted.POST("/books/:id/revert/:rev", middleware.RoleBasedAccess(bookHandler.RevertBook, "supervisor"))
+	restricted.PUT("/books/:id/cover", middleware.RoleBasedAccess(coverHandler.SetCover, "supervisor"))
+	restricted.DELETE("/books/:id/cover", middleware.RoleBasedAccess(coverHandler.DeleteCover, "supervisor"))
+	restricted.GET("/books/:id/ebooks", ebookHandler.GetEbooks)
+	restricted.POST("/books/:id/ebooks", middleware.RoleBasedAccess(ebookHandler.AddEbook, "supervisor"))
+	restricted.DELETE("/ebooks/:id", middleware.RoleBasedAccess(ebookHandler.DeleteEbook, "supervisor"))
+	restricted.POST("/ebooks/:id/download-url", ebookHandler.GetDownload)
+
+	restricted.GET("/works/:id", workHandler.GetWork)
+	restricted.POST("/works", middleware.RoleBasedAccess(workHandler.CreateWork, "supervisor"))
+	restricted.PUT("/works/:id", middleware.RoleBasedAccess(workHandler.UpdateWork, "supervisor"))
+	restricted.DELETE("/works/:id", middleware.RoleBasedAccess(workHandler.DeleteWork, "supervisor"))
+	restricted.POST("/works/:id/editions", middleware.RoleBasedAccess(workHandler.AddEdition, "supervisor"))
+	restricted.DELETE("/works/:id/editions/:book_id", middleware.RoleBasedAccess(workHandler.RemoveEdition, "supervisor"))
+	restricted.GET("/series", workHandler.GetAllSeries)
+	restricted.GET("/series/:id", workHandler.GetSeries)
+	restricted.POST("/series", middleware.RoleBasedAccess(workHandler.CreateSeries, "supervisor"))
+	restricted.PUT("/series/:id", middleware.RoleBasedAccess(workHandler.UpdateSeries, "supervisor"))
+	restricted.DELETE("/series/:id", middleware.RoleBasedAccess(workHandler.DeleteSeries, "supervisor"))
+	restricted.PUT("/series/:id/works/:work_id", middleware.RoleBasedAccess(workHandler.SetSeriesEntry, "supervisor"))
+	restricted.DELETE("/series/:id/works/:work_id", middleware.RoleBasedAccess(workHandler.RemoveSeriesEntry, "supervisor"))
+
+	restricted.GET("/books/:id/copies", copyHandler.GetCopies)
+	restricted.GET("/books/:id/availability", copyHandler.GetAvailability)
+	restricted.POST("/books/:id/holds", holdHandler.PlaceHold)
+	restricted.GET("/books/:id/holds", middleware.RoleBasedAccess(holdHandler.GetQueue, "supervisor"))
+	restricted.POST("/books/:id/copies", middleware.RoleBasedAccess(copyHandler.CreateCopy, "supervisor"))
+	restricted.GET("/copies/:id", copyHandler.GetCopy)
+	restricted.PUT("/copies/:id", middleware.RoleBasedAccess(copyHandler.UpdateCopy, "supervisor"))
+	restricted.DELETE("/copies/:id", middleware.RoleBasedAccess(copyHandler.DeleteCopy, "manager"))
+	restricted.GET("/copies/:id/loans", middleware.RoleBasedAccess(loanHandler.GetCopyLoans, "supervisor"))
+
+	restricted.POST("/loans", middleware.RoleBasedAccess(loanHandler.Checkout, "supervisor"))
+	restricted.GET("/loans/me", loanHandler.GetMyLoans)
+	restricted.GET("/loans/:id", loanHandler.GetLoan)
+	restricted.POST("/loans/:id/return", middleware.RoleBasedAccess(loanHandler.Return, "supervisor"))
+	restricted.POST("/loans/:id/renew", loanHandler.Renew)
+
+	restricted.GET("/policies", middleware.RoleBasedAccess(policyHandler.GetPolicies, "supervisor"))
+	restricted.GET("/policies/evaluate", middleware.RoleBasedAccess(policyHandler.DryRun, "supervisor"))
+	restricted.POST("/policies", middleware.RoleBasedAccess(policyHandler.CreatePolicy, "manager"))
+	restricted.PUT("/policies/:id", middleware.RoleBasedAccess(policyHandler.UpdatePolicy, "manager"))
+	restricted.DELETE("/policies/:id", middleware.RoleBasedAccess(policyHandler.DeletePolicy, "manager"))
+
+	restricted.GET("/fines/me", fineHandler.GetMyAccount)
+	restricted.POST("/fines/me/payments", fineHandler.Pay)
+	restricted.POST("/fines/:id/waive", middleware.RoleBasedAccess(fineHandler.Waive, "supervisor"))
+
+	restricted.GET("/books/:id/reviews", reviewHandler.GetReviews)
+	restricted.POST("/books/:id/reviews", reviewHandler.CreateReview)
+	restricted.PUT("/reviews/:id", reviewHandler.UpdateReview)
+	restricted.DELETE("/reviews/:id", reviewHandler.DeleteReview)
+	restricted.POST("/reviews/:id/report", moderationHandler.Report)
+	restricted.POST("/reviews/:id/hide", middleware.RoleBasedAccess(moderationHandler.HideReview, "supervisor"))
+	restricted.POST("/reviews/:id/unhide", middleware.RoleBasedAccess(moderationHandler.UnhideReview, "supervisor"))
+
+	restricted.GET("/moderation/reviews", middleware.RoleBasedAccess(moderationHandler.GetQueue, "supervisor"))
+	restricted.POST("/moderation/reviews/:id", middleware.RoleBasedAccess(moderationHandler.Decide, "supervisor"))
+	restricted.GET("/moderation/log", middleware.RoleBasedAccess(moderationHandler.GetLogs, "supervisor"))
+
+	restricted.GET("/lists/me", listHandler.GetMyLists)
+	restricted.POST("/lists", listHandler.CreateList)
+	restricted.GET("/lists/:id", listHandler.GetList)
+	restricted.PUT("/lists/:id", listHandler.UpdateList)
+	restricted.DELETE("/lists/:id", listHandler.DeleteList)
+	restricted.POST("/lists/:id/books", listHandler.AddBook)
+	restricted.PUT("/lists/:id/books/:book_id", listHandler.UpdateEntry)
+	restricted.DELETE("/lists/:id/books/:book_id", listHandler.RemoveBook)
+
+	restricted.POST("/books/:id/progress", progressHandler.RecordProgress)
+	restricted.GET("/progress/me", progressHandler.GetMyProgress)
+	restricted.GET("/goals/me", progressHandler.GetMyGoals)
+	restricted.PUT("/goals/me/:year", progressHandler.SetGoal)
+	restricted.GET("/reports/reading", middleware.RoleBasedAccess(progressHandler.GetReport, "manager"))
+
+	restricted.GET("/trash", middleware.RoleBasedAccess(trashHandler.GetTrash, "manager"))
+	restricted.POST("/trash/books/:id/restore", middleware.RoleBasedAccess(trashHandler.RestoreBook, "manager"))
+	restricted.DELETE("/trash/books/:id", middleware.RoleBasedAccess(trashHandler.PurgeBook, "manager"))
+	restricted.POST("/trash/users/:id/restore", middleware.RoleBasedAccess(trashHandler.RestoreUser, "manager"))
+	restricted.DELETE("/trash/users/:id", middleware.RoleBasedAccess(trashHandler.PurgeUser, "manager"))
+
+	restricted.GET("/holds/me", holdHandler.GetMyHolds)
+	restricted.DELETE("/holds/:id", holdHandler.CancelHold)
 
 	restricted.GET("/users", userHandler.GetUsers)
 	restricted.GET("/users/:id", userHandler.GetUser)
-	restricted.PUT("/users/:id", middleware.RoleBasedAccess(userHandler.UpdateUser, "supervisor"))
-	restricted.DELETE("/users/:id", middleware.RoleBasedAccess(userHandler.DeleteUser, "manager"))
+	restricted.PUT("/users/:id", middleware.RoleBasedAccess(userHandler.UpdateUser, "supervisor"), ifMatch...)
+	restricted.PATCH("/users/:id", middleware.RoleBasedAccess(userHandler.PatchUser, "supervisor"), ifMatch...)
+	restricted.DELETE("/users/:id", middleware.RoleBasedAccess(userHandler.DeleteUser, "manager"), ifMatch...)
+	restricted.GET("/users/:id/history", middleware.RoleBasedAccess(userHandler.GetHistory, "supervisor"))
+	restricted.GET("/users/:id/loans", middleware.RoleBasedAccess(loanHandler.GetUserLoans, "supervisor"))
+	restricted.GET("/users/:id/fines", middleware.RoleBasedAccess(fineHandler.GetUserAccount, "supervisor"))
+	restricted.POST("/users/:id/fines/payments", middleware.RoleBasedAccess(fineHandler.RecordPayment, "supervisor"))
 
 	// Start server
 	port := os.Getenv("SERVICE_PORT")
@@ -65,3 +294,16 @@ func main() {
 	}
 	e.Logger.Fatal(e.StartServer(s))
 }
+
+// every runs job at the given interval for the lifetime of the process. An
+// interval of zero or less never runs it.
+func every(interval time.Duration, job func()) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		job()
+	}
+}
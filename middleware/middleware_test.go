@@ -76,3 +76,37 @@ func TestRoleBasedAccess(t *testing.T) {
 		})
 	}
 }
+
+func TestRequireIfMatch(t *testing.T) {
+	e := echo.New()
+
+	handler := func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	}
+
+	tests := []struct {
+		name         string
+		ifMatch      string
+		expectedCode int
+	}{
+		{name: "Missing header", ifMatch: "", expectedCode: http.StatusPreconditionRequired},
+		{name: "Entity tag", ifMatch: `"3"`, expectedCode: http.StatusNoContent},
+		{name: "Any version", ifMatch: "*", expectedCode: http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/", nil)
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := RequireIfMatch(handler)(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedCode, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RequireIfMatch rejects requests without an If-Match header, so that
+// clients cannot change a resource without saying which version they saw.
+func RequireIfMatch(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if c.Request().Header.Get("If-Match") == "" {
+			return c.JSON(http.StatusPreconditionRequired, map[string]string{"message": "If-Match header is required"})
+		}
+		return next(c)
+	}
+}
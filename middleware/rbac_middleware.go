@@ -3,6 +3,8 @@ package middleware
 import (
 	"net/http"
 
+	util "go.test/utils"
+
 	"github.com/labstack/echo/v4"
 )
 
@@ -11,13 +13,7 @@ func RoleBasedAccess(next echo.HandlerFunc, requiredRole string) echo.HandlerFun
 	return func(c echo.Context) error {
 		userRole := c.Get("role").(string)
 
-		roleHierarchy := map[string]int{
-			"user":       1,
-			"supervisor": 2,
-			"manager":    3,
-		}
-
-		if roleHierarchy[userRole] < roleHierarchy[requiredRole] {
+		if !util.HasRole(userRole, requiredRole) {
 			return echo.NewHTTPError(http.StatusForbidden, "You don't have the necessary permissions to access this resource.")
 		}
 
@@ -1,9 +1,74 @@
 package model
 
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MaterialTypeBook is the material type of books that do not set one.
+const MaterialTypeBook = "book"
+
+const (
+	BookSortID        = "id"
+	BookSortTitle     = "title"
+	BookSortRating    = "rating"
+	BookSortPublished = "published"
+)
+
 type Book struct {
-	ID            uint   `json:"id" gorm:"primaryKey"`
-	Title         string `json:"title"`
-	Author        string `json:"author"`
-	ISBN          string `json:"isbn"`
-	PublishedDate string `json:"published_date"`
+	ID            uint             `json:"id" gorm:"primaryKey"`
+	Title         string           `json:"title"`
+	Author        string           `json:"author"`
+	ISBN          string           `json:"isbn"`
+	ISBNKey       string           `json:"-" gorm:"size:13;index"` // the ISBN-13 of a valid ISBN, which imports match on
+	PublishedDate PartialDate      `json:"published_date" gorm:"column:published_on;index"`
+	MaterialType  string           `json:"material_type" gorm:"size:32"`
+	WorkID        *uint            `json:"work_id" gorm:"index"` // set through the work; see Work
+	Version       int              `json:"version" gorm:"not null;default:1"`
+	UpdatedAt     time.Time        `json:"updated_at" gorm:"index"`
+	MARC          []byte           `json:"-" gorm:"->;-:migration"` // only loaded by exports and citations; see BookMARC
+	DeletedAt     gorm.DeletedAt   `json:"deleted_at,omitempty" gorm:"index"`
+	Availability  *Availability    `json:"availability,omitempty" gorm:"-"`
+	AverageRating float64          `json:"average_rating" gorm:"-"`
+	ReviewCount   int              `json:"review_count" gorm:"-"`
+	Lists         []ListMembership `json:"lists,omitempty" gorm:"-"`
+	CoverURL      string           `json:"cover_url,omitempty" gorm:"-"`
+	EditionCount  int              `json:"edition_count,omitempty" gorm:"->;-:migration"` // only loaded by collapsed lists
+}
+
+// BookFilter holds the query parameters of the book list. A date that is
+// only partly known counts from its first day, so a book published in 1965
+// is before 1965-06 and not after it. Collapse set to BookCollapseWork
+// keeps one book per work: the first catalogued edition that matches.
+type BookFilter struct {
+	Sort            string      `query:"sort"`
+	Collapse        string      `query:"collapse"`
+	PublishedAfter  PartialDate `query:"published_after"`
+	PublishedBefore PartialDate `query:"published_before"`
+	Year            int         `query:"year"`
+}
+
+// UnparsedDate is a published date kept from before dates were checked
+// that could not be read as one.
+type UnparsedDate struct {
+	BookID uint   `json:"book_id"`
+	Value  string `json:"value"`
+}
+
+// BookMARC is the MARC record a book was imported from, in ISO 2709. It is
+// kept whole, so that exports can give back the fields that have no book
+// field to go to.
+type BookMARC struct {
+	BookID uint   `gorm:"primaryKey;autoIncrement:false"`
+	Record []byte `gorm:"not null"`
+}
+
+// ExportRequest holds the query parameters of a catalog export. Since is an
+// RFC 3339 time.
+type ExportRequest struct {
+	BookFilter
+	Format string `query:"format"`
+	Since  string `query:"since"`
+	Gzip   bool   `query:"gzip"`
 }
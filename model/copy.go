@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+const (
+	CopyStatusAvailable = "available"
+	CopyStatusOnLoan    = "on_loan"
+	CopyStatusLost      = "lost"
+	CopyStatusInRepair  = "in_repair"
+	CopyStatusOnHold    = "on_hold"
+)
+
+// Copy is a physical item of a Book that can be shelved and lent out.
+type Copy struct {
+	ID              uint      `json:"id" gorm:"primaryKey"`
+	BookID          uint      `json:"book_id" gorm:"index"`
+	Barcode         string    `json:"barcode" gorm:"size:64;uniqueIndex"`
+	Condition       string    `json:"condition"`
+	Status          string    `json:"status" gorm:"size:32;index"`
+	AcquisitionDate time.Time `json:"acquisition_date"`
+	Location        string    `json:"location"`
+	Branch          string    `json:"branch" gorm:"size:64"`
+}
+
+// Availability summarizes the copies of a Book by status.
+type Availability struct {
+	Total     int `json:"total"`
+	Available int `json:"available"`
+	OnLoan    int `json:"on_loan"`
+	Lost      int `json:"lost"`
+	InRepair  int `json:"in_repair"`
+	OnHold    int `json:"on_hold"`
+}
+
+// Add counts n copies with the given status.
+func (a *Availability) Add(status string, n int) {
+	a.Total += n
+	switch status {
+	case CopyStatusAvailable:
+		a.Available += n
+	case CopyStatusOnLoan:
+		a.OnLoan += n
+	case CopyStatusLost:
+		a.Lost += n
+	case CopyStatusInRepair:
+		a.InRepair += n
+	case CopyStatusOnHold:
+		a.OnHold += n
+	}
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	CoverSizeOriginal = "original"
+	CoverSizeSmall    = "small"
+	CoverSizeMedium   = "medium"
+	CoverSizeLarge    = "large"
+)
+
+// CoverSizes are the longest sides, in pixels, of the thumbnails made of
+// every cover. Thumbnails are never larger than the original.
+var CoverSizes = map[string]int{
+	CoverSizeSmall:  150,
+	CoverSizeMedium: 300,
+	CoverSizeLarge:  600,
+}
+
+// Cover is the cover image of a book. Version changes with the image, so
+// that the files of each version can be cached for good.
+type Cover struct {
+	BookID        uint      `json:"book_id" gorm:"primaryKey;autoIncrement:false"`
+	Version       string    `json:"version" gorm:"size:16"`
+	ContentType   string    `json:"content_type" gorm:"size:32"`
+	ThumbnailType string    `json:"-" gorm:"size:32"`
+	Width         int       `json:"width"`
+	Height        int       `json:"height"`
+	Size          int64     `json:"size"`
+	URL           string    `json:"url" gorm:"-"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// Key returns the storage key of the cover in a size.
+func (c *Cover) Key(size string) string {
+	return fmt.Sprintf("covers/%d/%s/%s", c.BookID, c.Version, size)
+}
+
+// MediaType returns the content type of the cover in a size.
+func (c *Cover) MediaType(size string) string {
+	if size == CoverSizeOriginal {
+		return c.ContentType
+	}
+	return c.ThumbnailType
+}
+
+// SetURL fills in the address the cover is served from. It names the
+// version, so that it changes when the cover does.
+func (c *Cover) SetURL() {
+	c.URL = fmt.Sprintf("/api/books/%d/cover?v=%s", c.BookID, c.Version)
+}
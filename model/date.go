@@ -0,0 +1,241 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DatePrecisionYear  = "year"
+	DatePrecisionMonth = "month"
+	DatePrecisionDay   = "day"
+)
+
+var ErrInvalidDate = errors.New("date must be YYYY, YYYY-MM or YYYY-MM-DD")
+
+// PartialDate is a date of which only the year, or the year and month, may
+// be known. The zero value is an unknown date. It is written as "1965",
+// "1965-08" or "1965-08-01", and stored as the integer YYYYMMDD with zeros
+// for the parts that are not known, so that dates sort by the first day
+// they can mean.
+type PartialDate struct {
+	Year  int
+	Month int // 0 if only the year is known
+	Day   int // 0 if only the year and month are known
+}
+
+var (
+	isoDate = regexp.MustCompile(`^(\d{4})(?:-(\d{2})(?:-(\d{2}))?)?$`)
+	// The patterns below read the dates of catalog records typed before
+	// dates were checked: year first with any separator, optionally with a
+	// time, and English dates such as "August 1, 1965" or "1 Aug 1965".
+	looseDate      = regexp.MustCompile(`^(\d{4})(?:[-/.](\d{1,2})(?:[-/.](\d{1,2}))?)?(?:[T ]\d.*)?$`)
+	monthFirstDate = regexp.MustCompile(`^([A-Za-z]+)\.?\s+(?:(\d{1,2}),?\s+)?(\d{4})$`)
+	dayFirstDate   = regexp.MustCompile(`^(\d{1,2})\s+([A-Za-z]+)\.?,?\s+(\d{4})$`)
+	// legacyDecoration is what catalogers put around dates: brackets for
+	// dates taken from elsewhere, "c" or "p" for copyright dates and "?"
+	// for guesses.
+	legacyDecoration = regexp.MustCompile(`^(?:ca\.|c|p|©|℗)\s*|[?.]+$`)
+)
+
+// ParsePartialDate reads a date written as YYYY, YYYY-MM or YYYY-MM-DD. An
+// empty string is the zero date.
+func ParsePartialDate(s string) (PartialDate, error) {
+	if s == "" {
+		return PartialDate{}, nil
+	}
+	match := isoDate.FindStringSubmatch(s)
+	if match == nil {
+		return PartialDate{}, fmt.Errorf("%w, not %q", ErrInvalidDate, s)
+	}
+	return newPartialDate(s, match[1], match[2], match[3])
+}
+
+// ParseLegacyDate is ParsePartialDate for free-form dates, as found in old
+// records and imported files. Besides the forms ParsePartialDate reads, it
+// takes "1965/8/1", "1965-08-01T00:00:00Z", "[c1965]", "Aug. 1965",
+// "August 1, 1965" and "1 August 1965". Dates it cannot tell for sure,
+// such as "01/08/1965", are errors.
+func ParseLegacyDate(s string) (PartialDate, error) {
+	s = strings.TrimSpace(s)
+	trimmed := strings.TrimSpace(strings.Trim(s, "[]"))
+	trimmed = strings.TrimSpace(strings.Trim(legacyDecoration.ReplaceAllString(trimmed, ""), "[]"))
+	if match := looseDate.FindStringSubmatch(trimmed); match != nil {
+		return newPartialDate(s, match[1], match[2], match[3])
+	}
+	if match := monthFirstDate.FindStringSubmatch(trimmed); match != nil {
+		if month := monthNumber(match[1]); month != "" {
+			return newPartialDate(s, match[3], month, match[2])
+		}
+	}
+	if match := dayFirstDate.FindStringSubmatch(trimmed); match != nil {
+		if month := monthNumber(match[2]); month != "" {
+			return newPartialDate(s, match[3], month, match[1])
+		}
+	}
+	if s == "" {
+		return PartialDate{}, nil
+	}
+	return PartialDate{}, fmt.Errorf("%w, not %q", ErrInvalidDate, s)
+}
+
+func newPartialDate(s, year, month, day string) (PartialDate, error) {
+	var d PartialDate
+	d.Year, _ = strconv.Atoi(year)
+	d.Month, _ = strconv.Atoi(month)
+	d.Day, _ = strconv.Atoi(day)
+	if !d.valid() {
+		return PartialDate{}, fmt.Errorf("%w, not %q", ErrInvalidDate, s)
+	}
+	return d, nil
+}
+
+// monthNumber returns the number of an English month name or abbreviation,
+// as in "Aug" or "Sept", or "" if name is not one.
+func monthNumber(name string) string {
+	name = strings.ToLower(name)
+	if len(name) < 3 {
+		return ""
+	}
+	for i := time.January; i <= time.December; i++ {
+		if strings.HasPrefix(strings.ToLower(i.String()), name) {
+			return strconv.Itoa(int(i))
+		}
+	}
+	return ""
+}
+
+func (d PartialDate) valid() bool {
+	switch {
+	case d.Year < 1 || d.Year > 9999 || d.Month < 0 || d.Month > 12 || d.Day < 0:
+		return false
+	case d.Day == 0:
+		return true
+	case d.Month == 0:
+		return false
+	}
+	return time.Date(d.Year, time.Month(d.Month), d.Day, 0, 0, 0, 0, time.UTC).Day() == d.Day
+}
+
+func (d PartialDate) IsZero() bool {
+	return d == PartialDate{}
+}
+
+// Precision returns how much of the date is known: DatePrecisionYear,
+// DatePrecisionMonth or DatePrecisionDay, or "" for the zero date.
+func (d PartialDate) Precision() string {
+	switch {
+	case d.IsZero():
+		return ""
+	case d.Month == 0:
+		return DatePrecisionYear
+	case d.Day == 0:
+		return DatePrecisionMonth
+	}
+	return DatePrecisionDay
+}
+
+func (d PartialDate) String() string {
+	switch d.Precision() {
+	case DatePrecisionYear:
+		return fmt.Sprintf("%04d", d.Year)
+	case DatePrecisionMonth:
+		return fmt.Sprintf("%04d-%02d", d.Year, d.Month)
+	case DatePrecisionDay:
+		return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+	}
+	return ""
+}
+
+// SortKey returns the date as it is stored: YYYYMMDD, with zeros for the
+// parts that are not known.
+func (d PartialDate) SortKey() int {
+	return d.Year*10000 + d.Month*100 + d.Day
+}
+
+// EndKey returns the sort key every date after all the days d can mean
+// sorts at or after: 19660000 for 1965, 19650900 for 1965-08.
+func (d PartialDate) EndKey() int {
+	switch d.Precision() {
+	case DatePrecisionYear:
+		return (d.Year + 1) * 10000
+	case DatePrecisionMonth:
+		return d.SortKey() + 100
+	}
+	return d.SortKey() + 1
+}
+
+// MarshalJSON writes the date as a string, or null if it is not known.
+func (d PartialDate) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON takes a string in the form ParsePartialDate reads, or null.
+func (d *PartialDate) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*d = PartialDate{}
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("%w, not %s", ErrInvalidDate, data)
+	}
+	parsed, err := ParsePartialDate(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// UnmarshalParam reads the date from a query parameter.
+func (d *PartialDate) UnmarshalParam(param string) error {
+	parsed, err := ParsePartialDate(param)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+func (PartialDate) GormDataType() string {
+	return "int"
+}
+
+// Value stores the date as its sort key, or NULL if it is not known.
+func (d PartialDate) Value() (driver.Value, error) {
+	if d.IsZero() {
+		return nil, nil
+	}
+	return int64(d.SortKey()), nil
+}
+
+func (d *PartialDate) Scan(value interface{}) error {
+	var key int64
+	switch v := value.(type) {
+	case nil:
+		*d = PartialDate{}
+		return nil
+	case int64:
+		key = v
+	case []byte:
+		n, err := strconv.ParseInt(string(v), 10, 64)
+		if err != nil {
+			return err
+		}
+		key = n
+	default:
+		return fmt.Errorf("cannot read a date from %T", value)
+	}
+	*d = PartialDate{Year: int(key / 10000), Month: int(key / 100 % 100), Day: int(key % 100)}
+	return nil
+}
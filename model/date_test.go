@@ -0,0 +1,117 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePartialDate(t *testing.T) {
+	tests := []struct {
+		input     string
+		expected  PartialDate
+		precision string
+		valid     bool
+	}{
+		{"1965", PartialDate{Year: 1965}, DatePrecisionYear, true},
+		{"1965-08", PartialDate{Year: 1965, Month: 8}, DatePrecisionMonth, true},
+		{"1965-08-01", PartialDate{Year: 1965, Month: 8, Day: 1}, DatePrecisionDay, true},
+		{"2024-02-29", PartialDate{Year: 2024, Month: 2, Day: 29}, DatePrecisionDay, true},
+		{"", PartialDate{}, "", true},
+		{"2023-02-29", PartialDate{}, "", false},
+		{"1965-13", PartialDate{}, "", false},
+		{"0000", PartialDate{}, "", false},
+		{"1965-8-1", PartialDate{}, "", false},
+		{"August 1965", PartialDate{}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			date, err := ParsePartialDate(tt.input)
+			assert.Equal(t, tt.valid, err == nil, err)
+			assert.Equal(t, tt.expected, date)
+			assert.Equal(t, tt.precision, date.Precision())
+			if tt.valid {
+				assert.Equal(t, tt.input, date.String())
+			} else {
+				assert.ErrorIs(t, err, ErrInvalidDate)
+			}
+		})
+	}
+}
+
+func TestParseLegacyDate(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1965", "1965"},
+		{" 1965/8/1 ", "1965-08-01"},
+		{"1965.08", "1965-08"},
+		{"1965-08-01T12:00:00Z", "1965-08-01"},
+		{"1965-08-01 12:00:00", "1965-08-01"},
+		{"[c1965]", "1965"},
+		{"©1965.", "1965"},
+		{"[1965?]", "1965"},
+		{"ca. 1965", "1965"},
+		{"Aug. 1965", "1965-08"},
+		{"August 1, 1965", "1965-08-01"},
+		{"Sept 3 1965", "1965-09-03"},
+		{"1 August 1965", "1965-08-01"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			date, err := ParseLegacyDate(tt.input)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, date.String())
+		})
+	}
+	for _, input := range []string{"01/08/1965", "1960s", "n.d.", "Au 1965", "Smarch 1965", "February 30, 1965", "196"} {
+		_, err := ParseLegacyDate(input)
+		assert.ErrorIs(t, err, ErrInvalidDate, input)
+	}
+}
+
+func TestPartialDateKeys(t *testing.T) {
+	year := PartialDate{Year: 1965}
+	month := PartialDate{Year: 1965, Month: 12}
+	day := PartialDate{Year: 1965, Month: 8, Day: 31}
+	assert.Equal(t, 19650000, year.SortKey())
+	assert.Equal(t, 19660000, year.EndKey())
+	assert.Equal(t, 19651200, month.SortKey())
+	assert.Equal(t, 19651300, month.EndKey())
+	assert.Less(t, month.EndKey(), (PartialDate{Year: 1966}).SortKey())
+	assert.Equal(t, 19650832, day.EndKey())
+	assert.Less(t, day.EndKey(), (PartialDate{Year: 1965, Month: 9}).SortKey())
+}
+
+func TestPartialDateJSON(t *testing.T) {
+	data, err := json.Marshal(struct {
+		Known   PartialDate `json:"known"`
+		Unknown PartialDate `json:"unknown"`
+	}{Known: PartialDate{Year: 1965, Month: 8}})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"known":"1965-08","unknown":null}`, string(data))
+
+	var book Book
+	assert.NoError(t, json.Unmarshal([]byte(`{"published_date":"1965-08-01"}`), &book))
+	assert.Equal(t, PartialDate{Year: 1965, Month: 8, Day: 1}, book.PublishedDate)
+	assert.NoError(t, json.Unmarshal([]byte(`{"published_date":null}`), &book))
+	assert.True(t, book.PublishedDate.IsZero())
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"published_date":"August 1965"}`), &book), ErrInvalidDate)
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"published_date":1965}`), &book), ErrInvalidDate)
+}
+
+func TestPartialDateScan(t *testing.T) {
+	for _, date := range []PartialDate{{Year: 1965}, {Year: 1965, Month: 8}, {Year: 1965, Month: 8, Day: 1}, {}} {
+		value, err := date.Value()
+		assert.NoError(t, err)
+		var scanned PartialDate
+		assert.NoError(t, scanned.Scan(value))
+		assert.Equal(t, date, scanned)
+	}
+	var scanned PartialDate
+	assert.NoError(t, scanned.Scan([]byte("19650801")))
+	assert.Equal(t, PartialDate{Year: 1965, Month: 8, Day: 1}, scanned)
+}
@@ -0,0 +1,48 @@
+package model
+
+const (
+	MergeTarget = "target"
+	MergeSource = "source"
+)
+
+// DuplicateFilter holds the query parameters of the duplicate finder.
+type DuplicateFilter struct {
+	MinScore float64 `query:"min_score"`
+	Limit    int     `query:"limit"`
+}
+
+// DuplicatePair is two books that may be one, the older first, with how
+// alike they are. Score runs from 0 to 1.
+type DuplicatePair struct {
+	Books   [2]Book        `json:"books"`
+	Score   float64        `json:"score"`
+	Matches DuplicateMatch `json:"matches"`
+}
+
+// DuplicateMatch tells how alike each field of two books is, from 0 to 1.
+// Fields one of the books does not have are left out.
+type DuplicateMatch struct {
+	ISBN   *bool    `json:"isbn,omitempty"`
+	Title  float64  `json:"title"`
+	Author *float64 `json:"author,omitempty"`
+	Year   *float64 `json:"year,omitempty"`
+}
+
+// MergeRequest merges the source book into the target. Fields maps the
+// fields of Book.Fields to the side their value is taken from,
+// MergeTarget or MergeSource; fields it leaves out keep the target's
+// value. Non-zero versions must match the books' stored versions.
+type MergeRequest struct {
+	TargetID      uint              `json:"target_id"`
+	SourceID      uint              `json:"source_id"`
+	TargetVersion int               `json:"target_version"`
+	SourceVersion int               `json:"source_version"`
+	Fields        map[string]string `json:"fields"`
+}
+
+// MergeResult is the book two books were merged into, with the number of
+// records of each kind that were moved to it from the other.
+type MergeResult struct {
+	Book  *Book            `json:"book"`
+	Moved map[string]int64 `json:"moved"`
+}
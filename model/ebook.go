@@ -0,0 +1,49 @@
+package model
+
+import "time"
+
+const (
+	EbookFormatEPUB = "epub"
+	EbookFormatPDF  = "pdf"
+)
+
+// Ebook is a file of a book that readers can download. Its contents are
+// stored under their SHA-256, Checksum, and shared by ebooks that have the
+// same file.
+type Ebook struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	BookID    uint      `json:"book_id" gorm:"index"`
+	Format    string    `json:"format" gorm:"size:8"`
+	Filename  string    `json:"filename"`
+	Size      int64     `json:"size"`
+	Checksum  string    `json:"checksum" gorm:"size:64;index"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ContentType returns the media type of the ebook's format.
+func (e *Ebook) ContentType() string {
+	if e.Format == EbookFormatPDF {
+		return "application/pdf"
+	}
+	return "application/epub+zip"
+}
+
+// EbookDownload is an address an ebook can be downloaded from without a
+// token until it expires.
+type EbookDownload struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// EbookUpload is an ebook that has just been added, with a draft of its
+// book as the metadata in the file describes it. The draft is not saved:
+// Changes lists how it differs from the book, for staff to confirm by
+// updating the book. Cover is the book's new cover if the file's cover was
+// taken for it.
+type EbookUpload struct {
+	Ebook
+	Draft   *Book         `json:"draft"`
+	Changes []FieldChange `json:"changes"`
+	Cover   *Cover        `json:"cover,omitempty"`
+}
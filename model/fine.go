@@ -0,0 +1,54 @@
+package model
+
+import "time"
+
+const (
+	FineEntryCharge  = "charge"
+	FineEntryPayment = "payment"
+	FineEntryWaiver  = "waiver"
+
+	FinePaymentPending = "pending"
+	FinePaymentFailed  = "failed"
+)
+
+// FineEntry is one line of a user's fines ledger. Amounts are in cents and
+// always positive: charges add to the balance, payments and waivers take
+// from it. Online payments are pending while the payment provider is
+// charged and failed if it declines; failed payments do not count. Status is
+// empty for settled entries.
+type FineEntry struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"index"`
+	Type      string    `json:"type" gorm:"size:16"`
+	Amount    int64     `json:"amount"`
+	LoanID    *uint     `json:"loan_id,omitempty" gorm:"uniqueIndex"`
+	WaivedID  *uint     `json:"waived_id,omitempty" gorm:"uniqueIndex"`
+	Reason    string    `json:"reason,omitempty"`
+	Reference string    `json:"reference,omitempty"`
+	Status    string    `json:"status,omitempty" gorm:"size:16"`
+	Actor     string    `json:"actor"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// FineAccount is a user's ledger with its balance. Accruing is the fine
+// building up on loans that are overdue but not yet returned.
+type FineAccount struct {
+	UserID   uint        `json:"user_id"`
+	Balance  int64       `json:"balance"`
+	Accruing int64       `json:"accruing"`
+	Blocked  bool        `json:"blocked"`
+	Entries  []FineEntry `json:"entries"`
+}
+
+// PaymentRequest pays off part of a fines balance. Token is handed to the
+// payment provider for online payments.
+type PaymentRequest struct {
+	Amount int64  `json:"amount"`
+	Token  string `json:"token"`
+	Reason string `json:"reason"`
+}
+
+// WaiverRequest cancels a charge.
+type WaiverRequest struct {
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+const (
+	HoldStatusWaiting   = "waiting"
+	HoldStatusReady     = "ready"
+	HoldStatusFulfilled = "fulfilled"
+	HoldStatusCancelled = "cancelled"
+	HoldStatusExpired   = "expired"
+)
+
+// Hold is a user's place in the queue for a Book. Once a copy is returned it
+// is set aside for the oldest waiting hold, which then has until ExpiresAt to
+// pick it up.
+type Hold struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	BookID    uint       `json:"book_id" gorm:"index"`
+	UserID    uint       `json:"user_id" gorm:"index"`
+	Status    string     `json:"status" gorm:"size:32;index"`
+	CopyID    *uint      `json:"copy_id"`
+	CreatedAt time.Time  `json:"created_at"`
+	ReadyAt   *time.Time `json:"ready_at"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	Position  int        `json:"position,omitempty" gorm:"-"`
+}
+
+// Active reports whether the hold is still in the queue or awaiting pickup.
+func (h *Hold) Active() bool {
+	return h.Status == HoldStatusWaiting || h.Status == HoldStatusReady
+}
@@ -0,0 +1,106 @@
+package model
+
+import "time"
+
+const (
+	ImportFormatCSV     = "csv"
+	ImportFormatJSON    = "json"
+	ImportFormatNDJSON  = "ndjson"
+	ImportFormatMARC    = "marc"
+	ImportFormatMARCXML = "marcxml"
+)
+
+const (
+	ImportModeBestEffort = "best_effort"
+	ImportModeAtomic     = "atomic"
+)
+
+const (
+	ImportStatusQueued     = "queued"
+	ImportStatusRunning    = "running"
+	ImportStatusCompleted  = "completed"
+	ImportStatusRolledBack = "rolled_back"
+	ImportStatusFailed     = "failed"
+)
+
+const (
+	ImportRowCreated   = "created"
+	ImportRowUpdated   = "updated"
+	ImportRowUnchanged = "unchanged"
+	ImportRowRejected  = "rejected"
+)
+
+// ImportRequest holds the query parameters of a book import. Mapping lists
+// source columns and the book fields they go to, as in
+// "Writer:author,ISBN13:isbn".
+type ImportRequest struct {
+	Format  string `query:"format"`
+	Mapping string `query:"mapping"`
+	Mode    string `query:"mode"`
+	DryRun  bool   `query:"dry_run"`
+	Upsert  bool   `query:"upsert"`
+	Async   bool   `query:"async"`
+}
+
+// ImportJob is one upload of books and its outcome. Atomic imports and dry
+// runs are only committed when every row is accepted, and dry runs never
+// are; their rows report what would have happened.
+type ImportJob struct {
+	ID         uint        `json:"id" gorm:"primaryKey"`
+	Status     string      `json:"status" gorm:"size:16"`
+	Format     string      `json:"format" gorm:"size:16"`
+	Mode       string      `json:"mode" gorm:"size:16"`
+	DryRun     bool        `json:"dry_run"`
+	Upsert     bool        `json:"upsert"`
+	Committed  bool        `json:"committed"`
+	Actor      string      `json:"actor"`
+	Size       int64       `json:"size"`
+	BytesRead  int64       `json:"bytes_read"`
+	Progress   float64     `json:"progress" gorm:"-"`
+	Processed  int         `json:"processed"`
+	Created    int         `json:"created"`
+	Updated    int         `json:"updated"`
+	Unchanged  int         `json:"unchanged"`
+	Rejected   int         `json:"rejected"`
+	Error      string      `json:"error,omitempty"`
+	CreatedAt  time.Time   `json:"created_at"`
+	FinishedAt *time.Time  `json:"finished_at,omitempty"`
+	Rows       []ImportRow `json:"rows,omitempty" gorm:"foreignKey:JobID"`
+}
+
+// Count adds the outcome of a row to the job's totals.
+func (j *ImportJob) Count(row *ImportRow) {
+	j.Processed++
+	switch row.Action {
+	case ImportRowCreated:
+		j.Created++
+	case ImportRowUpdated:
+		j.Updated++
+	case ImportRowUnchanged:
+		j.Unchanged++
+	case ImportRowRejected:
+		j.Rejected++
+	}
+}
+
+// Transactional reports whether the job's rows are written in a single
+// transaction.
+func (j *ImportJob) Transactional() bool {
+	return j.Mode == ImportModeAtomic || j.DryRun
+}
+
+// ImportRow is the outcome of one record of an import. Rows are numbered
+// from 1 in the order of the upload, not counting a CSV header. MARC holds
+// the record of a MARC import, to be kept with the book.
+type ImportRow struct {
+	ID     uint                   `json:"-" gorm:"primaryKey"`
+	JobID  uint                   `json:"-" gorm:"index:idx_import_rows_job_action"`
+	Number int                    `json:"row"`
+	Action string                 `json:"action" gorm:"size:16;index:idx_import_rows_job_action"`
+	BookID uint                   `json:"book_id,omitempty"`
+	ISBN   string                 `json:"isbn,omitempty"`
+	Title  string                 `json:"title,omitempty"`
+	Reason string                 `json:"reason,omitempty"`
+	Fields map[string]interface{} `json:"-" gorm:"-"`
+	MARC   []byte                 `json:"-" gorm:"-"`
+}
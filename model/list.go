@@ -0,0 +1,59 @@
+package model
+
+import "time"
+
+// Built-in shelves every user has. A book sits on at most one of the
+// to-read, reading and read shelves; favorites is independent of them.
+const (
+	ShelfToRead    = "to_read"
+	ShelfReading   = "reading"
+	ShelfRead      = "read"
+	ShelfFavorites = "favorites"
+)
+
+var Shelves = []string{ShelfToRead, ShelfReading, ShelfRead, ShelfFavorites}
+
+// ReadingList is a user's ordered list of books. Shelf is set for the
+// built-in shelves and nil for custom lists. Public lists can be viewed by
+// anyone holding their ShareToken.
+type ReadingList struct {
+	ID          uint        `json:"id" gorm:"primaryKey"`
+	UserID      uint        `json:"user_id" gorm:"uniqueIndex:idx_reading_lists_user_shelf"`
+	Shelf       *string     `json:"shelf,omitempty" gorm:"size:32;uniqueIndex:idx_reading_lists_user_shelf"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Public      bool        `json:"public"`
+	ShareToken  *string     `json:"share_token,omitempty" gorm:"size:64;uniqueIndex"`
+	ShareURL    string      `json:"share_url,omitempty" gorm:"-"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+	Entries     []ListEntry `json:"entries,omitempty" gorm:"foreignKey:ListID"`
+}
+
+// ListEntry is a book on a ReadingList. Positions start at 1 and have no gaps.
+type ListEntry struct {
+	ID       uint      `json:"id" gorm:"primaryKey"`
+	ListID   uint      `json:"list_id" gorm:"uniqueIndex:idx_list_entries_list_book"`
+	BookID   uint      `json:"book_id" gorm:"uniqueIndex:idx_list_entries_list_book;index"`
+	Position int       `json:"position"`
+	Note     string    `json:"note"`
+	AddedAt  time.Time `json:"added_at" gorm:"autoCreateTime"`
+	Book     *Book     `json:"book,omitempty" gorm:"-"`
+}
+
+// ListMembership tells the caller that a book is on one of their lists.
+type ListMembership struct {
+	ListID   uint    `json:"list_id"`
+	Name     string  `json:"name"`
+	Shelf    *string `json:"shelf,omitempty"`
+	Position int     `json:"position"`
+	Note     string  `json:"note"`
+}
+
+// ListEntryRequest adds a book to a list or moves and annotates an entry. A
+// zero Position appends on add and keeps the current position on update.
+type ListEntryRequest struct {
+	BookID   uint    `json:"book_id"`
+	Position int     `json:"position"`
+	Note     *string `json:"note"`
+}
@@ -0,0 +1,48 @@
+package model
+
+import "time"
+
+const (
+	LoanEventCheckout = "checkout"
+	LoanEventReturn   = "return"
+	LoanEventRenew    = "renew"
+)
+
+// Loan records a Copy being lent to a User.
+type Loan struct {
+	ID           uint        `json:"id" gorm:"primaryKey"`
+	CopyID       uint        `json:"copy_id" gorm:"index"`
+	UserID       uint        `json:"user_id" gorm:"index"`
+	CheckedOutAt time.Time   `json:"checked_out_at"`
+	DueAt        time.Time   `json:"due_at"`
+	ReturnedAt   *time.Time  `json:"returned_at"`
+	RenewalCount int         `json:"renewal_count"`
+	Events       []LoanEvent `json:"events,omitempty"`
+}
+
+// LoanEvent is one entry in the history of a Loan.
+type LoanEvent struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	LoanID    uint      `json:"loan_id" gorm:"index"`
+	Type      string    `json:"type" gorm:"size:32"`
+	Actor     string    `json:"actor"`
+	Note      string    `json:"note,omitempty"`
+	DueAt     time.Time `json:"due_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CheckoutRequest identifies the copy and borrower of a new Loan. The copy
+// can be given either by ID or by barcode.
+type CheckoutRequest struct {
+	CopyID  uint       `json:"copy_id"`
+	Barcode string     `json:"barcode"`
+	UserID  uint       `json:"user_id"`
+	DueAt   *time.Time `json:"due_at"`
+}
+
+// RenewRequest asks for a loan to be extended. Supervisors can force a
+// renewal past the policy limit or an outstanding hold, giving a reason.
+type RenewRequest struct {
+	Force  bool   `json:"force"`
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,80 @@
+package model
+
+import "time"
+
+const (
+	PolicySourceDatabase = "database"
+	PolicySourceConfig   = "config"
+	PolicySourceDefault  = "default"
+)
+
+// LoanPolicy is one row of the loan rule table. An empty Role, MaterialType
+// or Branch matches any value. Fines are in cents.
+type LoanPolicy struct {
+	ID             uint   `json:"id" gorm:"primaryKey"`
+	Role           string `json:"role" gorm:"size:32"`
+	MaterialType   string `json:"material_type" gorm:"size:32"`
+	Branch         string `json:"branch" gorm:"size:64"`
+	LoanPeriodDays int    `json:"loan_period_days"`
+	MaxRenewals    int    `json:"max_renewals"`
+	MaxLoans       int    `json:"max_loans"`
+	GraceDays      int    `json:"grace_days"`
+	DailyFine      int64  `json:"daily_fine"`
+	MaxFine        int64  `json:"max_fine"`
+}
+
+// Matches reports whether the rule applies to the given borrower role,
+// material type and branch.
+func (p *LoanPolicy) Matches(role, materialType, branch string) bool {
+	return (p.Role == "" || p.Role == role) &&
+		(p.MaterialType == "" || p.MaterialType == materialType) &&
+		(p.Branch == "" || p.Branch == branch)
+}
+
+// PolicyDecision is the policy that applies to a borrower and a copy, and
+// which rule it came from.
+type PolicyDecision struct {
+	Role           string `json:"role"`
+	MaterialType   string `json:"material_type"`
+	Branch         string `json:"branch"`
+	LoanPeriodDays int    `json:"loan_period_days"`
+	MaxRenewals    int    `json:"max_renewals"`
+	MaxLoans       int    `json:"max_loans"`
+	GraceDays      int    `json:"grace_days"`
+	DailyFine      int64  `json:"daily_fine"`
+	MaxFine        int64  `json:"max_fine"`
+	Source         string `json:"source"`
+	RuleID         uint   `json:"rule_id,omitempty"`
+}
+
+// LoanPeriod returns the loan period as a duration.
+func (d *PolicyDecision) LoanPeriod() time.Duration {
+	return time.Duration(d.LoanPeriodDays) * 24 * time.Hour
+}
+
+// Fine returns the overdue fine for a loan due at dueAt and returned at
+// returnedAt. Nothing is owed within the grace period; past it, every
+// started day since the due date is charged, up to MaxFine when set.
+func (d *PolicyDecision) Fine(dueAt, returnedAt time.Time) int64 {
+	late := returnedAt.Sub(dueAt)
+	if late <= time.Duration(d.GraceDays)*24*time.Hour {
+		return 0
+	}
+	days := int64((late + 24*time.Hour - 1) / (24 * time.Hour))
+	fine := days * d.DailyFine
+	if d.MaxFine > 0 && fine > d.MaxFine {
+		fine = d.MaxFine
+	}
+	return fine
+}
+
+// PolicyDryRun answers what would happen if a user borrowed a copy now.
+type PolicyDryRun struct {
+	UserID      uint            `json:"user_id"`
+	CopyID      uint            `json:"copy_id"`
+	Policy      *PolicyDecision `json:"policy"`
+	ActiveLoans int             `json:"active_loans"`
+	DueAt       time.Time       `json:"due_at"`
+	Allowed     bool            `json:"allowed"`
+	Reason      string          `json:"reason,omitempty"`
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicyDecisionFine(t *testing.T) {
+	day := 24 * time.Hour
+	due := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	policy := &PolicyDecision{GraceDays: 2, DailyFine: 25, MaxFine: 200}
+
+	tests := []struct {
+		name       string
+		returnedAt time.Time
+		expected   int64
+	}{
+		{"Returned early", due.Add(-day), 0},
+		{"Returned on time", due, 0},
+		{"Within grace period", due.Add(2 * day), 0},
+		{"Past grace charges from due date", due.Add(2*day + time.Hour), 75},
+		{"Capped", due.Add(30 * day), 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, policy.Fine(due, tt.returnedAt))
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package model
+
+import "time"
+
+// ReadingProgress is one read-through of a Book by a user. A user has at most
+// one unfinished read-through of a book at a time; reading a finished book
+// again starts a new one.
+type ReadingProgress struct {
+	ID         uint             `json:"id" gorm:"primaryKey"`
+	UserID     uint             `json:"user_id" gorm:"index:idx_reading_progress_user_book"`
+	BookID     uint             `json:"book_id" gorm:"index:idx_reading_progress_user_book"`
+	Page       int              `json:"page"`
+	TotalPages int              `json:"total_pages"`
+	Percent    float64          `json:"percent"`
+	StartedAt  time.Time        `json:"started_at"`
+	FinishedAt *time.Time       `json:"finished_at" gorm:"index"`
+	UpdatedAt  time.Time        `json:"updated_at"`
+	Updates    []ProgressUpdate `json:"updates,omitempty" gorm:"foreignKey:ProgressID"`
+}
+
+// ProgressUpdate records where a user was in a read-through at some point.
+type ProgressUpdate struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	ProgressID uint      `json:"progress_id" gorm:"index"`
+	Page       int       `json:"page"`
+	Percent    float64   `json:"percent"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ProgressRequest reports progress on a book by page or by percentage. Once
+// Finished is set, or the last page or 100% is reached, the read-through is
+// closed at FinishedAt, or now if that is not given.
+type ProgressRequest struct {
+	Page       *int       `json:"page"`
+	TotalPages *int       `json:"total_pages"`
+	Percent    *float64   `json:"percent"`
+	StartedAt  *time.Time `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+	Finished   bool       `json:"finished"`
+}
+
+// ReadingGoal is the number of books a user means to finish in a year.
+type ReadingGoal struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"uniqueIndex:idx_reading_goals_user_year"`
+	Year      int       `json:"year" gorm:"uniqueIndex:idx_reading_goals_user_year"`
+	Target    int       `json:"target"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// YearSummary is a user's reading in one year measured against their goal.
+// Expected is how many books should be finished by now to stay on track.
+type YearSummary struct {
+	Year      int               `json:"year"`
+	Target    int               `json:"target"`
+	Finished  int               `json:"finished"`
+	Remaining int               `json:"remaining"`
+	Percent   float64           `json:"percent"`
+	Expected  int               `json:"expected,omitempty"`
+	OnTrack   bool              `json:"on_track"`
+	Books     []ReadingProgress `json:"books"`
+}
+
+// ReadingReport holds library-wide reading statistics for a year. It never
+// names users, and books only appear in PopularBooks once at least
+// MinReaders different users have finished them.
+type ReadingReport struct {
+	Year                int             `json:"year"`
+	Readers             int             `json:"readers"`
+	Started             int             `json:"started"`
+	Finished            int             `json:"finished"`
+	FinishedByMonth     [12]int         `json:"finished_by_month"`
+	AverageDaysToFinish float64         `json:"average_days_to_finish"`
+	GoalsSet            int             `json:"goals_set"`
+	GoalsMet            int             `json:"goals_met"`
+	AverageTarget       float64         `json:"average_target"`
+	MinReaders          int             `json:"min_readers"`
+	PopularBooks        []BookReadCount `json:"popular_books"`
+}
+
+type BookReadCount struct {
+	BookID  uint   `json:"book_id"`
+	Title   string `json:"title"`
+	Readers int    `json:"readers"`
+}
@@ -0,0 +1,78 @@
+package model
+
+import "time"
+
+const (
+	ReviewStatusPublished = "published"
+	ReviewStatusHidden    = "hidden"
+	ReviewStatusPending   = "pending"
+)
+
+const (
+	ModerationActionHold    = "hold"
+	ModerationActionApprove = "approve"
+	ModerationActionHide    = "hide"
+	ModerationActionBan     = "ban"
+)
+
+// Review is a user's star rating and comment on a Book. Each user can review
+// a book once.
+type Review struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	BookID    uint      `json:"book_id" gorm:"uniqueIndex:idx_reviews_book_user"`
+	UserID    uint      `json:"user_id" gorm:"uniqueIndex:idx_reviews_book_user"`
+	Rating    int       `json:"rating"`
+	Text      string    `json:"text" gorm:"type:text"`
+	Status    string    `json:"status" gorm:"size:16;index"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RatingStats aggregates the published reviews of a Book.
+type RatingStats struct {
+	Average float64 `json:"average"`
+	Count   int     `json:"count"`
+}
+
+// ReviewReport is a user flagging a review for moderation.
+type ReviewReport struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	ReviewID  uint      `json:"review_id" gorm:"uniqueIndex:idx_review_reports_review_user"`
+	UserID    uint      `json:"user_id" gorm:"uniqueIndex:idx_review_reports_review_user"`
+	Reason    string    `json:"reason"`
+	Resolved  bool      `json:"resolved" gorm:"index"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ModerationLog records a moderation decision on a review, made either by a
+// supervisor or by the automatic filter.
+type ModerationLog struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	ReviewID  uint      `json:"review_id" gorm:"index"`
+	Action    string    `json:"action" gorm:"size:16"`
+	Actor     string    `json:"actor"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ReviewBan stops a user from writing reviews.
+type ReviewBan struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"uniqueIndex"`
+	Actor     string    `json:"actor"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ModerationItem is a review waiting for a moderator, with the reports
+// filed against it.
+type ModerationItem struct {
+	Review  Review         `json:"review"`
+	Reports []ReviewReport `json:"reports"`
+}
+
+// ModerationDecision is a moderator's verdict on a review.
+type ModerationDecision struct {
+	Action string `json:"action"`
+	Reason string `json:"reason"`
+}
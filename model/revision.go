@@ -0,0 +1,135 @@
+package model
+
+import (
+	"reflect"
+	"time"
+)
+
+const (
+	RevisionCreate  = "create"
+	RevisionUpdate  = "update"
+	RevisionDelete  = "delete"
+	RevisionRestore = "restore"
+	RevisionRevert  = "revert"
+)
+
+// BookRevision is one entry in the history of a Book. Revisions are numbered
+// from 1 per book. Snapshot holds the book's fields as they were after the
+// change, so that any revision can be reverted to.
+type BookRevision struct {
+	ID        uint                   `json:"id" gorm:"primaryKey"`
+	BookID    uint                   `json:"book_id" gorm:"uniqueIndex:idx_book_revisions_book_revision"`
+	Revision  int                    `json:"revision" gorm:"uniqueIndex:idx_book_revisions_book_revision"`
+	Action    string                 `json:"action" gorm:"size:16"`
+	Actor     string                 `json:"actor"`
+	Note      string                 `json:"note,omitempty"`
+	Changes   []FieldChange          `json:"changes" gorm:"serializer:json;type:text"`
+	Snapshot  map[string]interface{} `json:"snapshot" gorm:"serializer:json;type:text"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// UserRevision is one entry in the history of a User. Unlike books, only
+// the changes are kept.
+type UserRevision struct {
+	ID        uint          `json:"id" gorm:"primaryKey"`
+	UserID    uint          `json:"user_id" gorm:"uniqueIndex:idx_user_revisions_user_revision"`
+	Revision  int           `json:"revision" gorm:"uniqueIndex:idx_user_revisions_user_revision"`
+	Action    string        `json:"action" gorm:"size:16"`
+	Actor     string        `json:"actor"`
+	Note      string        `json:"note,omitempty"`
+	Changes   []FieldChange `json:"changes" gorm:"serializer:json;type:text"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+// FieldChange is the old and new value of one field in a revision.
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// Fields returns the fields of the book that are kept in its history, keyed
+// by their JSON names.
+func (b *Book) Fields() map[string]interface{} {
+	return map[string]interface{}{
+		"title":          b.Title,
+		"author":         b.Author,
+		"isbn":           b.ISBN,
+		"published_date": b.PublishedDate.String(),
+		"material_type":  b.MaterialType,
+	}
+}
+
+// SetFields is the inverse of Fields. Fields missing from the map are left
+// as they are, and so is the published date if it cannot be read; callers
+// that take dates from users check them first.
+func (b *Book) SetFields(fields map[string]interface{}) {
+	set := func(name string, dst *string) {
+		if v, ok := fields[name].(string); ok {
+			*dst = v
+		}
+	}
+	set("title", &b.Title)
+	set("author", &b.Author)
+	set("isbn", &b.ISBN)
+	if v, ok := fields["published_date"].(string); ok {
+		if date, err := ParseLegacyDate(v); err == nil {
+			b.PublishedDate = date
+		}
+	}
+	set("material_type", &b.MaterialType)
+}
+
+// Fields returns the fields of the user that are kept in its history, keyed
+// by their JSON names.
+func (u *User) Fields() map[string]interface{} {
+	return map[string]interface{}{
+		"username": u.Username,
+		"role":     u.Role,
+	}
+}
+
+// SetFields is the inverse of Fields. Fields missing from the map are left
+// as they are.
+func (u *User) SetFields(fields map[string]interface{}) {
+	if v, ok := fields["username"].(string); ok {
+		u.Username = v
+	}
+	if v, ok := fields["role"].(string); ok {
+		u.Role = v
+	}
+}
+
+// DiffFields lists the fields of a book whose values differ between two
+// snapshots, in a stable order. A field missing from before counts as
+// unchanged while it is still empty.
+func DiffFields(before, after map[string]interface{}) []FieldChange {
+	return diffFields(bookFieldOrder, before, after)
+}
+
+// DiffUserFields is DiffFields for users.
+func DiffUserFields(before, after map[string]interface{}) []FieldChange {
+	return diffFields(userFieldOrder, before, after)
+}
+
+func diffFields(order []string, before, after map[string]interface{}) []FieldChange {
+	changes := []FieldChange{}
+	for _, field := range order {
+		if _, ok := before[field]; !ok && isEmpty(after[field]) {
+			continue
+		}
+		if !reflect.DeepEqual(before[field], after[field]) {
+			changes = append(changes, FieldChange{Field: field, Old: before[field], New: after[field]})
+		}
+	}
+	return changes
+}
+
+var (
+	bookFieldOrder = []string{"title", "author", "isbn", "published_date", "material_type"}
+	userFieldOrder = []string{"username", "role"}
+)
+
+func isEmpty(v interface{}) bool {
+	return v == nil || reflect.ValueOf(v).IsZero()
+}
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffFields(t *testing.T) {
+	before := &Book{Title: "Dune", Author: "Herbert", ISBN: "1"}
+	after := &Book{Title: "Dune", Author: "Frank Herbert", ISBN: "1"}
+
+	assert.Equal(t, []FieldChange{{Field: "author", Old: "Herbert", New: "Frank Herbert"}}, DiffFields(before.Fields(), after.Fields()))
+	assert.Empty(t, DiffFields(after.Fields(), after.Fields()))
+
+	created := DiffFields(map[string]interface{}{}, before.Fields())
+	assert.Equal(t, []FieldChange{
+		{Field: "title", Old: nil, New: "Dune"},
+		{Field: "author", Old: nil, New: "Herbert"},
+		{Field: "isbn", Old: nil, New: "1"},
+	}, created)
+}
+
+func TestSetFields(t *testing.T) {
+	book := &Book{ID: 3, Title: "Draft", Author: "Someone"}
+	book.SetFields(map[string]interface{}{"title": "Final", "isbn": "42"})
+
+	assert.Equal(t, &Book{ID: 3, Title: "Final", Author: "Someone", ISBN: "42"}, book)
+}
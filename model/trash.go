@@ -0,0 +1,7 @@
+package model
+
+// Trash lists the books and users that have been deleted but not yet purged.
+type Trash struct {
+	Books []Book `json:"books"`
+	Users []User `json:"users"`
+}
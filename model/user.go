@@ -1,8 +1,12 @@
 package model
 
+import "gorm.io/gorm"
+
 type User struct {
-	ID       uint   `json:"id" gorm:"primaryKey"`
-	Username string `json:"username" gorm:"unique"`
-	Password string `json:"-"`
-	Role     string `json:"role"`
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	Username  string         `json:"username" gorm:"unique"`
+	Password  string         `json:"-"`
+	Role      string         `json:"role"`
+	Version   int            `json:"version" gorm:"not null;default:1"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
 }
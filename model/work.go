@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+// BookCollapseWork collapses a book list to one book per work.
+const BookCollapseWork = "work"
+
+// Work is what the editions and translations of a book have in common.
+// Its editions are the books that refer to it; a book is an edition of at
+// most one work. A work may have a numbered place in a Series.
+type Work struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	Title          string    `json:"title"`
+	Author         string    `json:"author"`
+	SeriesID       *uint     `json:"series_id" gorm:"index"`
+	SeriesPosition *float64  `json:"series_position"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	Editions       []Book    `json:"editions,omitempty" gorm:"foreignKey:WorkID"`
+	Previous       *Work     `json:"previous,omitempty" gorm:"-"`
+	Next           *Work     `json:"next,omitempty" gorm:"-"`
+}
+
+// Series is an ordered run of works. Works are ordered by their position
+// in the series, which need not be a whole number, so that a novella can
+// go between books 1 and 2 as 1.5.
+type Series struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Works       []Work    `json:"works,omitempty" gorm:"foreignKey:SeriesID"`
+}
+
+// EditionRequest adds a book to a work.
+type EditionRequest struct {
+	BookID uint `json:"book_id"`
+}
+
+// SeriesEntryRequest places a work in a series.
+type SeriesEntryRequest struct {
+	Position *float64 `json:"position"`
+}
@@ -1,19 +1,41 @@
 package repository
 
 import (
+	"errors"
+	"fmt"
+	"time"
+
 	"go.test/model"
+	util "go.test/utils"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type BookRepository interface {
-	GetAll() ([]model.Book, error)
+	GetAll(filter *model.BookFilter) ([]model.Book, error)
+	Export(filter *model.BookFilter, since time.Time, each func(book *model.Book) error) error
 	GetByID(id uint) (*model.Book, error)
-	Create(book *model.Book) error
-	Update(book *model.Book) error
-	Delete(id uint) error
+	GetByIDs(ids []uint) ([]model.Book, error)
+	Create(book *model.Book, actor string) error
+	Update(book *model.Book, actor string) error
+	Patch(id uint, version int, actor, note string, apply func(book *model.Book) error) (*model.Book, error)
+	Delete(id uint, version int, actor string) error
+	GetDeleted() ([]model.Book, error)
+	Restore(id uint, actor string) error
+	Purge(id uint) (coverKeys, checksums []string, err error)
+	GetRevisions(bookID uint) ([]model.BookRevision, error)
+	Revert(bookID uint, revision int, actor string) (*model.Book, error)
+	MigratePublishedDates() ([]model.UnparsedDate, error)
+	MigrateISBNKeys() error
+	Merge(targetID, sourceID uint, targetVersion, sourceVersion int, actor string, apply func(target, source *model.Book) error) (*model.Book, map[string]int64, error)
 }
 
+var (
+	ErrBookHasCopies   = errors.New("book still has copies")
+	ErrVersionMismatch = errors.New("the record has been changed since it was read")
+)
+
 type bookRepository struct {
 	db *gorm.DB
 }
@@ -22,14 +44,96 @@ func NewBookRepository(db *gorm.DB) BookRepository {
 	return &bookRepository{db}
 }
 
-func (r *bookRepository) GetAll() ([]model.Book, error) {
+func (r *bookRepository) GetAll(filter *model.BookFilter) ([]model.Book, error) {
 	var books []model.Book
-	if err := r.db.Find(&books).Error; err != nil {
+	if err := r.listQuery(filter).Find(&books).Error; err != nil {
 		return nil, err
 	}
 	return books, nil
 }
 
+// Export calls each with the books that match filter, one at a time, with
+// the MARC records they were imported from. The books are read through a
+// cursor, so the catalog does not have to fit in memory. A non-zero since
+// leaves out books last changed before then.
+func (r *bookRepository) Export(filter *model.BookFilter, since time.Time, each func(book *model.Book) error) error {
+	query := withMARC(r.listQuery(filter))
+	if !since.IsZero() {
+		query = query.Where("books.updated_at >= ?", since)
+	}
+	rows, err := query.Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var book model.Book
+		if err := r.db.ScanRows(rows, &book); err != nil {
+			return err
+		}
+		if err := each(&book); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// withMARC adds the MARC record of each book to a query of books.
+func withMARC(query *gorm.DB) *gorm.DB {
+	return query.
+		Select("books.*, book_marcs.record AS marc").
+		Joins("LEFT JOIN book_marcs ON book_marcs.book_id = books.id")
+}
+
+// listQuery selects the books that match filter, in the order it asks for.
+func (r *bookRepository) listQuery(filter *model.BookFilter) *gorm.DB {
+	query := r.db.Model(&model.Book{})
+	switch filter.Sort {
+	case model.BookSortTitle:
+		query = query.Order("books.title, books.id")
+	case model.BookSortRating:
+		ratings := r.db.Model(&model.Review{}).
+			Select("book_id, AVG(rating) AS average_rating").
+			Where("status = ?", model.ReviewStatusPublished).
+			Group("book_id")
+		query = query.Select("books.*").
+			Joins("LEFT JOIN (?) AS ratings ON ratings.book_id = books.id", ratings).
+			Order("COALESCE(ratings.average_rating, 0) DESC, books.id")
+	case model.BookSortPublished:
+		query = query.Order("books.published_on IS NULL, books.published_on, books.id")
+	default:
+		query = query.Order("books.id")
+	}
+	query = filterBooks(query, filter)
+	if filter.Collapse == model.BookCollapseWork {
+		// Each work is represented by the first of its editions that
+		// match, counting how many do.
+		editions := filterBooks(r.db.Model(&model.Book{}), filter).
+			Select("MIN(books.id) AS id, COUNT(*) AS edition_count").
+			Where("books.work_id IS NOT NULL").
+			Group("books.work_id")
+		query = query.Select("books.*, COALESCE(editions.edition_count, 1) AS edition_count").
+			Joins("LEFT JOIN (?) AS editions ON editions.id = books.id", editions).
+			Where("books.work_id IS NULL OR editions.id IS NOT NULL")
+	}
+	return query
+}
+
+// filterBooks restricts a query of books to those that match filter.
+func filterBooks(query *gorm.DB, filter *model.BookFilter) *gorm.DB {
+	if !filter.PublishedAfter.IsZero() {
+		query = query.Where("books.published_on >= ?", filter.PublishedAfter.EndKey())
+	}
+	if !filter.PublishedBefore.IsZero() {
+		query = query.Where("books.published_on < ?", filter.PublishedBefore.SortKey())
+	}
+	if filter.Year != 0 {
+		year := model.PartialDate{Year: filter.Year}
+		query = query.Where("books.published_on >= ? AND books.published_on < ?", year.SortKey(), year.EndKey())
+	}
+	return query
+}
+
 func (r *bookRepository) GetByID(id uint) (*model.Book, error) {
 	var book model.Book
 	if err := r.db.First(&book, id).Error; err != nil {
@@ -38,14 +142,477 @@ func (r *bookRepository) GetByID(id uint) (*model.Book, error) {
 	return &book, nil
 }
 
-func (r *bookRepository) Create(book *model.Book) error {
-	return r.db.Create(book).Error
+// GetByIDs returns the books with the given IDs, in that order, with the
+// MARC records they were imported from. It fails with
+// gorm.ErrRecordNotFound unless every book is found.
+func (r *bookRepository) GetByIDs(ids []uint) ([]model.Book, error) {
+	var books []model.Book
+	if err := withMARC(r.db.Model(&model.Book{})).Where("books.id IN ?", ids).Find(&books).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]model.Book, len(books))
+	for _, book := range books {
+		byID[book.ID] = book
+	}
+	ordered := make([]model.Book, 0, len(ids))
+	for _, id := range ids {
+		book, ok := byID[id]
+		if !ok {
+			return nil, gorm.ErrRecordNotFound
+		}
+		ordered = append(ordered, book)
+	}
+	return ordered, nil
 }
 
-func (r *bookRepository) Update(book *model.Book) error {
-	return r.db.Save(book).Error
+// Create adds a book and records it as its first revision.
+func (r *bookRepository) Create(book *model.Book, actor string) error {
+	book.Version = 1
+	book.ISBNKey = isbnKey(book.ISBN)
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(book).Error; err != nil {
+			return err
+		}
+		return recordRevision(tx, book.ID, model.RevisionCreate, actor, "", map[string]interface{}{}, book.Fields())
+	})
 }
 
-func (r *bookRepository) Delete(id uint) error {
-	return r.db.Delete(&model.Book{}, id).Error
+// Update saves a book and records the fields that changed. Saving a book
+// without changes leaves no revision. A non-zero book.Version must match the
+// stored version; on success it is set to the new one.
+func (r *bookRepository) Update(book *model.Book, actor string) error {
+	updated, err := r.Patch(book.ID, book.Version, actor, "", func(current *model.Book) error {
+		current.SetFields(book.Fields())
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	book.Version = updated.Version
+	return nil
+}
+
+// Patch changes a book with apply while its row is locked, so that apply
+// sees the book as it is stored. The change is recorded like an update,
+// with note, and a non-zero version must match the stored version.
+func (r *bookRepository) Patch(id uint, version int, actor, note string, apply func(book *model.Book) error) (*model.Book, error) {
+	var book *model.Book
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		current, err := lockBook(tx, id)
+		if err != nil {
+			return err
+		}
+		if version != 0 && version != current.Version {
+			return ErrVersionMismatch
+		}
+		before := current.Fields()
+		if err := apply(current); err != nil {
+			return err
+		}
+		if err := updateBook(tx, current, version, bookColumns(current)); err != nil {
+			return err
+		}
+		book = current
+		return recordRevision(tx, id, model.RevisionUpdate, actor, note, before, current.Fields())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return book, nil
+}
+
+// Delete moves a book to the trash. A non-zero version must match the stored
+// version.
+func (r *bookRepository) Delete(id uint, version int, actor string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		current, err := lockBook(tx, id)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := updateBook(tx, current, version, map[string]interface{}{"deleted_at": time.Now()}); err != nil {
+			return err
+		}
+		return recordRevision(tx, id, model.RevisionDelete, actor, "", current.Fields(), current.Fields())
+	})
+}
+
+// GetDeleted returns the books in the trash, most recently deleted first.
+func (r *bookRepository) GetDeleted() ([]model.Book, error) {
+	var books []model.Book
+	if err := r.db.Unscoped().Where("deleted_at IS NOT NULL").Order("deleted_at DESC, id").Find(&books).Error; err != nil {
+		return nil, err
+	}
+	return books, nil
+}
+
+// Restore takes a book out of the trash.
+func (r *bookRepository) Restore(id uint, actor string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var book model.Book
+		err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("deleted_at IS NOT NULL").
+			First(&book, id).Error
+		if err != nil {
+			return err
+		}
+		if err := updateBook(tx, &book, 0, map[string]interface{}{"deleted_at": nil}); err != nil {
+			return err
+		}
+		return recordRevision(tx, id, model.RevisionRestore, actor, "", book.Fields(), book.Fields())
+	})
+}
+
+// Purge permanently deletes a book in the trash together with its holds,
+// reviews, list entries, reading progress, history, MARC record, cover and
+// ebooks. Books that still have copies are kept so that their loan history
+// stays intact. It returns the storage keys of the cover images and the
+// checksums of the ebook files, which are for the caller to delete once the
+// rows are gone.
+func (r *bookRepository) Purge(id uint) (coverKeys, checksums []string, err error) {
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		var book model.Book
+		err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("deleted_at IS NOT NULL").
+			First(&book, id).Error
+		if err != nil {
+			return err
+		}
+		var copies int64
+		if err := tx.Model(&model.Copy{}).Where("book_id = ?", id).Count(&copies).Error; err != nil {
+			return err
+		}
+		if copies > 0 {
+			return ErrBookHasCopies
+		}
+
+		reviews := func() *gorm.DB {
+			return tx.Model(&model.Review{}).Select("id").Where("book_id = ?", id)
+		}
+		if err := tx.Where("review_id IN (?)", reviews()).Delete(&model.ReviewReport{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("review_id IN (?)", reviews()).Delete(&model.ModerationLog{}).Error; err != nil {
+			return err
+		}
+		progress := tx.Model(&model.ReadingProgress{}).Select("id").Where("book_id = ?", id)
+		if err := tx.Where("progress_id IN (?)", progress).Delete(&model.ProgressUpdate{}).Error; err != nil {
+			return err
+		}
+		var covers []model.Cover
+		if err := tx.Where("book_id = ?", id).Find(&covers).Error; err != nil {
+			return err
+		}
+		for _, cover := range covers {
+			coverKeys = append(coverKeys, cover.Key(model.CoverSizeOriginal))
+			for size := range model.CoverSizes {
+				coverKeys = append(coverKeys, cover.Key(size))
+			}
+		}
+		if err := tx.Model(&model.Ebook{}).Where("book_id = ?", id).Distinct().Pluck("checksum", &checksums).Error; err != nil {
+			return err
+		}
+		owned := []interface{}{&model.Review{}, &model.Hold{}, &model.ListEntry{}, &model.ReadingProgress{}, &model.BookRevision{}, &model.BookMARC{}, &model.Cover{}, &model.Ebook{}}
+		for _, record := range owned {
+			if err := tx.Where("book_id = ?", id).Delete(record).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Unscoped().Delete(&book).Error
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return coverKeys, checksums, nil
+}
+
+// Merge merges one book into another. apply sets the target's fields from
+// the two books; then the source's copies, and with them its loans, its
+// holds, reviews, list entries, read-throughs, ebooks and MARC record move
+// to the target, and the source goes to the trash. Records that would clash
+// with the target's, such as a second review by the same user, stay with
+// the source, and waiting holds of users who already wait for the target
+// are cancelled. A target that is not an edition of a work becomes an
+// edition of the source's. Non-zero versions must match the stored versions.
+func (r *bookRepository) Merge(targetID, sourceID uint, targetVersion, sourceVersion int, actor string, apply func(target, source *model.Book) error) (*model.Book, map[string]int64, error) {
+	var merged *model.Book
+	moved := make(map[string]int64)
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Books are locked in the order of their IDs, so that merges of
+		// the same two books cannot deadlock.
+		ids := []uint{targetID, sourceID}
+		if targetID > sourceID {
+			ids[0], ids[1] = sourceID, targetID
+		}
+		locked := make(map[uint]*model.Book, 2)
+		for _, id := range ids {
+			book, err := lockBook(tx, id)
+			if err != nil {
+				return err
+			}
+			locked[id] = book
+		}
+		target, source := locked[targetID], locked[sourceID]
+		if (targetVersion != 0 && targetVersion != target.Version) || (sourceVersion != 0 && sourceVersion != source.Version) {
+			return ErrVersionMismatch
+		}
+		before := target.Fields()
+		if err := apply(target, source); err != nil {
+			return err
+		}
+		columns := bookColumns(target)
+		if target.WorkID == nil && source.WorkID != nil {
+			target.WorkID = source.WorkID
+			columns["work_id"] = source.WorkID
+		}
+		if err := updateBook(tx, target, 0, columns); err != nil {
+			return err
+		}
+		if err := recordRevision(tx, target.ID, model.RevisionUpdate, actor, fmt.Sprintf("merged book %d", source.ID), before, target.Fields()); err != nil {
+			return err
+		}
+		if err := moveRecords(tx, source.ID, target.ID, moved); err != nil {
+			return err
+		}
+		if err := updateBook(tx, source, 0, map[string]interface{}{"deleted_at": time.Now()}); err != nil {
+			return err
+		}
+		merged = target
+		return recordRevision(tx, source.ID, model.RevisionDelete, actor, fmt.Sprintf("merged into book %d", target.ID), source.Fields(), source.Fields())
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return merged, moved, nil
+}
+
+// moveRecords moves the records of one book to another for Merge, counting
+// them in moved by kind.
+func moveRecords(tx *gorm.DB, from, to uint, moved map[string]int64) error {
+	// taken selects a column of the target's records. MySQL cannot update a
+	// table that a subquery reads from, so the subquery reads from a
+	// derived table instead.
+	taken := func(record interface{}, column string, conditions ...interface{}) *gorm.DB {
+		query := tx.Model(record).Select(column).Where("book_id = ?", to)
+		if len(conditions) > 0 {
+			query = query.Where(conditions[0], conditions[1:]...)
+		}
+		return tx.Table("(?) AS taken", query).Select(column)
+	}
+	active := []string{model.HoldStatusWaiting, model.HoldStatusReady}
+	err := tx.Model(&model.Hold{}).
+		Where("book_id = ? AND status = ?", from, model.HoldStatusWaiting).
+		Where("user_id IN (?)", taken(&model.Hold{}, "user_id", "status IN ?", active)).
+		Update("status", model.HoldStatusCancelled).Error
+	if err != nil {
+		return err
+	}
+	moves := []struct {
+		kind   string
+		record interface{}
+		clash  *gorm.DB
+	}{
+		{"copies", &model.Copy{}, nil},
+		{"holds", &model.Hold{}, nil},
+		{"reviews", &model.Review{}, tx.Where("user_id NOT IN (?)", taken(&model.Review{}, "user_id"))},
+		{"list_entries", &model.ListEntry{}, tx.Where("list_id NOT IN (?)", taken(&model.ListEntry{}, "list_id"))},
+		{"reading_progress", &model.ReadingProgress{}, tx.Where("finished_at IS NOT NULL OR user_id NOT IN (?)", taken(&model.ReadingProgress{}, "user_id", "finished_at IS NULL"))},
+		{"ebooks", &model.Ebook{}, nil},
+		{"marc", &model.BookMARC{}, tx.Where("NOT EXISTS (?)", taken(&model.BookMARC{}, "book_id"))},
+	}
+	for _, move := range moves {
+		query := tx.Model(move.record).Where("book_id = ?", from)
+		if move.clash != nil {
+			query = query.Where(move.clash)
+		}
+		result := query.Update("book_id", to)
+		if result.Error != nil {
+			return result.Error
+		}
+		moved[move.kind] = result.RowsAffected
+	}
+	return nil
+}
+
+// GetRevisions returns the history of a book, newest first. The history of
+// a book in the trash is kept until it is purged.
+func (r *bookRepository) GetRevisions(bookID uint) ([]model.BookRevision, error) {
+	var revisions []model.BookRevision
+	if err := r.db.Where("book_id = ?", bookID).Order("revision DESC").Find(&revisions).Error; err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// Revert sets a book's fields back to how they were after an earlier
+// revision and records that as a new revision.
+func (r *bookRepository) Revert(bookID uint, revision int, actor string) (*model.Book, error) {
+	var book *model.Book
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		current, err := lockBook(tx, bookID)
+		if err != nil {
+			return err
+		}
+		var target model.BookRevision
+		if err := tx.Where("book_id = ? AND revision = ?", bookID, revision).First(&target).Error; err != nil {
+			return err
+		}
+		before := current.Fields()
+		current.SetFields(target.Snapshot)
+		if err := updateBook(tx, current, 0, bookColumns(current)); err != nil {
+			return err
+		}
+		book = current
+		note := fmt.Sprintf("reverted to revision %d", revision)
+		return recordRevision(tx, bookID, model.RevisionRevert, actor, note, before, current.Fields())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return book, nil
+}
+
+// lockBook loads a book that is not in the trash and locks its row, which
+// also serializes the numbering of its revisions.
+func lockBook(tx *gorm.DB, id uint) (*model.Book, error) {
+	var book model.Book
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&book, id).Error; err != nil {
+		return nil, err
+	}
+	return &book, nil
+}
+
+// updateBook writes updates to a book and moves it to the next version. The
+// write is conditional on the version the book was read at, and expected, if
+// not zero, has to match that version too.
+func updateBook(tx *gorm.DB, book *model.Book, expected int, updates map[string]interface{}) error {
+	if expected != 0 && expected != book.Version {
+		return ErrVersionMismatch
+	}
+	updates["version"] = gorm.Expr("version + 1")
+	result := tx.Unscoped().Model(&model.Book{}).
+		Where("id = ? AND version = ?", book.ID, book.Version).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionMismatch
+	}
+	book.Version++
+	return nil
+}
+
+// bookColumns returns the columns of the fields Book.Fields returns, for
+// updates that save them.
+func bookColumns(book *model.Book) map[string]interface{} {
+	return map[string]interface{}{
+		"title":         book.Title,
+		"author":        book.Author,
+		"isbn":          book.ISBN,
+		"isbn_key":      isbnKey(book.ISBN),
+		"published_on":  book.PublishedDate,
+		"material_type": book.MaterialType,
+	}
+}
+
+// isbnKey returns the key books are matched on by ISBN: the ISBN-13 of a
+// valid ISBN, so that hyphens, spaces and the ISBN-10 form make no
+// difference, or nothing for an ISBN that is not valid.
+func isbnKey(isbn string) string {
+	key, ok := util.ISBN13(isbn)
+	if !ok {
+		return ""
+	}
+	return key
+}
+
+// recordRevision adds the next revision of a book. Updates that change
+// nothing are not recorded.
+func recordRevision(tx *gorm.DB, bookID uint, action, actor, note string, before, after map[string]interface{}) error {
+	changes := model.DiffFields(before, after)
+	if action == model.RevisionUpdate && len(changes) == 0 {
+		return nil
+	}
+	var last int
+	err := tx.Model(&model.BookRevision{}).
+		Select("COALESCE(MAX(revision), 0)").
+		Where("book_id = ?", bookID).
+		Scan(&last).Error
+	if err != nil {
+		return err
+	}
+	return tx.Create(&model.BookRevision{
+		BookID:   bookID,
+		Revision: last + 1,
+		Action:   action,
+		Actor:    actor,
+		Note:     note,
+		Changes:  changes,
+		Snapshot: after,
+	}).Error
+}
+
+// MigratePublishedDates reads the free-form published dates books had
+// before dates were checked, kept in the published_date column, into their
+// dates. Dates that are read are cleared from the old column; the others
+// are left there and returned, until staff give those books a date.
+func (r *bookRepository) MigratePublishedDates() ([]model.UnparsedDate, error) {
+	if !r.db.Migrator().HasColumn("books", "published_date") {
+		return nil, nil
+	}
+	var legacy []struct {
+		ID            uint
+		PublishedDate string
+	}
+	err := r.db.Table("books").Select("id, published_date").
+		Where("published_date <> '' AND published_on IS NULL").Order("id").Find(&legacy).Error
+	if err != nil {
+		return nil, err
+	}
+	unparsed := []model.UnparsedDate{}
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		for _, book := range legacy {
+			date, err := model.ParseLegacyDate(book.PublishedDate)
+			if err != nil || date.IsZero() {
+				unparsed = append(unparsed, model.UnparsedDate{BookID: book.ID, Value: book.PublishedDate})
+				continue
+			}
+			err = tx.Table("books").Where("id = ?", book.ID).
+				Updates(map[string]interface{}{"published_on": date, "published_date": ""}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return unparsed, nil
+}
+
+// MigrateISBNKeys fills in the ISBN keys of books saved before books were
+// matched on them, those in the trash included.
+func (r *bookRepository) MigrateISBNKeys() error {
+	var books []model.Book
+	err := r.db.Unscoped().Select("id", "isbn").
+		Where("isbn <> '' AND (isbn_key = '' OR isbn_key IS NULL)").Order("id").Find(&books).Error
+	if err != nil {
+		return err
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, book := range books {
+			key := isbnKey(book.ISBN)
+			if key == "" {
+				continue
+			}
+			if err := tx.Unscoped().Model(&model.Book{}).Where("id = ?", book.ID).UpdateColumn("isbn_key", key).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"errors"
+
+	"go.test/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrCopyInUse    = errors.New("copy is on loan or set aside for a hold")
+	ErrBarcodeTaken = errors.New("another copy has this barcode")
+)
+
+type CopyRepository interface {
+	GetByBookID(bookID uint) ([]model.Copy, error)
+	GetByID(id uint) (*model.Copy, error)
+	GetByBarcode(barcode string) (*model.Copy, error)
+	Create(bookCopy *model.Copy) error
+	Update(bookCopy *model.Copy, check func(current *model.Copy) error) error
+	Delete(id uint) error
+	GetAvailability(bookIDs []uint) (map[uint]*model.Availability, error)
+}
+
+type copyRepository struct {
+	db *gorm.DB
+}
+
+func NewCopyRepository(db *gorm.DB) CopyRepository {
+	return &copyRepository{db}
+}
+
+func (r *copyRepository) GetByBookID(bookID uint) ([]model.Copy, error) {
+	var copies []model.Copy
+	if err := r.db.Where("book_id = ?", bookID).Order("id").Find(&copies).Error; err != nil {
+		return nil, err
+	}
+	return copies, nil
+}
+
+func (r *copyRepository) GetByID(id uint) (*model.Copy, error) {
+	var bookCopy model.Copy
+	if err := r.db.First(&bookCopy, id).Error; err != nil {
+		return nil, err
+	}
+	return &bookCopy, nil
+}
+
+func (r *copyRepository) GetByBarcode(barcode string) (*model.Copy, error) {
+	var bookCopy model.Copy
+	if err := r.db.Where("barcode = ?", barcode).First(&bookCopy).Error; err != nil {
+		return nil, err
+	}
+	return &bookCopy, nil
+}
+
+func (r *copyRepository) Create(bookCopy *model.Copy) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkBarcode(tx, bookCopy); err != nil {
+			return err
+		}
+		return tx.Create(bookCopy).Error
+	})
+}
+
+// Update saves a copy once check accepts the copy as it is stored. The row
+// is locked in between, so that a checkout or return cannot change the
+// status check saw.
+func (r *copyRepository) Update(bookCopy *model.Copy, check func(current *model.Copy) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		current, err := lockCopy(tx, bookCopy.ID)
+		if err != nil {
+			return err
+		}
+		if err := check(current); err != nil {
+			return err
+		}
+		if err := checkBarcode(tx, bookCopy); err != nil {
+			return err
+		}
+		return tx.Save(bookCopy).Error
+	})
+}
+
+// Delete deletes a copy that is not out on loan or set aside for a hold.
+func (r *copyRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := lockCopy(tx, id); err != nil {
+			return err
+		}
+		var loans int64
+		if err := tx.Model(&model.Loan{}).Where("copy_id = ? AND returned_at IS NULL", id).Count(&loans).Error; err != nil {
+			return err
+		}
+		var holds int64
+		if err := tx.Model(&model.Hold{}).Where("copy_id = ? AND status = ?", id, model.HoldStatusReady).Count(&holds).Error; err != nil {
+			return err
+		}
+		if loans > 0 || holds > 0 {
+			return ErrCopyInUse
+		}
+		return tx.Delete(&model.Copy{}, id).Error
+	})
+}
+
+// checkBarcode fails with ErrBarcodeTaken if another copy has the barcode
+// of bookCopy.
+func checkBarcode(tx *gorm.DB, bookCopy *model.Copy) error {
+	var taken int64
+	err := tx.Model(&model.Copy{}).Where("barcode = ? AND id <> ?", bookCopy.Barcode, bookCopy.ID).Count(&taken).Error
+	if err != nil {
+		return err
+	}
+	if taken > 0 {
+		return ErrBarcodeTaken
+	}
+	return nil
+}
+
+func lockCopy(tx *gorm.DB, id uint) (*model.Copy, error) {
+	var bookCopy model.Copy
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&bookCopy, id).Error; err != nil {
+		return nil, err
+	}
+	return &bookCopy, nil
+}
+
+// GetAvailability counts copies per status for each of the given books.
+// Every requested book gets an entry, even when it has no copies.
+func (r *copyRepository) GetAvailability(bookIDs []uint) (map[uint]*model.Availability, error) {
+	result := make(map[uint]*model.Availability, len(bookIDs))
+	for _, id := range bookIDs {
+		result[id] = &model.Availability{}
+	}
+	if len(bookIDs) == 0 {
+		return result, nil
+	}
+
+	var rows []struct {
+		BookID uint
+		Status string
+		Count  int
+	}
+	err := r.db.Model(&model.Copy{}).
+		Select("book_id, status, COUNT(*) AS count").
+		Where("book_id IN ?", bookIDs).
+		Group("book_id, status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		result[row.BookID].Add(row.Status, row.Count)
+	}
+	return result, nil
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"go.test/model"
+
+	"gorm.io/gorm"
+)
+
+type CoverRepository interface {
+	GetByBookID(bookID uint) (*model.Cover, error)
+	GetByBookIDs(bookIDs []uint) (map[uint]model.Cover, error)
+	Save(cover *model.Cover) error
+	Delete(bookID uint) error
+}
+
+type coverRepository struct {
+	db *gorm.DB
+}
+
+func NewCoverRepository(db *gorm.DB) CoverRepository {
+	return &coverRepository{db}
+}
+
+func (r *coverRepository) GetByBookID(bookID uint) (*model.Cover, error) {
+	var cover model.Cover
+	if err := r.db.Where("book_id = ?", bookID).First(&cover).Error; err != nil {
+		return nil, err
+	}
+	return &cover, nil
+}
+
+// GetByBookIDs returns the covers of the books that have one.
+func (r *coverRepository) GetByBookIDs(bookIDs []uint) (map[uint]model.Cover, error) {
+	covers := make(map[uint]model.Cover, len(bookIDs))
+	if len(bookIDs) == 0 {
+		return covers, nil
+	}
+	var rows []model.Cover
+	if err := r.db.Where("book_id IN ?", bookIDs).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, cover := range rows {
+		covers[cover.BookID] = cover
+	}
+	return covers, nil
+}
+
+// Save creates the cover of a book or replaces the one it has.
+func (r *coverRepository) Save(cover *model.Cover) error {
+	return r.db.Save(cover).Error
+}
+
+func (r *coverRepository) Delete(bookID uint) error {
+	return r.db.Where("book_id = ?", bookID).Delete(&model.Cover{}).Error
+}
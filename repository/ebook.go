@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"go.test/model"
+
+	"gorm.io/gorm"
+)
+
+type EbookRepository interface {
+	GetByID(id uint) (*model.Ebook, error)
+	GetByBookID(bookID uint) ([]model.Ebook, error)
+	Create(ebook *model.Ebook) error
+	Delete(id uint) error
+	CountByChecksum(checksum string) (int64, error)
+}
+
+type ebookRepository struct {
+	db *gorm.DB
+}
+
+func NewEbookRepository(db *gorm.DB) EbookRepository {
+	return &ebookRepository{db}
+}
+
+func (r *ebookRepository) GetByID(id uint) (*model.Ebook, error) {
+	var ebook model.Ebook
+	if err := r.db.First(&ebook, id).Error; err != nil {
+		return nil, err
+	}
+	return &ebook, nil
+}
+
+func (r *ebookRepository) GetByBookID(bookID uint) ([]model.Ebook, error) {
+	var ebooks []model.Ebook
+	if err := r.db.Where("book_id = ?", bookID).Order("id").Find(&ebooks).Error; err != nil {
+		return nil, err
+	}
+	return ebooks, nil
+}
+
+func (r *ebookRepository) Create(ebook *model.Ebook) error {
+	return r.db.Create(ebook).Error
+}
+
+func (r *ebookRepository) Delete(id uint) error {
+	return r.db.Delete(&model.Ebook{}, id).Error
+}
+
+// CountByChecksum returns how many ebooks share the file with the given
+// checksum.
+func (r *ebookRepository) CountByChecksum(checksum string) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.Ebook{}).Where("checksum = ?", checksum).Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"errors"
+
+	"go.test/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrChargeWaived  = errors.New("charge has already been waived")
+	ErrNothingOwed   = errors.New("nothing is owed on this account")
+	ErrInvalidAmount = errors.New("amount must be positive and cannot exceed the balance")
+)
+
+type FineRepository interface {
+	GetByID(id uint) (*model.FineEntry, error)
+	GetByUserID(userID uint) ([]model.FineEntry, error)
+	GetBalance(userID uint) (int64, error)
+	Create(entry *model.FineEntry) error
+	AddPayment(payment *model.FineEntry) error
+	SettlePayment(payment *model.FineEntry) error
+	Waive(waiver *model.FineEntry) error
+}
+
+type fineRepository struct {
+	db *gorm.DB
+}
+
+func NewFineRepository(db *gorm.DB) FineRepository {
+	return &fineRepository{db}
+}
+
+func (r *fineRepository) GetByID(id uint) (*model.FineEntry, error) {
+	var entry model.FineEntry
+	if err := r.db.First(&entry, id).Error; err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *fineRepository) GetByUserID(userID uint) ([]model.FineEntry, error) {
+	var entries []model.FineEntry
+	if err := r.db.Where("user_id = ?", userID).Order("created_at, id").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *fineRepository) GetBalance(userID uint) (int64, error) {
+	return fineBalance(r.db, userID)
+}
+
+func (r *fineRepository) Create(entry *model.FineEntry) error {
+	return r.db.Create(entry).Error
+}
+
+// AddPayment records a payment if it is positive and no more than the
+// balance. The user's account is locked while the balance is checked, so
+// concurrent payments, pending ones included, cannot pay more than is owed.
+func (r *fineRepository) AddPayment(payment *model.FineEntry) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockAccount(tx, payment.UserID); err != nil {
+			return err
+		}
+		balance, err := fineBalance(tx, payment.UserID)
+		if err != nil {
+			return err
+		}
+		if payment.Amount <= 0 || payment.Amount > balance {
+			return ErrInvalidAmount
+		}
+		payment.Type = model.FineEntryPayment
+		return tx.Create(payment).Error
+	})
+}
+
+// SettlePayment saves the status and provider reference of a pending
+// payment once the provider has answered.
+func (r *fineRepository) SettlePayment(payment *model.FineEntry) error {
+	return r.db.Model(payment).Select("status", "reference").Updates(payment).Error
+}
+
+// Waive records a waiver for the charge in waiver.WaivedID. It waives the
+// amount of the charge, or the outstanding balance if payments have already
+// brought it lower. The charge row is locked so it is only waived once.
+func (r *fineRepository) Waive(waiver *model.FineEntry) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var charge model.FineEntry
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("type = ?", model.FineEntryCharge).
+			First(&charge, *waiver.WaivedID).Error
+		if err != nil {
+			return err
+		}
+		var waived int64
+		if err := tx.Model(&model.FineEntry{}).Where("waived_id = ?", charge.ID).Count(&waived).Error; err != nil {
+			return err
+		}
+		if waived > 0 {
+			return ErrChargeWaived
+		}
+		if err := lockAccount(tx, charge.UserID); err != nil {
+			return err
+		}
+		balance, err := fineBalance(tx, charge.UserID)
+		if err != nil {
+			return err
+		}
+		if balance <= 0 {
+			return ErrNothingOwed
+		}
+		waiver.UserID = charge.UserID
+		waiver.Type = model.FineEntryWaiver
+		waiver.Amount = min(charge.Amount, balance)
+		return tx.Create(waiver).Error
+	})
+}
+
+// lockAccount locks the user row, which serializes the writes to a user's
+// ledger that depend on its balance.
+func lockAccount(tx *gorm.DB, userID uint) error {
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&model.User{}, userID).Error
+}
+
+// fineBalance returns what a user owes: their charges less their payments
+// and waivers. Failed payments do not count.
+func fineBalance(db *gorm.DB, userID uint) (int64, error) {
+	var balance int64
+	err := db.Model(&model.FineEntry{}).
+		Select("COALESCE(SUM(CASE WHEN type = ? THEN amount ELSE -amount END), 0)", model.FineEntryCharge).
+		Where("user_id = ? AND (status IS NULL OR status <> ?)", userID, model.FinePaymentFailed).
+		Scan(&balance).Error
+	return balance, err
+}
@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"go.test/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrHoldClosed = errors.New("hold is no longer active")
+
+type HoldRepository interface {
+	GetByID(id uint) (*model.Hold, error)
+	GetByUserID(userID uint, activeOnly bool) ([]model.Hold, error)
+	GetQueue(bookID uint) ([]model.Hold, error)
+	GetPosition(hold *model.Hold) (int, error)
+	Create(hold *model.Hold) error
+	Cancel(id uint, now time.Time, pickup time.Duration) error
+	ExpireReady(now time.Time, pickup time.Duration) (int, error)
+	AssignAvailable(now time.Time, pickup time.Duration) (int, error)
+}
+
+type holdRepository struct {
+	db *gorm.DB
+}
+
+func NewHoldRepository(db *gorm.DB) HoldRepository {
+	return &holdRepository{db}
+}
+
+func (r *holdRepository) GetByID(id uint) (*model.Hold, error) {
+	var hold model.Hold
+	if err := r.db.First(&hold, id).Error; err != nil {
+		return nil, err
+	}
+	return &hold, nil
+}
+
+func (r *holdRepository) GetByUserID(userID uint, activeOnly bool) ([]model.Hold, error) {
+	var holds []model.Hold
+	query := r.db.Where("user_id = ?", userID)
+	if activeOnly {
+		query = query.Where("status IN ?", []string{model.HoldStatusWaiting, model.HoldStatusReady})
+	}
+	if err := query.Order("created_at, id").Find(&holds).Error; err != nil {
+		return nil, err
+	}
+	return holds, nil
+}
+
+// GetQueue returns the active holds on a book in the order they will be
+// served.
+func (r *holdRepository) GetQueue(bookID uint) ([]model.Hold, error) {
+	var holds []model.Hold
+	err := r.db.Where("book_id = ? AND status IN ?", bookID, []string{model.HoldStatusWaiting, model.HoldStatusReady}).
+		Order("created_at, id").
+		Find(&holds).Error
+	if err != nil {
+		return nil, err
+	}
+	return holds, nil
+}
+
+// GetPosition returns the 1-based position of a waiting hold in its book's
+// queue.
+func (r *holdRepository) GetPosition(hold *model.Hold) (int, error) {
+	var ahead int64
+	err := r.db.Model(&model.Hold{}).
+		Where("book_id = ? AND status = ?", hold.BookID, model.HoldStatusWaiting).
+		Where("created_at < ? OR (created_at = ? AND id < ?)", hold.CreatedAt, hold.CreatedAt, hold.ID).
+		Count(&ahead).Error
+	if err != nil {
+		return 0, err
+	}
+	return int(ahead) + 1, nil
+}
+
+func (r *holdRepository) Create(hold *model.Hold) error {
+	return r.db.Create(hold).Error
+}
+
+// Cancel closes an active hold. A copy that was waiting for pickup is passed
+// on to the next hold in the queue.
+func (r *holdRepository) Cancel(id uint, now time.Time, pickup time.Duration) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return closeHold(tx, id, model.HoldStatusCancelled, now, pickup)
+	})
+}
+
+// ExpireReady expires every hold whose pickup window has passed and hands
+// the copies on. It returns the number of expired holds.
+func (r *holdRepository) ExpireReady(now time.Time, pickup time.Duration) (int, error) {
+	var ids []uint
+	err := r.db.Model(&model.Hold{}).
+		Where("status = ? AND expires_at < ?", model.HoldStatusReady, now).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return 0, err
+	}
+	expired := 0
+	for _, id := range ids {
+		err := r.db.Transaction(func(tx *gorm.DB) error {
+			return closeHold(tx, id, model.HoldStatusExpired, now, pickup)
+		})
+		if errors.Is(err, ErrHoldClosed) {
+			continue
+		}
+		if err != nil {
+			return expired, err
+		}
+		expired++
+	}
+	return expired, nil
+}
+
+// AssignAvailable sets aside available copies for books that still have
+// waiting holds, which happens when copies are added or come back from
+// repair. It returns the number of holds that became ready.
+func (r *holdRepository) AssignAvailable(now time.Time, pickup time.Duration) (int, error) {
+	var copies []model.Copy
+	err := r.db.Where("status = ? AND book_id IN (?)", model.CopyStatusAvailable,
+		r.db.Model(&model.Hold{}).Select("book_id").Where("status = ?", model.HoldStatusWaiting)).
+		Order("id").
+		Find(&copies).Error
+	if err != nil {
+		return 0, err
+	}
+	assigned := 0
+	for i := range copies {
+		var hold *model.Hold
+		err := r.db.Transaction(func(tx *gorm.DB) error {
+			res := tx.Model(&model.Copy{}).
+				Where("id = ? AND status = ?", copies[i].ID, model.CopyStatusAvailable).
+				Update("status", model.CopyStatusOnHold)
+			if res.Error != nil || res.RowsAffected == 0 {
+				return res.Error
+			}
+			var err error
+			hold, err = assignNextHold(tx, &copies[i], now, pickup)
+			return err
+		})
+		if err != nil {
+			return assigned, err
+		}
+		if hold != nil {
+			assigned++
+		}
+	}
+	return assigned, nil
+}
+
+func closeHold(tx *gorm.DB, id uint, status string, now time.Time, pickup time.Duration) error {
+	var hold model.Hold
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&hold, id).Error; err != nil {
+		return err
+	}
+	if !hold.Active() {
+		return ErrHoldClosed
+	}
+	wasReady := hold.Status == model.HoldStatusReady
+	if err := tx.Model(&hold).Update("status", status).Error; err != nil {
+		return err
+	}
+	if !wasReady || hold.CopyID == nil {
+		return nil
+	}
+	var bookCopy model.Copy
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&bookCopy, *hold.CopyID).Error; err != nil {
+		return err
+	}
+	if bookCopy.Status != model.CopyStatusOnHold {
+		return nil
+	}
+	_, err := assignNextHold(tx, &bookCopy, now, pickup)
+	return err
+}
+
+// assignNextHold sets the copy aside for the oldest waiting hold on its book,
+// or puts it back on the shelf when nobody is waiting. It returns the hold
+// that became ready, if any.
+func assignNextHold(tx *gorm.DB, bookCopy *model.Copy, now time.Time, pickup time.Duration) (*model.Hold, error) {
+	var hold model.Hold
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("book_id = ? AND status = ?", bookCopy.BookID, model.HoldStatusWaiting).
+		Order("created_at, id").
+		First(&hold).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, tx.Model(&model.Copy{}).Where("id = ?", bookCopy.ID).Update("status", model.CopyStatusAvailable).Error
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := now.Add(pickup)
+	hold.Status = model.HoldStatusReady
+	hold.CopyID = &bookCopy.ID
+	hold.ReadyAt = &now
+	hold.ExpiresAt = &expiresAt
+	if err := tx.Save(&hold).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Model(&model.Copy{}).Where("id = ?", bookCopy.ID).Update("status", model.CopyStatusOnHold).Error; err != nil {
+		return nil, err
+	}
+	return &hold, nil
+}
+
+// fulfillHold checks out a copy that was set aside for the given user. It
+// reports false when the user has no ready hold on the copy.
+func fulfillHold(tx *gorm.DB, copyID, userID uint) (bool, error) {
+	res := tx.Model(&model.Hold{}).
+		Where("copy_id = ? AND user_id = ? AND status = ?", copyID, userID, model.HoldStatusReady).
+		Update("status", model.HoldStatusFulfilled)
+	if res.Error != nil || res.RowsAffected == 0 {
+		return false, res.Error
+	}
+	res = tx.Model(&model.Copy{}).
+		Where("id = ? AND status = ?", copyID, model.CopyStatusOnHold).
+		Update("status", model.CopyStatusOnLoan)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return false, ErrCopyUnavailable
+	}
+	return true, nil
+}
@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"go.test/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// importBatchSize is how many row reports are written at a time, which is
+// also how often an import's progress is saved.
+const importBatchSize = 100
+
+// errRollback ends an import transaction that must not be committed.
+var errRollback = errors.New("rollback")
+
+type ImportRepository interface {
+	CreateJob(job *model.ImportJob) error
+	UpdateJob(job *model.ImportJob) error
+	GetJob(id uint) (*model.ImportJob, error)
+	GetRows(jobID uint, action string) ([]model.ImportRow, error)
+	Import(job *model.ImportJob, next func() (*model.ImportRow, error), merge func(row *model.ImportRow, existing *model.Book) (*model.Book, error)) error
+}
+
+type importRepository struct {
+	db *gorm.DB
+}
+
+func NewImportRepository(db *gorm.DB) ImportRepository {
+	return &importRepository{db}
+}
+
+func (r *importRepository) CreateJob(job *model.ImportJob) error {
+	return r.db.Omit("Rows").Create(job).Error
+}
+
+func (r *importRepository) UpdateJob(job *model.ImportJob) error {
+	return r.db.Omit("Rows").Save(job).Error
+}
+
+func (r *importRepository) GetJob(id uint) (*model.ImportJob, error) {
+	var job model.ImportJob
+	if err := r.db.First(&job, id).Error; err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// GetRows returns the row reports of an import in upload order. A non-empty
+// action returns only the rows with that outcome.
+func (r *importRepository) GetRows(jobID uint, action string) ([]model.ImportRow, error) {
+	var rows []model.ImportRow
+	query := r.db.Where("job_id = ?", jobID)
+	if action != "" {
+		query = query.Where("action = ?", action)
+	}
+	if err := query.Order("number").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// Import writes the rows returned by next to the catalog until next returns
+// io.EOF. Rows with an ISBN are matched to the first book with that ISBN;
+// merge gets that book, or nil, and returns the book to save, or an error
+// that rejects the row. Rows that next already rejected are only reported.
+//
+// Transactional jobs write all rows in one transaction that is committed
+// only if no row was rejected and the job is not a dry run. Other jobs
+// commit row by row. Row reports and progress are saved outside the
+// transaction, so they are kept either way.
+func (r *importRepository) Import(job *model.ImportJob, next func() (*model.ImportRow, error), merge func(row *model.ImportRow, existing *model.Book) (*model.Book, error)) error {
+	batch := make([]model.ImportRow, 0, importBatchSize)
+	flush := func() error {
+		if len(batch) > 0 {
+			if err := r.db.Create(&batch).Error; err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+		return r.UpdateJob(job)
+	}
+	each := func(write func(row *model.ImportRow) error) error {
+		for {
+			row, err := next()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if row.Action != model.ImportRowRejected {
+				if err := write(row); err != nil {
+					return err
+				}
+			}
+			row.JobID = job.ID
+			job.Count(row)
+			batch = append(batch, *row)
+			if len(batch) == importBatchSize {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	var err error
+	if job.Transactional() {
+		job.Committed = false
+		err = r.db.Transaction(func(tx *gorm.DB) error {
+			err := each(func(row *model.ImportRow) error {
+				return importRow(tx, job, row, merge)
+			})
+			if err != nil {
+				return err
+			}
+			if job.DryRun || job.Rejected > 0 {
+				return errRollback
+			}
+			return nil
+		})
+		if errors.Is(err, errRollback) {
+			err = nil
+		} else if err == nil {
+			job.Committed = true
+		}
+	} else {
+		job.Committed = true
+		err = each(func(row *model.ImportRow) error {
+			return r.db.Transaction(func(tx *gorm.DB) error {
+				return importRow(tx, job, row, merge)
+			})
+		})
+	}
+	if flushErr := flush(); err == nil {
+		err = flushErr
+	}
+	if !job.Committed {
+		// The books these rows created were rolled back.
+		clearErr := r.db.Model(&model.ImportRow{}).
+			Where("job_id = ? AND action = ?", job.ID, model.ImportRowCreated).
+			Update("book_id", 0).Error
+		if err == nil {
+			err = clearErr
+		}
+	}
+	return err
+}
+
+// importRow creates or updates the book of a row and records the revision.
+func importRow(tx *gorm.DB, job *model.ImportJob, row *model.ImportRow, merge func(row *model.ImportRow, existing *model.Book) (*model.Book, error)) error {
+	var existing *model.Book
+	if row.ISBN != "" {
+		var book model.Book
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("isbn_key = ?", isbnKey(row.ISBN)).
+			Order("id").
+			First(&book).Error
+		if err == nil {
+			existing = &book
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+	}
+	var before map[string]interface{}
+	if existing != nil {
+		before = existing.Fields()
+	}
+	book, err := merge(row, existing)
+	if err != nil {
+		row.Action = model.ImportRowRejected
+		row.Reason = err.Error()
+		return nil
+	}
+	note := fmt.Sprintf("import %d", job.ID)
+	if existing == nil {
+		book.Version = 1
+		book.ISBNKey = isbnKey(book.ISBN)
+		if err := tx.Create(book).Error; err != nil {
+			return err
+		}
+		row.Action = model.ImportRowCreated
+		row.BookID = book.ID
+		if err := saveMARC(tx, book.ID, row.MARC); err != nil {
+			return err
+		}
+		return recordRevision(tx, book.ID, model.RevisionCreate, job.Actor, note, map[string]interface{}{}, book.Fields())
+	}
+	row.BookID = book.ID
+	if err := saveMARC(tx, book.ID, row.MARC); err != nil {
+		return err
+	}
+	if len(model.DiffFields(before, book.Fields())) == 0 {
+		row.Action = model.ImportRowUnchanged
+		return nil
+	}
+	if err := updateBook(tx, book, 0, bookColumns(book)); err != nil {
+		return err
+	}
+	row.Action = model.ImportRowUpdated
+	return recordRevision(tx, book.ID, model.RevisionUpdate, job.Actor, note, before, book.Fields())
+}
+
+// saveMARC keeps the MARC record a book was imported from, replacing any
+// earlier one. A nil record leaves things as they are.
+func saveMARC(tx *gorm.DB, bookID uint, record []byte) error {
+	if record == nil {
+		return nil
+	}
+	return tx.Save(&model.BookMARC{BookID: bookID, Record: record}).Error
+}
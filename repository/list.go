@@ -0,0 +1,281 @@
+package repository
+
+import (
+	"errors"
+	"sort"
+
+	"go.test/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrAlreadyOnList = errors.New("book is already on this list")
+	ErrNotOnList     = errors.New("book is not on this list")
+)
+
+type ListRepository interface {
+	GetByID(id uint) (*model.ReadingList, error)
+	GetByShareToken(token string) (*model.ReadingList, error)
+	GetByUserID(userID uint) ([]model.ReadingList, error)
+	GetMemberships(userID, bookID uint) ([]model.ListMembership, error)
+	EnsureShelves(userID uint) error
+	Create(list *model.ReadingList) error
+	Update(list *model.ReadingList) error
+	Delete(id uint) error
+	AddEntry(entry *model.ListEntry, removeFrom []uint) error
+	UpdateEntry(listID, bookID uint, position int, note *string) (*model.ListEntry, error)
+	RemoveEntry(listID, bookID uint) error
+}
+
+type listRepository struct {
+	db *gorm.DB
+}
+
+func NewListRepository(db *gorm.DB) ListRepository {
+	return &listRepository{db}
+}
+
+// GetByID returns a list with its entries in order and the books they refer
+// to.
+func (r *listRepository) GetByID(id uint) (*model.ReadingList, error) {
+	var list model.ReadingList
+	if err := r.db.Preload("Entries", orderByPosition).First(&list, id).Error; err != nil {
+		return nil, err
+	}
+	if err := r.loadBooks(list.Entries); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+func (r *listRepository) GetByShareToken(token string) (*model.ReadingList, error) {
+	var list model.ReadingList
+	if err := r.db.Where("share_token = ?", token).First(&list).Error; err != nil {
+		return nil, err
+	}
+	return r.GetByID(list.ID)
+}
+
+// GetByUserID returns a user's lists without their entries, shelves first.
+func (r *listRepository) GetByUserID(userID uint) ([]model.ReadingList, error) {
+	var lists []model.ReadingList
+	err := r.db.Where("user_id = ?", userID).
+		Order("CASE WHEN shelf IS NULL THEN 1 ELSE 0 END, id").
+		Find(&lists).Error
+	if err != nil {
+		return nil, err
+	}
+	return lists, nil
+}
+
+// GetMemberships returns the lists of a user that contain the book.
+func (r *listRepository) GetMemberships(userID, bookID uint) ([]model.ListMembership, error) {
+	var memberships []model.ListMembership
+	err := r.db.Model(&model.ListEntry{}).
+		Select("reading_lists.id AS list_id, reading_lists.name, reading_lists.shelf, list_entries.position, list_entries.note").
+		Joins("JOIN reading_lists ON reading_lists.id = list_entries.list_id").
+		Where("reading_lists.user_id = ? AND list_entries.book_id = ?", userID, bookID).
+		Order("reading_lists.id").
+		Scan(&memberships).Error
+	if err != nil {
+		return nil, err
+	}
+	return memberships, nil
+}
+
+// EnsureShelves creates whichever built-in shelves the user does not have
+// yet. It is safe to call concurrently.
+func (r *listRepository) EnsureShelves(userID uint) error {
+	shelves := make([]model.ReadingList, len(model.Shelves))
+	for i := range model.Shelves {
+		shelf := model.Shelves[i]
+		shelves[i] = model.ReadingList{UserID: userID, Shelf: &shelf, Name: shelfNames[shelf]}
+	}
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&shelves).Error
+}
+
+var shelfNames = map[string]string{
+	model.ShelfToRead:    "To read",
+	model.ShelfReading:   "Reading",
+	model.ShelfRead:      "Read",
+	model.ShelfFavorites: "Favorites",
+}
+
+func (r *listRepository) Create(list *model.ReadingList) error {
+	return r.db.Omit("Entries").Create(list).Error
+}
+
+func (r *listRepository) Update(list *model.ReadingList) error {
+	return r.db.Omit("Entries").Save(list).Error
+}
+
+func (r *listRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("list_id = ?", id).Delete(&model.ListEntry{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.ReadingList{}, id).Error
+	})
+}
+
+// AddEntry puts a book on a list at entry.Position, or at the end if the
+// position is zero or past the end, and takes it off the lists in
+// removeFrom.
+func (r *listRepository) AddEntry(entry *model.ListEntry, removeFrom []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Lock every list involved in id order so that two moves between
+		// the same shelves cannot deadlock.
+		ids := append([]uint{entry.ListID}, removeFrom...)
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		var count int
+		for _, id := range ids {
+			n, err := lockList(tx, id)
+			if err != nil {
+				return err
+			}
+			if id == entry.ListID {
+				count = n
+			}
+		}
+		var existing int64
+		if err := tx.Model(&model.ListEntry{}).Where("list_id = ? AND book_id = ?", entry.ListID, entry.BookID).Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return ErrAlreadyOnList
+		}
+
+		if entry.Position < 1 || entry.Position > count {
+			entry.Position = count + 1
+		} else if err := shiftEntries(tx, entry.ListID, entry.Position, count, 1); err != nil {
+			return err
+		}
+		if err := tx.Create(entry).Error; err != nil {
+			return err
+		}
+
+		for _, listID := range removeFrom {
+			if err := removeEntry(tx, listID, entry.BookID); err != nil && !errors.Is(err, ErrNotOnList) {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// UpdateEntry moves an entry to a new position, shifting the entries in
+// between, and replaces its note when one is given. A zero position keeps
+// the entry where it is.
+func (r *listRepository) UpdateEntry(listID, bookID uint, position int, note *string) (*model.ListEntry, error) {
+	var entry model.ListEntry
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		count, err := lockList(tx, listID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("list_id = ? AND book_id = ?", listID, bookID).First(&entry).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrNotOnList
+			}
+			return err
+		}
+
+		if position > count {
+			position = count
+		}
+		if position > 0 && position != entry.Position {
+			if position < entry.Position {
+				err = shiftEntries(tx, listID, position, entry.Position-1, 1)
+			} else {
+				err = shiftEntries(tx, listID, entry.Position+1, position, -1)
+			}
+			if err != nil {
+				return err
+			}
+			entry.Position = position
+		}
+		if note != nil {
+			entry.Note = *note
+		}
+		return tx.Save(&entry).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *listRepository) RemoveEntry(listID, bookID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := lockList(tx, listID); err != nil {
+			return err
+		}
+		return removeEntry(tx, listID, bookID)
+	})
+}
+
+func (r *listRepository) loadBooks(entries []model.ListEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(entries))
+	for i := range entries {
+		ids[i] = entries[i].BookID
+	}
+	var books []model.Book
+	if err := r.db.Where("id IN ?", ids).Find(&books).Error; err != nil {
+		return err
+	}
+	byID := make(map[uint]*model.Book, len(books))
+	for i := range books {
+		byID[books[i].ID] = &books[i]
+	}
+	for i := range entries {
+		entries[i].Book = byID[entries[i].BookID]
+	}
+	return nil
+}
+
+// lockList locks a list row so that concurrent changes to its entries are
+// serialized, and returns how many entries it has.
+func lockList(tx *gorm.DB, listID uint) (int, error) {
+	var list model.ReadingList
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&list, listID).Error; err != nil {
+		return 0, err
+	}
+	var count int64
+	if err := tx.Model(&model.ListEntry{}).Where("list_id = ?", listID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+// shiftEntries moves the entries at positions from..to of a list by delta.
+func shiftEntries(tx *gorm.DB, listID uint, from, to, delta int) error {
+	return tx.Model(&model.ListEntry{}).
+		Where("list_id = ? AND position BETWEEN ? AND ?", listID, from, to).
+		Update("position", gorm.Expr("position + ?", delta)).Error
+}
+
+// removeEntry deletes a book from a list and closes the gap it leaves.
+func removeEntry(tx *gorm.DB, listID, bookID uint) error {
+	var entry model.ListEntry
+	if err := tx.Where("list_id = ? AND book_id = ?", listID, bookID).First(&entry).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotOnList
+		}
+		return err
+	}
+	if err := tx.Delete(&entry).Error; err != nil {
+		return err
+	}
+	return tx.Model(&model.ListEntry{}).
+		Where("list_id = ? AND position > ?", listID, entry.Position).
+		Update("position", gorm.Expr("position - 1")).Error
+}
+
+func orderByPosition(db *gorm.DB) *gorm.DB {
+	return db.Order("position")
+}
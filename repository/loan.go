@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"go.test/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrCopyUnavailable = errors.New("copy is not available for checkout")
+	ErrLoanReturned    = errors.New("loan has already been returned")
+	ErrLoanConflict    = errors.New("loan was changed by another request, please retry")
+)
+
+type LoanRepository interface {
+	GetByID(id uint) (*model.Loan, error)
+	GetByUserID(userID uint, activeOnly bool) ([]model.Loan, error)
+	GetByCopyID(copyID uint) ([]model.Loan, error)
+	HasActiveLoan(userID, bookID uint) (bool, error)
+	Checkout(loan *model.Loan, actor string) error
+	Return(loan *model.Loan, returnedAt time.Time, actor string, holdPickup time.Duration, charge *model.FineEntry) (*model.Loan, error)
+	Renew(loan *model.Loan, dueAt time.Time, actor, note string) error
+}
+
+type loanRepository struct {
+	db *gorm.DB
+}
+
+func NewLoanRepository(db *gorm.DB) LoanRepository {
+	return &loanRepository{db}
+}
+
+func (r *loanRepository) GetByID(id uint) (*model.Loan, error) {
+	var loan model.Loan
+	if err := r.db.Preload("Events", orderByID).First(&loan, id).Error; err != nil {
+		return nil, err
+	}
+	return &loan, nil
+}
+
+func (r *loanRepository) GetByUserID(userID uint, activeOnly bool) ([]model.Loan, error) {
+	var loans []model.Loan
+	query := r.db.Where("user_id = ?", userID)
+	if activeOnly {
+		query = query.Where("returned_at IS NULL")
+	}
+	if err := query.Order("checked_out_at DESC").Find(&loans).Error; err != nil {
+		return nil, err
+	}
+	return loans, nil
+}
+
+func (r *loanRepository) GetByCopyID(copyID uint) ([]model.Loan, error) {
+	var loans []model.Loan
+	if err := r.db.Where("copy_id = ?", copyID).Order("checked_out_at DESC").Find(&loans).Error; err != nil {
+		return nil, err
+	}
+	return loans, nil
+}
+
+// HasActiveLoan reports whether the user has a copy of the book on loan.
+func (r *loanRepository) HasActiveLoan(userID, bookID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.Loan{}).
+		Joins("JOIN copies ON copies.id = loans.copy_id").
+		Where("loans.user_id = ? AND copies.book_id = ? AND loans.returned_at IS NULL", userID, bookID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Checkout marks the copy as on loan and records the loan in one
+// transaction. The copy status is flipped with a conditional update, so of
+// two concurrent checkouts of the same copy only one can succeed. A copy
+// that is set aside for a hold can only be checked out by the hold's owner.
+func (r *loanRepository) Checkout(loan *model.Loan, actor string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&model.Copy{}).
+			Where("id = ? AND status = ?", loan.CopyID, model.CopyStatusAvailable).
+			Update("status", model.CopyStatusOnLoan)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			if err := tx.First(&model.Copy{}, loan.CopyID).Error; err != nil {
+				return err
+			}
+			fulfilled, err := fulfillHold(tx, loan.CopyID, loan.UserID)
+			if err != nil {
+				return err
+			}
+			if !fulfilled {
+				return ErrCopyUnavailable
+			}
+		}
+		if err := tx.Create(loan).Error; err != nil {
+			return err
+		}
+		event := model.LoanEvent{
+			LoanID:    loan.ID,
+			Type:      model.LoanEventCheckout,
+			Actor:     actor,
+			DueAt:     loan.DueAt,
+			CreatedAt: loan.CheckedOutAt,
+		}
+		if err := tx.Create(&event).Error; err != nil {
+			return err
+		}
+		loan.Events = []model.LoanEvent{event}
+		return nil
+	})
+}
+
+// Return closes the loan, posts its overdue charge, if any, and passes its
+// copy on to the next hold on the book, or puts it back on the shelf. The
+// loan row is locked for the duration of the transaction so a loan can only
+// be returned once, and the return only applies if the loan still has the
+// renewal count it was read with, so the charge was priced against the due
+// date that is being closed.
+func (r *loanRepository) Return(loan *model.Loan, returnedAt time.Time, actor string, holdPickup time.Duration, charge *model.FineEntry) (*model.Loan, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var locked model.Loan
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&locked, loan.ID).Error; err != nil {
+			return err
+		}
+		if locked.ReturnedAt != nil {
+			return ErrLoanReturned
+		}
+		if locked.RenewalCount != loan.RenewalCount {
+			return ErrLoanConflict
+		}
+		res := tx.Model(&model.Loan{}).
+			Where("id = ? AND returned_at IS NULL", loan.ID).
+			Update("returned_at", returnedAt)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrLoanReturned
+		}
+		if charge != nil {
+			charge.LoanID = &locked.ID
+			if err := tx.Create(charge).Error; err != nil {
+				return err
+			}
+		}
+
+		var bookCopy model.Copy
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&bookCopy, locked.CopyID).Error; err != nil {
+			return err
+		}
+		if bookCopy.Status == model.CopyStatusOnLoan {
+			if _, err := assignNextHold(tx, &bookCopy, returnedAt, holdPickup); err != nil {
+				return err
+			}
+		}
+		event := model.LoanEvent{
+			LoanID:    locked.ID,
+			Type:      model.LoanEventReturn,
+			Actor:     actor,
+			DueAt:     locked.DueAt,
+			CreatedAt: returnedAt,
+		}
+		return tx.Create(&event).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByID(loan.ID)
+}
+
+// Renew moves the due date of an active loan and records the renewal. The
+// update only applies if the loan still has the renewal count it was read
+// with, so two renewals racing each other cannot both pass the policy check.
+func (r *loanRepository) Renew(loan *model.Loan, dueAt time.Time, actor, note string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&model.Loan{}).
+			Where("id = ? AND returned_at IS NULL AND renewal_count = ?", loan.ID, loan.RenewalCount).
+			Updates(map[string]interface{}{
+				"due_at":        dueAt,
+				"renewal_count": loan.RenewalCount + 1,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrLoanConflict
+		}
+		event := model.LoanEvent{
+			LoanID:    loan.ID,
+			Type:      model.LoanEventRenew,
+			Actor:     actor,
+			Note:      note,
+			DueAt:     dueAt,
+			CreatedAt: time.Now(),
+		}
+		if err := tx.Create(&event).Error; err != nil {
+			return err
+		}
+		loan.DueAt = dueAt
+		loan.RenewalCount++
+		loan.Events = append(loan.Events, event)
+		return nil
+	})
+}
+
+func orderByID(db *gorm.DB) *gorm.DB {
+	return db.Order("id")
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"go.test/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ModerationRepository interface {
+	CreateReport(report *model.ReviewReport) error
+	GetReports(reviewID uint, unresolvedOnly bool) ([]model.ReviewReport, error)
+	GetQueue() ([]model.Review, error)
+	GetLogs(reviewID uint) ([]model.ModerationLog, error)
+	CreateLog(entry *model.ModerationLog) error
+	IsBanned(userID uint) (bool, error)
+	ApplyDecision(review *model.Review, entry *model.ModerationLog, ban *model.ReviewBan) error
+}
+
+type moderationRepository struct {
+	db *gorm.DB
+}
+
+func NewModerationRepository(db *gorm.DB) ModerationRepository {
+	return &moderationRepository{db}
+}
+
+func (r *moderationRepository) CreateReport(report *model.ReviewReport) error {
+	return r.db.Create(report).Error
+}
+
+func (r *moderationRepository) GetReports(reviewID uint, unresolvedOnly bool) ([]model.ReviewReport, error) {
+	var reports []model.ReviewReport
+	query := r.db.Where("review_id = ?", reviewID)
+	if unresolvedOnly {
+		query = query.Where("resolved = ?", false)
+	}
+	if err := query.Order("created_at, id").Find(&reports).Error; err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+// GetQueue returns the reviews held by the filter and the reviews with
+// unresolved reports, oldest first.
+func (r *moderationRepository) GetQueue() ([]model.Review, error) {
+	var reviews []model.Review
+	reported := r.db.Model(&model.ReviewReport{}).Select("review_id").Where("resolved = ?", false)
+	err := r.db.Where("status = ? OR id IN (?)", model.ReviewStatusPending, reported).
+		Order("created_at, id").
+		Find(&reviews).Error
+	if err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
+
+// GetLogs returns the moderation log, newest first. A zero reviewID returns
+// the log for all reviews.
+func (r *moderationRepository) GetLogs(reviewID uint) ([]model.ModerationLog, error) {
+	var entries []model.ModerationLog
+	query := r.db.Order("created_at DESC, id DESC")
+	if reviewID != 0 {
+		query = query.Where("review_id = ?", reviewID)
+	}
+	if err := query.Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *moderationRepository) CreateLog(entry *model.ModerationLog) error {
+	return r.db.Create(entry).Error
+}
+
+func (r *moderationRepository) IsBanned(userID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.ReviewBan{}).Where("user_id = ?", userID).Count(&count).Error
+	return count > 0, err
+}
+
+// ApplyDecision stores the new status of the review, resolves its reports,
+// logs the decision and, when ban is set, bans the author, all in one
+// transaction.
+func (r *moderationRepository) ApplyDecision(review *model.Review, entry *model.ModerationLog, ban *model.ReviewBan) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(review).Update("status", review.Status).Error; err != nil {
+			return err
+		}
+		err := tx.Model(&model.ReviewReport{}).
+			Where("review_id = ? AND resolved = ?", review.ID, false).
+			Update("resolved", true).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(entry).Error; err != nil {
+			return err
+		}
+		if ban == nil {
+			return nil
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(ban).Error
+	})
+}
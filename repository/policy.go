@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"go.test/model"
+
+	"gorm.io/gorm"
+)
+
+type PolicyRepository interface {
+	GetAll() ([]model.LoanPolicy, error)
+	GetByID(id uint) (*model.LoanPolicy, error)
+	Create(policy *model.LoanPolicy) error
+	Update(policy *model.LoanPolicy) error
+	Delete(id uint) error
+}
+
+type policyRepository struct {
+	db *gorm.DB
+}
+
+func NewPolicyRepository(db *gorm.DB) PolicyRepository {
+	return &policyRepository{db}
+}
+
+func (r *policyRepository) GetAll() ([]model.LoanPolicy, error) {
+	var policies []model.LoanPolicy
+	if err := r.db.Order("id").Find(&policies).Error; err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+func (r *policyRepository) GetByID(id uint) (*model.LoanPolicy, error) {
+	var policy model.LoanPolicy
+	if err := r.db.First(&policy, id).Error; err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func (r *policyRepository) Create(policy *model.LoanPolicy) error {
+	return r.db.Create(policy).Error
+}
+
+func (r *policyRepository) Update(policy *model.LoanPolicy) error {
+	return r.db.Save(policy).Error
+}
+
+func (r *policyRepository) Delete(id uint) error {
+	return r.db.Delete(&model.LoanPolicy{}, id).Error
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"go.test/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ProgressRepository interface {
+	GetByUserID(userID, bookID uint) ([]model.ReadingProgress, error)
+	GetBetween(from, to time.Time) ([]model.ReadingProgress, error)
+	Record(userID, bookID uint, apply func(progress *model.ReadingProgress) error) (*model.ReadingProgress, error)
+	GetGoals(userID uint) ([]model.ReadingGoal, error)
+	GetGoalsByYear(year int) ([]model.ReadingGoal, error)
+	SaveGoal(goal *model.ReadingGoal) error
+}
+
+type progressRepository struct {
+	db *gorm.DB
+}
+
+func NewProgressRepository(db *gorm.DB) ProgressRepository {
+	return &progressRepository{db}
+}
+
+// GetByUserID returns a user's read-throughs with their updates, newest
+// first. A zero bookID returns every book.
+func (r *progressRepository) GetByUserID(userID, bookID uint) ([]model.ReadingProgress, error) {
+	var progress []model.ReadingProgress
+	query := r.db.Preload("Updates", orderByID).Where("user_id = ?", userID)
+	if bookID != 0 {
+		query = query.Where("book_id = ?", bookID)
+	}
+	if err := query.Order("started_at DESC, id DESC").Find(&progress).Error; err != nil {
+		return nil, err
+	}
+	return progress, nil
+}
+
+// GetBetween returns the read-throughs that were started or finished in
+// [from, to), without their updates.
+func (r *progressRepository) GetBetween(from, to time.Time) ([]model.ReadingProgress, error) {
+	var progress []model.ReadingProgress
+	err := r.db.Where("(started_at >= ? AND started_at < ?) OR (finished_at >= ? AND finished_at < ?)", from, to, from, to).
+		Find(&progress).Error
+	if err != nil {
+		return nil, err
+	}
+	return progress, nil
+}
+
+// Record applies a progress update to the user's unfinished read-through of
+// the book, starting a new one if there is none, and adds it to the history.
+// Updates from the same user are serialized so that two at once cannot both
+// start a read-through.
+func (r *progressRepository) Record(userID, bookID uint, apply func(progress *model.ReadingProgress) error) (*model.ReadingProgress, error) {
+	var progress model.ReadingProgress
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&model.User{}, userID).Error; err != nil {
+			return err
+		}
+		err := tx.Where("user_id = ? AND book_id = ? AND finished_at IS NULL", userID, bookID).
+			Order("id DESC").
+			First(&progress).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			progress = model.ReadingProgress{UserID: userID, BookID: bookID}
+		} else if err != nil {
+			return err
+		}
+
+		if err := apply(&progress); err != nil {
+			return err
+		}
+		if err := tx.Omit("Updates").Save(&progress).Error; err != nil {
+			return err
+		}
+		update := model.ProgressUpdate{
+			ProgressID: progress.ID,
+			Page:       progress.Page,
+			Percent:    progress.Percent,
+		}
+		if err := tx.Create(&update).Error; err != nil {
+			return err
+		}
+		progress.Updates = []model.ProgressUpdate{update}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &progress, nil
+}
+
+func (r *progressRepository) GetGoals(userID uint) ([]model.ReadingGoal, error) {
+	var goals []model.ReadingGoal
+	if err := r.db.Where("user_id = ?", userID).Order("year DESC").Find(&goals).Error; err != nil {
+		return nil, err
+	}
+	return goals, nil
+}
+
+func (r *progressRepository) GetGoalsByYear(year int) ([]model.ReadingGoal, error) {
+	var goals []model.ReadingGoal
+	if err := r.db.Where("year = ?", year).Find(&goals).Error; err != nil {
+		return nil, err
+	}
+	return goals, nil
+}
+
+// SaveGoal sets the user's goal for the year, replacing any earlier one.
+func (r *progressRepository) SaveGoal(goal *model.ReadingGoal) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "year"}},
+		DoUpdates: clause.AssignmentColumns([]string{"target", "updated_at"}),
+	}).Create(goal).Error
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"go.test/model"
+
+	"gorm.io/gorm"
+)
+
+type ReviewRepository interface {
+	GetByBookID(bookID uint, includeHidden bool) ([]model.Review, error)
+	GetByID(id uint) (*model.Review, error)
+	GetByBookAndUser(bookID, userID uint) (*model.Review, error)
+	Create(review *model.Review) error
+	Update(review *model.Review) error
+	Delete(id uint) error
+	GetStats(bookIDs []uint) (map[uint]model.RatingStats, error)
+}
+
+type reviewRepository struct {
+	db *gorm.DB
+}
+
+func NewReviewRepository(db *gorm.DB) ReviewRepository {
+	return &reviewRepository{db}
+}
+
+func (r *reviewRepository) GetByBookID(bookID uint, includeHidden bool) ([]model.Review, error) {
+	var reviews []model.Review
+	query := r.db.Where("book_id = ?", bookID)
+	if !includeHidden {
+		query = query.Where("status = ?", model.ReviewStatusPublished)
+	}
+	if err := query.Order("created_at DESC, id DESC").Find(&reviews).Error; err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
+
+func (r *reviewRepository) GetByID(id uint) (*model.Review, error) {
+	var review model.Review
+	if err := r.db.First(&review, id).Error; err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+func (r *reviewRepository) GetByBookAndUser(bookID, userID uint) (*model.Review, error) {
+	var review model.Review
+	if err := r.db.Where("book_id = ? AND user_id = ?", bookID, userID).First(&review).Error; err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+func (r *reviewRepository) Create(review *model.Review) error {
+	return r.db.Create(review).Error
+}
+
+func (r *reviewRepository) Update(review *model.Review) error {
+	return r.db.Save(review).Error
+}
+
+func (r *reviewRepository) Delete(id uint) error {
+	return r.db.Delete(&model.Review{}, id).Error
+}
+
+// GetStats returns the average rating and review count of each book, over
+// published reviews only. Books without reviews are left out.
+func (r *reviewRepository) GetStats(bookIDs []uint) (map[uint]model.RatingStats, error) {
+	stats := make(map[uint]model.RatingStats, len(bookIDs))
+	if len(bookIDs) == 0 {
+		return stats, nil
+	}
+	var rows []struct {
+		BookID  uint
+		Average float64
+		Count   int
+	}
+	err := r.db.Model(&model.Review{}).
+		Select("book_id, AVG(rating) AS average, COUNT(*) AS count").
+		Where("book_id IN ? AND status = ?", bookIDs, model.ReviewStatusPublished).
+		Group("book_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		stats[row.BookID] = model.RatingStats{Average: row.Average, Count: row.Count}
+	}
+	return stats, nil
+}
@@ -1,9 +1,13 @@
 package repository
 
 import (
+	"errors"
+	"time"
+
 	"go.test/model"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserRepository interface {
@@ -11,10 +15,21 @@ type UserRepository interface {
 	Create(user *model.User) error
 	GetAll() ([]model.User, error)
 	GetByID(id uint) (*model.User, error)
-	Update(user *model.User) error
-	Delete(id uint) error
+	Update(user *model.User, actor string) error
+	Patch(id uint, version int, actor, note string, apply func(user *model.User) error) (*model.User, error)
+	GetRevisions(userID uint) ([]model.UserRevision, error)
+	Delete(id uint, version int) error
+	GetDeleted() ([]model.User, error)
+	Restore(id uint) error
+	Purge(id uint) error
 }
 
+var (
+	ErrUserHasObligations = errors.New("user still has loans out or fines owed")
+	ErrUsernameTaken      = errors.New("username is already taken")
+	ErrUsernameInTrash    = errors.New("username belongs to a deleted user; restore or purge that user first")
+)
+
 type userRepository struct {
 	db *gorm.DB
 }
@@ -31,8 +46,24 @@ func (r *userRepository) GetByUsername(username string) (*model.User, error) {
 	return &user, nil
 }
 
+// Create adds a user. Usernames stay taken while their user is in the
+// trash, so that restoring the user cannot clash with a newer one.
 func (r *userRepository) Create(user *model.User) error {
-	return r.db.Create(user).Error
+	user.Version = 1
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var existing model.User
+		err := tx.Unscoped().Where("username = ?", user.Username).First(&existing).Error
+		if err == nil {
+			if existing.DeletedAt.Valid {
+				return ErrUsernameInTrash
+			}
+			return ErrUsernameTaken
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		return tx.Create(user).Error
+	})
 }
 
 func (r *userRepository) GetAll() ([]model.User, error) {
@@ -51,10 +82,187 @@ func (r *userRepository) GetByID(id uint) (*model.User, error) {
 	return &user, nil
 }
 
-func (r *userRepository) Update(user *model.User) error {
-	return r.db.Save(user).Error
+// Update saves a user's name and role and records the fields that changed.
+// A non-zero user.Version must match the stored version; on success it is
+// set to the new one.
+func (r *userRepository) Update(user *model.User, actor string) error {
+	updated, err := r.Patch(user.ID, user.Version, actor, "", func(current *model.User) error {
+		current.SetFields(user.Fields())
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	user.Version = updated.Version
+	return nil
+}
+
+// Patch changes a user with apply while their row is locked, so that apply
+// sees the user as they are stored. The change is recorded with note, and a
+// non-zero version must match the stored version.
+func (r *userRepository) Patch(id uint, version int, actor, note string, apply func(user *model.User) error) (*model.User, error) {
+	var user model.User
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, id).Error; err != nil {
+			return err
+		}
+		if version != 0 && version != user.Version {
+			return ErrVersionMismatch
+		}
+		before := user.Fields()
+		if err := apply(&user); err != nil {
+			return err
+		}
+		updates := user.Fields()
+		updates["version"] = gorm.Expr("version + 1")
+		result := tx.Model(&model.User{}).
+			Where("id = ? AND version = ?", id, user.Version).
+			Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrVersionMismatch
+		}
+		user.Version++
+		return recordUserRevision(tx, id, actor, note, before, user.Fields())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Delete moves a user to the trash. A non-zero version must match the stored
+// version.
+func (r *userRepository) Delete(id uint, version int) error {
+	query := r.db.Model(&model.User{}).Where("id = ?", id)
+	if version != 0 {
+		query = query.Where("version = ?", version)
+	}
+	result := query.Updates(map[string]interface{}{
+		"deleted_at": time.Now(),
+		"version":    gorm.Expr("version + 1"),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 && version != 0 {
+		if _, err := r.GetByID(id); err == nil {
+			return ErrVersionMismatch
+		}
+	}
+	return nil
+}
+
+// GetDeleted returns the users in the trash, most recently deleted first.
+func (r *userRepository) GetDeleted() ([]model.User, error) {
+	var users []model.User
+	if err := r.db.Unscoped().Where("deleted_at IS NOT NULL").Order("deleted_at DESC, id").Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
 }
 
-func (r *userRepository) Delete(id uint) error {
-	return r.db.Delete(&model.User{}, id).Error
+// Restore takes a user out of the trash.
+func (r *userRepository) Restore(id uint) error {
+	result := r.db.Unscoped().Model(&model.User{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetRevisions returns the history of a user, newest first.
+func (r *userRepository) GetRevisions(userID uint) ([]model.UserRevision, error) {
+	var revisions []model.UserRevision
+	if err := r.db.Where("user_id = ?", userID).Order("revision DESC").Find(&revisions).Error; err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// Purge permanently deletes a user in the trash together with their
+// reviews, reports, holds, lists, reading history and change history. Loans
+// and fine entries are kept as library records. Users with loans out or
+// fines owed are kept.
+func (r *userRepository) Purge(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var user model.User
+		err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("deleted_at IS NOT NULL").
+			First(&user, id).Error
+		if err != nil {
+			return err
+		}
+		var loans int64
+		if err := tx.Model(&model.Loan{}).Where("user_id = ? AND returned_at IS NULL", id).Count(&loans).Error; err != nil {
+			return err
+		}
+		balance, err := fineBalance(tx, id)
+		if err != nil {
+			return err
+		}
+		if loans > 0 || balance > 0 {
+			return ErrUserHasObligations
+		}
+
+		reviews := func() *gorm.DB {
+			return tx.Model(&model.Review{}).Select("id").Where("user_id = ?", id)
+		}
+		if err := tx.Where("review_id IN (?)", reviews()).Delete(&model.ReviewReport{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("review_id IN (?)", reviews()).Delete(&model.ModerationLog{}).Error; err != nil {
+			return err
+		}
+		lists := tx.Model(&model.ReadingList{}).Select("id").Where("user_id = ?", id)
+		if err := tx.Where("list_id IN (?)", lists).Delete(&model.ListEntry{}).Error; err != nil {
+			return err
+		}
+		progress := tx.Model(&model.ReadingProgress{}).Select("id").Where("user_id = ?", id)
+		if err := tx.Where("progress_id IN (?)", progress).Delete(&model.ProgressUpdate{}).Error; err != nil {
+			return err
+		}
+		owned := []interface{}{
+			&model.Review{}, &model.ReviewReport{}, &model.ReviewBan{}, &model.Hold{},
+			&model.ReadingList{}, &model.ReadingProgress{}, &model.ReadingGoal{}, &model.UserRevision{},
+		}
+		for _, record := range owned {
+			if err := tx.Where("user_id = ?", id).Delete(record).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Unscoped().Delete(&user).Error
+	})
+}
+
+// recordUserRevision adds the next revision of a user. Updates that change
+// nothing are not recorded.
+func recordUserRevision(tx *gorm.DB, userID uint, actor, note string, before, after map[string]interface{}) error {
+	changes := model.DiffUserFields(before, after)
+	if len(changes) == 0 {
+		return nil
+	}
+	var last int
+	err := tx.Model(&model.UserRevision{}).
+		Select("COALESCE(MAX(revision), 0)").
+		Where("user_id = ?", userID).
+		Scan(&last).Error
+	if err != nil {
+		return err
+	}
+	return tx.Create(&model.UserRevision{
+		UserID:   userID,
+		Revision: last + 1,
+		Action:   model.RevisionUpdate,
+		Actor:    actor,
+		Note:     note,
+		Changes:  changes,
+	}).Error
 }
@@ -0,0 +1,235 @@
+package repository
+
+import (
+	"errors"
+
+	"go.test/model"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrNotEdition  = errors.New("book is not an edition of this work")
+	ErrNotInSeries = errors.New("work is not in this series")
+)
+
+type WorkRepository interface {
+	GetByID(id uint) (*model.Work, error)
+	GetNeighbors(work *model.Work) (previous, next *model.Work, err error)
+	Create(work *model.Work) error
+	Update(work *model.Work) error
+	Delete(id uint) error
+	AddEdition(workID, bookID uint) error
+	RemoveEdition(workID, bookID uint) error
+	GetAllSeries() ([]model.Series, error)
+	GetSeries(id uint) (*model.Series, error)
+	CreateSeries(series *model.Series) error
+	UpdateSeries(series *model.Series) error
+	DeleteSeries(id uint) error
+	SetSeriesEntry(seriesID, workID uint, position float64) error
+	RemoveSeriesEntry(seriesID, workID uint) error
+}
+
+type workRepository struct {
+	db *gorm.DB
+}
+
+func NewWorkRepository(db *gorm.DB) WorkRepository {
+	return &workRepository{db}
+}
+
+// orderEditions orders the editions of a work by when they were published,
+// those without a date last.
+func orderEditions(db *gorm.DB) *gorm.DB {
+	return db.Order("published_on IS NULL, published_on, id")
+}
+
+// orderBySeriesPosition orders the works of a series.
+func orderBySeriesPosition(db *gorm.DB) *gorm.DB {
+	return db.Order("series_position, id")
+}
+
+// GetByID returns a work with its editions.
+func (r *workRepository) GetByID(id uint) (*model.Work, error) {
+	var work model.Work
+	if err := r.db.Preload("Editions", orderEditions).First(&work, id).Error; err != nil {
+		return nil, err
+	}
+	return &work, nil
+}
+
+// GetNeighbors returns the works just before and after a work in its
+// series, without their editions. Either is nil at the ends of the series,
+// and both are if the work is not in one.
+func (r *workRepository) GetNeighbors(work *model.Work) (*model.Work, *model.Work, error) {
+	if work.SeriesID == nil {
+		return nil, nil, nil
+	}
+	neighbor := func(cmp, order string) (*model.Work, error) {
+		var found model.Work
+		err := r.db.Where("series_id = ?", *work.SeriesID).
+			Where("series_position "+cmp+" ? OR (series_position = ? AND id "+cmp+" ?)", *work.SeriesPosition, *work.SeriesPosition, work.ID).
+			Order("series_position " + order + ", id " + order).
+			First(&found).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &found, nil
+	}
+	previous, err := neighbor("<", "DESC")
+	if err != nil {
+		return nil, nil, err
+	}
+	next, err := neighbor(">", "ASC")
+	if err != nil {
+		return nil, nil, err
+	}
+	return previous, next, nil
+}
+
+func (r *workRepository) Create(work *model.Work) error {
+	return r.db.Omit("Editions").Create(work).Error
+}
+
+// Update saves the title and author of a work. Its place in a series is
+// changed through the series.
+func (r *workRepository) Update(work *model.Work) error {
+	result := r.db.Model(work).Select("title", "author", "updated_at").Updates(work)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Delete deletes a work. Its editions, including those in the trash, are
+// left as books of their own.
+func (r *workRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&model.Book{}).Where("work_id = ?", id).UpdateColumn("work_id", nil).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&model.Work{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// AddEdition makes a book an edition of a work, taking it from the work it
+// was an edition of, if any. Editions are not books' history, so the book
+// keeps its version.
+func (r *workRepository) AddEdition(workID, bookID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Select("id").First(&model.Work{}, workID).Error; err != nil {
+			return err
+		}
+		if err := tx.Select("id").First(&model.Book{}, bookID).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.Book{}).Where("id = ?", bookID).UpdateColumn("work_id", workID).Error
+	})
+}
+
+func (r *workRepository) RemoveEdition(workID, bookID uint) error {
+	result := r.db.Model(&model.Book{}).Where("id = ? AND work_id = ?", bookID, workID).UpdateColumn("work_id", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotEdition
+	}
+	return nil
+}
+
+// GetAllSeries returns every series, without its works, by title.
+func (r *workRepository) GetAllSeries() ([]model.Series, error) {
+	var series []model.Series
+	if err := r.db.Order("title, id").Find(&series).Error; err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+// GetSeries returns a series with its works in order and their editions.
+func (r *workRepository) GetSeries(id uint) (*model.Series, error) {
+	var series model.Series
+	err := r.db.Preload("Works", orderBySeriesPosition).
+		Preload("Works.Editions", orderEditions).
+		First(&series, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &series, nil
+}
+
+func (r *workRepository) CreateSeries(series *model.Series) error {
+	return r.db.Omit("Works").Create(series).Error
+}
+
+func (r *workRepository) UpdateSeries(series *model.Series) error {
+	result := r.db.Model(series).Select("title", "description", "updated_at").Updates(series)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteSeries deletes a series. Its works are kept, out of any series.
+func (r *workRepository) DeleteSeries(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.Work{}).Where("series_id = ?", id).
+			Updates(map[string]interface{}{"series_id": nil, "series_position": nil}).Error
+		if err != nil {
+			return err
+		}
+		result := tx.Delete(&model.Series{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// SetSeriesEntry puts a work at a position in a series, taking it from the
+// series it was in, if any. Works may share a position; they are then
+// ordered by ID.
+func (r *workRepository) SetSeriesEntry(seriesID, workID uint, position float64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Select("id").First(&model.Series{}, seriesID).Error; err != nil {
+			return err
+		}
+		if err := tx.Select("id").First(&model.Work{}, workID).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.Work{}).Where("id = ?", workID).
+			Updates(map[string]interface{}{"series_id": seriesID, "series_position": position}).Error
+	})
+}
+
+func (r *workRepository) RemoveSeriesEntry(seriesID, workID uint) error {
+	result := r.db.Model(&model.Work{}).Where("id = ? AND series_id = ?", workID, seriesID).
+		Updates(map[string]interface{}{"series_id": nil, "series_position": nil})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotInSeries
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package storage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"os"
+	"time"
+)
+
+// ContentStore keeps files under the SHA-256 of their contents, so that a
+// file that is uploaded twice is stored once, and checks files against
+// their hash as they are read. Since files are shared, callers delete one
+// only when nothing refers to its hash any more.
+type ContentStore struct {
+	blobs BlobStore
+}
+
+func NewContentStore(blobs BlobStore) *ContentStore {
+	return &ContentStore{blobs}
+}
+
+// ContentKey returns the key a file with the given hash is stored under,
+// as in "sha256/ab/ab12...".
+func ContentKey(sum string) string {
+	return "sha256/" + sum[:2] + "/" + sum
+}
+
+// Put stores the contents of r unless a file with the same hash is stored
+// already, and returns their hash and size.
+func (s *ContentStore) Put(r io.Reader) (sum string, size int64, err error) {
+	spool, err := os.CreateTemp("", "blob-*")
+	if err != nil {
+		return "", 0, err
+	}
+	defer os.Remove(spool.Name())
+	defer spool.Close()
+	hash := sha256.New()
+	if size, err = io.Copy(io.MultiWriter(spool, hash), r); err != nil {
+		return "", 0, err
+	}
+	sum = hex.EncodeToString(hash.Sum(nil))
+	if info, err := s.blobs.Stat(ContentKey(sum)); err == nil && info.Size == size {
+		return sum, size, nil
+	} else if err != nil && !errors.Is(err, ErrNotFound) {
+		return "", 0, err
+	}
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		return "", 0, err
+	}
+	if err := s.blobs.Put(ContentKey(sum), spool); err != nil {
+		return "", 0, err
+	}
+	return sum, size, nil
+}
+
+// Get returns the file with the given hash. Reading it from the start to the
+// end gives ErrChecksumMismatch if it has been damaged. The file can seek
+// if the store's files can, but parts of it are not checked.
+func (s *ContentStore) Get(sum string) (io.ReadCloser, error) {
+	if !validSum(sum) {
+		return nil, ErrInvalidKey
+	}
+	file, err := s.blobs.Get(ContentKey(sum))
+	if err != nil {
+		return nil, err
+	}
+	return &verifyingReader{ReadCloser: file, hash: sha256.New(), sum: sum, size: -1, verify: true}, nil
+}
+
+func (s *ContentStore) Stat(sum string) (*BlobInfo, error) {
+	if !validSum(sum) {
+		return nil, ErrInvalidKey
+	}
+	return s.blobs.Stat(ContentKey(sum))
+}
+
+func (s *ContentStore) Delete(sum string) error {
+	if !validSum(sum) {
+		return ErrInvalidKey
+	}
+	return s.blobs.Delete(ContentKey(sum))
+}
+
+// PresignURL returns an address the file with the given hash can be
+// downloaded from until expiry has passed. Downloads from it are not
+// checked against the hash.
+func (s *ContentStore) PresignURL(sum string, expiry time.Duration) (string, error) {
+	if !validSum(sum) {
+		return "", ErrInvalidKey
+	}
+	return s.blobs.PresignURL(ContentKey(sum), expiry)
+}
+
+func validSum(sum string) bool {
+	if len(sum) != sha256.Size*2 {
+		return false
+	}
+	decoded, err := hex.DecodeString(sum)
+	return err == nil && hex.EncodeToString(decoded) == sum
+}
+
+// verifyingReader checks a file against its hash once it has been read
+// from the start to the end, which is its size if a seek has told it that,
+// or else io.EOF.
+type verifyingReader struct {
+	io.ReadCloser
+	hash   hash.Hash
+	sum    string
+	pos    int64
+	size   int64
+	verify bool
+}
+
+func (r *verifyingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.pos += int64(n)
+	if !r.verify {
+		return n, err
+	}
+	r.hash.Write(p[:n])
+	if err == io.EOF || r.pos == r.size {
+		r.verify = false
+		if hex.EncodeToString(r.hash.Sum(nil)) != r.sum {
+			return n, ErrChecksumMismatch
+		}
+	}
+	return n, err
+}
+
+// Seek moves within the file if the store's files can seek. Only reading
+// from the start is checked.
+func (r *verifyingReader) Seek(offset int64, whence int) (int64, error) {
+	seeker, ok := r.ReadCloser.(io.Seeker)
+	if !ok {
+		return 0, errors.New("stored file cannot seek")
+	}
+	pos, err := seeker.Seek(offset, whence)
+	if err != nil {
+		return pos, err
+	}
+	if whence == io.SeekEnd && offset == 0 {
+		r.size = pos
+	}
+	r.pos = pos
+	r.verify = pos == 0
+	if r.verify {
+		r.hash.Reset()
+	}
+	return pos, nil
+}
@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentStore(t *testing.T) {
+	root := t.TempDir()
+	blobs, _ := NewFSStore(root, nil)
+	s := NewContentStore(blobs)
+
+	sum, size, err := s.Put(strings.NewReader("hello"))
+	assert.NoError(t, err)
+	assert.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", sum)
+	assert.Equal(t, int64(5), size)
+	again, _, err := s.Put(strings.NewReader("hello"))
+	assert.NoError(t, err)
+	assert.Equal(t, sum, again)
+	stored, _ := blobs.List("sha256/")
+	assert.Len(t, stored, 1, "the same contents are stored once")
+	assert.Equal(t, "sha256/2c/"+sum, stored[0].Key)
+
+	file, err := s.Get(sum)
+	if assert.NoError(t, err) {
+		data, err := io.ReadAll(file)
+		file.Close()
+		assert.NoError(t, err)
+		assert.Equal(t, "hello", string(data))
+	}
+
+	os.WriteFile(filepath.Join(root, "sha256", "2c", sum), []byte("jello"), 0o644)
+	file, err = s.Get(sum)
+	if assert.NoError(t, err) {
+		_, err := io.ReadAll(file)
+		file.Close()
+		assert.ErrorIs(t, err, ErrChecksumMismatch)
+	}
+
+	// Reads that know the size, as http.ServeContent's do, are checked when
+	// they reach it; reads of a part are not.
+	file, _ = s.Get(sum)
+	seeker := file.(io.ReadSeeker)
+	seeker.Seek(0, io.SeekEnd)
+	seeker.Seek(0, io.SeekStart)
+	_, err = file.Read(make([]byte, 5))
+	assert.ErrorIs(t, err, ErrChecksumMismatch)
+	seeker.Seek(1, io.SeekStart)
+	data, err := io.ReadAll(file)
+	assert.NoError(t, err)
+	assert.Equal(t, "ello", string(data))
+	file.Close()
+
+	_, err = s.Get(strings.ToUpper(sum))
+	assert.ErrorIs(t, err, ErrInvalidKey)
+	_, err = s.Get("../../etc/passwd")
+	assert.ErrorIs(t, err, ErrInvalidKey)
+
+	assert.NoError(t, s.Delete(sum))
+	_, err = s.Get(sum)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFSStore(t *testing.T) {
+	root := t.TempDir()
+	s, err := NewFSStore(root, NewURLSigner("/api", []byte("secret")))
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.Put("covers/1/v1/original", strings.NewReader("first")))
+	assert.NoError(t, s.Put("covers/1/v1/original", strings.NewReader("second")))
+	file, err := s.Get("covers/1/v1/original")
+	if assert.NoError(t, err) {
+		data, _ := io.ReadAll(file)
+		file.Close()
+		assert.Equal(t, "second", string(data))
+	}
+	entries, _ := os.ReadDir(filepath.Join(root, "covers", "1", "v1"))
+	assert.Len(t, entries, 1, "no temporary files are left behind")
+	info, err := s.Stat("covers/1/v1/original")
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(6), info.Size)
+	}
+
+	assert.NoError(t, s.Delete("covers/1/v1/original"))
+	assert.NoError(t, s.Delete("covers/1/v1/original"))
+	_, err = s.Get("covers/1/v1/original")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = s.Stat("covers/1")
+	assert.ErrorIs(t, err, ErrNotFound, "directories are not files")
+
+	for _, key := range []string{"../outside", "/etc/passwd", "covers//1", ".", ""} {
+		assert.ErrorIs(t, s.Put(key, strings.NewReader("x")), ErrInvalidKey, key)
+	}
+}
+
+func TestFSStoreList(t *testing.T) {
+	s, _ := NewFSStore(t.TempDir(), nil)
+	for _, key := range []string{"covers/10/a", "covers/1/b", "covers/1/a", "ebooks/1"} {
+		assert.NoError(t, s.Put(key, strings.NewReader(key)))
+	}
+	keys := func(prefix string) []string {
+		blobs, err := s.List(prefix)
+		assert.NoError(t, err)
+		var keys []string
+		for _, blob := range blobs {
+			keys = append(keys, blob.Key)
+		}
+		return keys
+	}
+	assert.Equal(t, []string{"covers/1/a", "covers/1/b", "covers/10/a", "ebooks/1"}, keys(""))
+	assert.Equal(t, []string{"covers/1/a", "covers/1/b", "covers/10/a"}, keys("covers/1"))
+	assert.Equal(t, []string{"covers/1/a", "covers/1/b"}, keys("covers/1/"))
+	assert.Empty(t, keys("missing/"))
+}
+
+func TestURLSigner(t *testing.T) {
+	signer := NewURLSigner("/api/", []byte("secret"))
+	now := time.Unix(1700000000, 0)
+	signed := signer.Sign("ebooks/1/my book.epub", now.Add(time.Minute))
+	u, err := url.Parse(signed)
+	assert.NoError(t, err)
+	assert.Equal(t, "/api/ebooks/1/my book.epub", u.Path)
+
+	expires, signature := u.Query().Get("expires"), u.Query().Get("signature")
+	assert.NoError(t, signer.Verify("ebooks/1/my book.epub", expires, signature, now))
+	assert.ErrorIs(t, signer.Verify("ebooks/2/my book.epub", expires, signature, now), ErrInvalidSignature)
+	assert.ErrorIs(t, signer.Verify("ebooks/1/my book.epub", "1800000000", signature, now), ErrInvalidSignature)
+	assert.ErrorIs(t, signer.Verify("ebooks/1/my book.epub", expires, signature, now.Add(2*time.Minute)), ErrExpiredURL)
+	assert.ErrorIs(t, NewURLSigner("/api", []byte("other")).Verify("ebooks/1/my book.epub", expires, signature, now), ErrInvalidSignature)
+}
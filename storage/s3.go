@@ -0,0 +1,339 @@
+package storage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// S3Config says where an S3-compatible bucket is and how to sign in to it.
+type S3Config struct {
+	// Endpoint is the base URL of the service, such as
+	// "https://s3.eu-central-1.amazonaws.com" or "http://localhost:9000".
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// PathStyle puts the bucket in the path rather than in the host name,
+	// as MinIO and most other S3-compatible services need.
+	PathStyle bool
+	// Client sends the requests; http.DefaultClient if nil.
+	Client *http.Client
+}
+
+type s3Store struct {
+	endpoint  *url.URL
+	bucket    string
+	pathStyle bool
+	signer    sigV4
+	client    *http.Client
+	now       func() time.Time
+}
+
+// NewS3Store returns a BlobStore that keeps files as the objects of an S3
+// bucket.
+func NewS3Store(config S3Config) (BlobStore, error) {
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", config.Endpoint)
+	}
+	if config.Bucket == "" {
+		return nil, fmt.Errorf("no S3 bucket given")
+	}
+	client := config.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	region := config.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	return &s3Store{
+		endpoint:  endpoint,
+		bucket:    config.Bucket,
+		pathStyle: config.PathStyle,
+		signer:    sigV4{config.AccessKeyID, config.SecretAccessKey, region},
+		client:    client,
+		now:       time.Now,
+	}, nil
+}
+
+// Put uploads a file with its SHA-256, which S3 checks. Readers that cannot
+// seek are spooled to a temporary file first, since the hash and length
+// have to be known before the upload starts.
+func (s *s3Store) Put(key string, r io.Reader) error {
+	if !validKey(key) {
+		return ErrInvalidKey
+	}
+	body, ok := r.(io.ReadSeeker)
+	if !ok {
+		spool, err := os.CreateTemp("", "s3-upload-*")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(spool.Name())
+		defer spool.Close()
+		if _, err := io.Copy(spool, r); err != nil {
+			return err
+		}
+		if _, err := spool.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		body = spool
+	}
+	start, err := body.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	hash := sha256.New()
+	size, err := io.Copy(hash, body)
+	if err != nil {
+		return err
+	}
+	if _, err := body.Seek(start, io.SeekStart); err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPut, s.objectURL(key).String(), io.NopCloser(body))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	resp, err := s.do(req, hex.EncodeToString(hash.Sum(nil)))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Get returns an object that can seek. Reading after a seek asks S3 for the
+// rest of the object from the new offset.
+func (s *s3Store) Get(key string) (io.ReadCloser, error) {
+	if !validKey(key) {
+		return nil, ErrInvalidKey
+	}
+	body, size, err := s.getRange(key, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &s3Object{store: s, key: key, body: body, size: size}, nil
+}
+
+// getRange returns an object from offset on, and the size of the whole
+// object.
+func (s *s3Store) getRange(key string, offset int64) (io.ReadCloser, int64, error) {
+	req, err := http.NewRequest(http.MethodGet, s.objectURL(key).String(), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", "bytes="+strconv.FormatInt(offset, 10)+"-")
+	}
+	resp, err := s.do(req, emptyPayloadHash)
+	if err != nil {
+		return nil, 0, err
+	}
+	size := resp.ContentLength
+	if resp.StatusCode == http.StatusPartialContent {
+		// Content-Range: bytes 100-199/200
+		_, total, _ := strings.Cut(resp.Header.Get("Content-Range"), "/")
+		if size, err = strconv.ParseInt(total, 10, 64); err != nil {
+			resp.Body.Close()
+			return nil, 0, fmt.Errorf("S3 GET %s: bad Content-Range %q", key, resp.Header.Get("Content-Range"))
+		}
+	}
+	return resp.Body, size, nil
+}
+
+func (s *s3Store) Stat(key string) (*BlobInfo, error) {
+	if !validKey(key) {
+		return nil, ErrInvalidKey
+	}
+	req, err := http.NewRequest(http.MethodHead, s.objectURL(key).String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.do(req, emptyPayloadHash)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	updatedAt, _ := http.ParseTime(resp.Header.Get("Last-Modified"))
+	return &BlobInfo{Key: key, Size: resp.ContentLength, UpdatedAt: updatedAt}, nil
+}
+
+func (s *s3Store) Delete(key string) error {
+	if !validKey(key) {
+		return ErrInvalidKey
+	}
+	req, err := http.NewRequest(http.MethodDelete, s.objectURL(key).String(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(req, emptyPayloadHash)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+type s3ListResult struct {
+	Contents []struct {
+		Key          string
+		Size         int64
+		LastModified time.Time
+	}
+	IsTruncated           bool
+	NextContinuationToken string
+}
+
+// List pages through ListObjectsV2, which returns keys in order.
+func (s *s3Store) List(prefix string) ([]BlobInfo, error) {
+	var blobs []BlobInfo
+	token := ""
+	for {
+		u := s.objectURL("")
+		query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		u.RawQuery = canonicalQuery(query)
+		req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := s.do(req, emptyPayloadHash)
+		if err != nil {
+			return nil, err
+		}
+		var result s3ListResult
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("listing S3 objects: %w", err)
+		}
+		for _, object := range result.Contents {
+			blobs = append(blobs, BlobInfo{Key: object.Key, Size: object.Size, UpdatedAt: object.LastModified})
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return blobs, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+func (s *s3Store) PresignURL(key string, expiry time.Duration) (string, error) {
+	if !validKey(key) {
+		return "", ErrInvalidKey
+	}
+	return s.signer.presign(http.MethodGet, s.objectURL(key), expiry, s.now()), nil
+}
+
+// objectURL returns the address of an object, or of the bucket for an
+// empty key.
+func (s *s3Store) objectURL(key string) *url.URL {
+	u := *s.endpoint
+	path := strings.TrimSuffix(u.Path, "/")
+	if s.pathStyle {
+		path += "/" + s.bucket
+	} else {
+		u.Host = s.bucket + "." + u.Host
+	}
+	if key != "" || !s.pathStyle {
+		path += "/"
+	}
+	u.Path = path + key
+	u.RawPath = uriEncode(path, false) + uriEncode(key, false)
+	return &u
+}
+
+type s3Error struct {
+	Code    string
+	Message string
+}
+
+// do signs and sends a request. Missing objects give ErrNotFound and other
+// failures the error S3 reports.
+func (s *s3Store) do(req *http.Request, payloadHash string) (*http.Response, error) {
+	s.signer.sign(req, payloadHash, s.now())
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound && req.URL.RawQuery == "" {
+		return nil, ErrNotFound
+	}
+	var s3Err s3Error
+	if xml.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&s3Err) == nil && s3Err.Code != "" {
+		return nil, fmt.Errorf("S3 %s %s: %s: %s", req.Method, req.URL.Path, s3Err.Code, s3Err.Message)
+	}
+	return nil, fmt.Errorf("S3 %s %s: %s", req.Method, req.URL.Path, resp.Status)
+}
+
+// s3Object is an object being read, which can seek.
+type s3Object struct {
+	store  *s3Store
+	key    string
+	body   io.ReadCloser // nil after a seek, until the next read
+	size   int64
+	offset int64
+}
+
+func (o *s3Object) Read(p []byte) (int, error) {
+	if o.body == nil {
+		if o.offset >= o.size {
+			return 0, io.EOF
+		}
+		body, _, err := o.store.getRange(o.key, o.offset)
+		if err != nil {
+			return 0, err
+		}
+		o.body = body
+	}
+	n, err := o.body.Read(p)
+	o.offset += int64(n)
+	return n, err
+}
+
+func (o *s3Object) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += o.offset
+	case io.SeekEnd:
+		offset += o.size
+	}
+	if offset < 0 {
+		return 0, errors.New("seek before the start of an S3 object")
+	}
+	if offset != o.offset && o.body != nil {
+		o.body.Close()
+		o.body = nil
+	}
+	o.offset = offset
+	return offset, nil
+}
+
+func (o *s3Object) Close() error {
+	if o.body == nil {
+		return nil
+	}
+	return o.body.Close()
+}
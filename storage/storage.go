@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"time"
+)
+
+var (
+	ErrNotFound         = errors.New("stored file not found")
+	ErrInvalidKey       = errors.New("invalid storage key")
+	ErrChecksumMismatch = errors.New("stored file does not match its checksum")
+)
+
+// BlobStore keeps files under slash-separated keys such as
+// "covers/1/0123456789abcdef/small".
+type BlobStore interface {
+	// Put stores the contents of r under key, replacing any file there.
+	// Readers never see a file that is only partly written.
+	Put(key string, r io.Reader) error
+	// Get returns the file stored under key, or ErrNotFound.
+	Get(key string) (io.ReadCloser, error)
+	// Stat describes the file stored under key, or returns ErrNotFound.
+	Stat(key string) (*BlobInfo, error)
+	// Delete removes the file stored under key. Deleting a missing file is
+	// not an error.
+	Delete(key string) error
+	// List describes the files whose keys start with prefix, in key order.
+	List(prefix string) ([]BlobInfo, error)
+	// PresignURL returns an address the file stored under key can be
+	// downloaded from without other credentials until expiry has passed.
+	PresignURL(key string, expiry time.Duration) (string, error)
+}
+
+type BlobInfo struct {
+	Key       string    `json:"key"`
+	Size      int64     `json:"size"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// validKey reports whether a key can be stored. Keys are relative paths
+// without "." or ".." elements, so that they cannot reach outside the
+// filesystem root or change meaning in a URL.
+func validKey(key string) bool {
+	return fs.ValidPath(key) && key != "."
+}
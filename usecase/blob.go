@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"io"
+	"time"
+
+	"go.test/storage"
+)
+
+type BlobUsecase interface {
+	OpenSigned(key, expires, signature string) (io.ReadCloser, *storage.BlobInfo, error)
+}
+
+type blobUsecase struct {
+	store  storage.BlobStore
+	signer *storage.URLSigner
+}
+
+// NewBlobUsecase returns a BlobUsecase that serves the files of store at
+// the addresses signer signs, for stores that cannot serve files
+// themselves.
+func NewBlobUsecase(store storage.BlobStore, signer *storage.URLSigner) BlobUsecase {
+	return &blobUsecase{store, signer}
+}
+
+// OpenSigned returns a stored file if the signature of its address is good
+// and has not expired.
+func (u *blobUsecase) OpenSigned(key, expires, signature string) (io.ReadCloser, *storage.BlobInfo, error) {
+	if err := u.signer.Verify("blobs/"+key, expires, signature, time.Now()); err != nil {
+		return nil, nil, err
+	}
+	info, err := u.store.Stat(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	file, err := u.store.Get(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return file, info, nil
+}
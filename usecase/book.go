@@ -1,42 +1,194 @@
 package usecase
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+
 	"go.test/model"
 	"go.test/repository"
 )
 
 type BookUsecase interface {
-	GetAllBooks() ([]model.Book, error)
-	GetBookByID(id uint) (*model.Book, error)
-	CreateBook(book *model.Book) error
-	UpdateBook(book *model.Book) error
-	DeleteBook(id uint) error
+	GetAllBooks(filter *model.BookFilter) ([]model.Book, error)
+	GetBookByID(id uint, username string) (*model.Book, error)
+	CreateBook(book *model.Book, actor string) error
+	UpdateBook(book *model.Book, actor string) error
+	PatchBook(id uint, version int, mediaType string, patch []byte, actor string) (*model.Book, error)
+	DeleteBook(id uint, version int, actor string) error
+	GetHistory(id uint) ([]model.BookRevision, error)
+	RevertBook(id uint, revision int, actor string) (*model.Book, error)
+	MigratePublishedDates() ([]model.UnparsedDate, error)
+	MigrateISBNKeys() error
 }
 
+var (
+	ErrVersionMismatch = repository.ErrVersionMismatch
+	ErrInvalidCollapse = errors.New("collapse must be work")
+)
+
 type bookUsecase struct {
-	bookRepo repository.BookRepository
+	bookRepo   repository.BookRepository
+	copyRepo   repository.CopyRepository
+	reviewRepo repository.ReviewRepository
+	listRepo   repository.ListRepository
+	userRepo   repository.UserRepository
+	coverRepo  repository.CoverRepository
+}
+
+func NewBookUsecase(bookRepo repository.BookRepository, copyRepo repository.CopyRepository, reviewRepo repository.ReviewRepository, listRepo repository.ListRepository, userRepo repository.UserRepository, coverRepo repository.CoverRepository) BookUsecase {
+	return &bookUsecase{bookRepo, copyRepo, reviewRepo, listRepo, userRepo, coverRepo}
+}
+
+func (u *bookUsecase) GetAllBooks(filter *model.BookFilter) ([]model.Book, error) {
+	if filter.Collapse != "" && filter.Collapse != model.BookCollapseWork {
+		return nil, ErrInvalidCollapse
+	}
+	books, err := u.bookRepo.GetAll(filter)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.decorate(books); err != nil {
+		return nil, err
+	}
+	return books, nil
+}
+
+// GetBookByID returns a book along with the lists of the given user that
+// contain it. An empty username leaves the lists out.
+func (u *bookUsecase) GetBookByID(id uint, username string) (*model.Book, error) {
+	book, err := u.bookRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	books := []model.Book{*book}
+	if err := u.decorate(books); err != nil {
+		return nil, err
+	}
+	if username != "" {
+		user, err := u.userRepo.GetByUsername(username)
+		if err != nil {
+			return nil, err
+		}
+		if books[0].Lists, err = u.listRepo.GetMemberships(user.ID, book.ID); err != nil {
+			return nil, err
+		}
+	}
+	return &books[0], nil
+}
+
+// CreateBook adds a book. Books are made editions of works through the
+// work, so a work ID in book is ignored.
+func (u *bookUsecase) CreateBook(book *model.Book, actor string) error {
+	book.WorkID = nil
+	return u.bookRepo.Create(book, actor)
+}
+
+// UpdateBook saves changes to an existing book. Books in the trash have to
+// be restored first; saving them directly would bring them back. A non-zero
+// book.Version has to match the stored version.
+func (u *bookUsecase) UpdateBook(book *model.Book, actor string) error {
+	return u.bookRepo.Update(book, actor)
+}
+
+// PatchBook applies a JSON Merge Patch or JSON Patch to a book's fields.
+// The patch is applied to the stored book under lock, so it cannot lose a
+// concurrent change, and the patched book must still have a title.
+func (u *bookUsecase) PatchBook(id uint, version int, mediaType string, patch []byte, actor string) (*model.Book, error) {
+	book, err := u.bookRepo.Patch(id, version, actor, patchNote(mediaType), func(book *model.Book) error {
+		fields, err := patchFields(mediaType, book.Fields(), patch)
+		if err != nil {
+			return err
+		}
+		if _, err := model.ParsePartialDate(fields["published_date"].(string)); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+		}
+		book.SetFields(fields)
+		if strings.TrimSpace(book.Title) == "" {
+			return fmt.Errorf("%w: title is required", ErrInvalidPatch)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	books := []model.Book{*book}
+	if err := u.decorate(books); err != nil {
+		return nil, err
+	}
+	return &books[0], nil
 }
 
-func NewBookUsecase(bookRepo repository.BookRepository) BookUsecase {
-	return &bookUsecase{bookRepo}
+// MigratePublishedDates reads the published dates kept from before dates
+// were checked and returns those that are not dates.
+func (u *bookUsecase) MigratePublishedDates() ([]model.UnparsedDate, error) {
+	return u.bookRepo.MigratePublishedDates()
 }
 
-func (u *bookUsecase) GetAllBooks() ([]model.Book, error) {
-	return u.bookRepo.GetAll()
+// MigrateISBNKeys fills in the keys imports match books on by ISBN for
+// books saved before there were any.
+func (u *bookUsecase) MigrateISBNKeys() error {
+	return u.bookRepo.MigrateISBNKeys()
 }
 
-func (u *bookUsecase) GetBookByID(id uint) (*model.Book, error) {
-	return u.bookRepo.GetByID(id)
+func (u *bookUsecase) DeleteBook(id uint, version int, actor string) error {
+	return u.bookRepo.Delete(id, version, actor)
 }
 
-func (u *bookUsecase) CreateBook(book *model.Book) error {
-	return u.bookRepo.Create(book)
+// GetHistory returns the revisions of a book, newest first, including books
+// in the trash.
+func (u *bookUsecase) GetHistory(id uint) ([]model.BookRevision, error) {
+	revisions, err := u.bookRepo.GetRevisions(id)
+	if err != nil {
+		return nil, err
+	}
+	if len(revisions) == 0 {
+		if _, err := u.bookRepo.GetByID(id); err != nil {
+			return nil, err
+		}
+	}
+	return revisions, nil
 }
 
-func (u *bookUsecase) UpdateBook(book *model.Book) error {
-	return u.bookRepo.Update(book)
+func (u *bookUsecase) RevertBook(id uint, revision int, actor string) (*model.Book, error) {
+	book, err := u.bookRepo.Revert(id, revision, actor)
+	if err != nil {
+		return nil, err
+	}
+	books := []model.Book{*book}
+	if err := u.decorate(books); err != nil {
+		return nil, err
+	}
+	return &books[0], nil
 }
 
-func (u *bookUsecase) DeleteBook(id uint) error {
-	return u.bookRepo.Delete(id)
+// decorate fills in the availability and rating summaries of the books and
+// the addresses of their covers.
+func (u *bookUsecase) decorate(books []model.Book) error {
+	ids := make([]uint, len(books))
+	for i := range books {
+		ids[i] = books[i].ID
+	}
+	availability, err := u.copyRepo.GetAvailability(ids)
+	if err != nil {
+		return err
+	}
+	ratings, err := u.reviewRepo.GetStats(ids)
+	if err != nil {
+		return err
+	}
+	covers, err := u.coverRepo.GetByBookIDs(ids)
+	if err != nil {
+		return err
+	}
+	for i := range books {
+		books[i].Availability = availability[books[i].ID]
+		books[i].AverageRating = ratings[books[i].ID].Average
+		books[i].ReviewCount = ratings[books[i].ID].Count
+		if cover, ok := covers[books[i].ID]; ok {
+			cover.SetURL()
+			books[i].CoverURL = cover.URL
+		}
+	}
+	return nil
 }
@@ -0,0 +1,271 @@
+package usecase
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"go.test/model"
+	"go.test/repository"
+	util "go.test/utils"
+)
+
+const (
+	CitationBibTeX   = "bibtex"
+	CitationRIS      = "ris"
+	CitationCSLJSON  = "csljson"
+	CitationAPA      = "apa"
+	CitationMLA      = "mla"
+	CitationChicago  = "chicago"
+	maxCitationBooks = 100
+)
+
+var (
+	ErrUnknownCitationFormat = errors.New("format must be bibtex, ris, csljson, apa, mla or chicago")
+	ErrTooManyCitations      = errors.New("at most 100 books can be cited at once")
+)
+
+type CitationUsecase interface {
+	CiteBooks(ids []uint, format string, html bool) (text, contentType string, err error)
+}
+
+type citationUsecase struct {
+	bookRepo repository.BookRepository
+}
+
+func NewCitationUsecase(bookRepo repository.BookRepository) CitationUsecase {
+	return &citationUsecase{bookRepo}
+}
+
+// CiteBooks cites books in a reference manager format or as a bibliography
+// in a citation style, APA unless the format says otherwise. Styles are
+// plain text, or HTML with the titles in italics if html is set; their
+// entries are sorted as in a bibliography. Other formats keep the order of
+// ids.
+func (u *citationUsecase) CiteBooks(ids []uint, format string, html bool) (string, string, error) {
+	if format == "" {
+		format = CitationAPA
+	}
+	cite, ok := citationFormats[format]
+	if !ok {
+		return "", "", ErrUnknownCitationFormat
+	}
+	ids = uniqueIDs(ids)
+	if len(ids) > maxCitationBooks {
+		return "", "", ErrTooManyCitations
+	}
+	books, err := u.bookRepo.GetByIDs(ids)
+	if err != nil {
+		return "", "", err
+	}
+	cited := make([]citedBook, len(books))
+	for i := range books {
+		cited[i] = newCitedBook(&books[i])
+	}
+	contentType := cite.contentType
+	if cite.style != nil {
+		contentType = "text/plain; charset=utf-8"
+		if html {
+			contentType = "text/html; charset=utf-8"
+		}
+		return bibliography(cited, cite.style, html), contentType, nil
+	}
+	return cite.write(cited), contentType, nil
+}
+
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	unique := ids[:0:0]
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// citeName is the name of an author as citations need it. Organizations
+// and authors known by one name only have a literal name.
+type citeName struct {
+	Family  string
+	Given   string
+	Suffix  string
+	Literal string
+}
+
+// citedBook is a book with what citations make of it.
+type citedBook struct {
+	*model.Book
+	Authors []citeName
+	Year    int
+	Date    []int // year, month and day, as far as they are known
+}
+
+var authorSeparators = regexp.MustCompile(`\s+(?:and|&)\s+`)
+
+func newCitedBook(book *model.Book) citedBook {
+	cited := citedBook{Book: book, Authors: bookAuthors(book)}
+	for _, part := range []int{book.PublishedDate.Year, book.PublishedDate.Month, book.PublishedDate.Day} {
+		if part == 0 {
+			break
+		}
+		cited.Date = append(cited.Date, part)
+	}
+	if len(cited.Date) > 0 {
+		cited.Year = cited.Date[0]
+	}
+	return cited
+}
+
+// bookAuthors returns the authors of a book. The names in the MARC record a
+// book was imported from are used while its author has not been changed;
+// otherwise the author field is split into names.
+func bookAuthors(book *model.Book) []citeName {
+	if book.MARC != nil {
+		if record, err := util.UnmarshalMARC(book.MARC); err == nil {
+			if names := marcAuthors(record); len(names) > 0 && sameAuthor(record, book.Author) {
+				return names
+			}
+		}
+	}
+	return parseAuthors(book.Author)
+}
+
+func sameAuthor(record *util.MARCRecord, author string) bool {
+	for _, mapping := range marcMappings {
+		if mapping.field == "author" {
+			field := mapping.find(record)
+			return field != nil && mapping.read(field) == author
+		}
+	}
+	return false
+}
+
+// marcAuthors reads the main entry and the added entries of a record that
+// are for authors, rather than for editors, translators and the like.
+func marcAuthors(record *util.MARCRecord) []citeName {
+	var names []citeName
+	for _, field := range record.Fields {
+		name := trimISBD(field.Subfield('a'))
+		if name == "" {
+			continue
+		}
+		switch field.Tag {
+		case "700", "710":
+			relator := strings.ToLower(field.Subfield('e') + field.Subfield('4'))
+			if relator != "" && !strings.Contains(relator, "aut") {
+				continue
+			}
+		case "100", "110":
+		default:
+			continue
+		}
+		if field.Tag[1] == '1' || field.Indicators[0] != '1' {
+			names = append(names, citeName{Literal: name})
+		} else {
+			names = append(names, parseName(name))
+		}
+	}
+	return names
+}
+
+// parseAuthors splits an author field into names. Names are separated by
+// semicolons, or else by "and" or "&".
+func parseAuthors(author string) []citeName {
+	var parts []string
+	if strings.Contains(author, ";") {
+		parts = strings.Split(author, ";")
+	} else {
+		parts = authorSeparators.Split(author, -1)
+	}
+	var names []citeName
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			names = append(names, parseName(part))
+		}
+	}
+	return names
+}
+
+var nameSuffixes = map[string]bool{"Jr.": true, "Jr": true, "Sr.": true, "Sr": true, "II": true, "III": true, "IV": true}
+
+// parseName reads a name written as "Family, Given, Suffix" or as "Given
+// Family". Lower-case particles such as "van" go with the family name.
+func parseName(name string) citeName {
+	name = strings.TrimRight(strings.TrimSpace(name), " /:;,=")
+	if words := strings.Fields(name); len(words) == 0 || !nameSuffixes[words[len(words)-1]] {
+		name = trimISBD(name)
+	}
+	if family, rest, ok := strings.Cut(name, ","); ok {
+		given, suffix, _ := strings.Cut(rest, ",")
+		given, suffix = strings.TrimSpace(given), strings.TrimSpace(suffix)
+		if suffix == "" && nameSuffixes[given] {
+			parsed := parseName(family)
+			parsed.Suffix = given
+			return parsed
+		}
+		return citeName{Family: strings.TrimSpace(family), Given: given, Suffix: suffix}
+	}
+	words := strings.Fields(name)
+	if len(words) < 2 {
+		return citeName{Literal: name}
+	}
+	i := len(words) - 1
+	for i > 1 && startsLower(words[i-1]) {
+		i--
+	}
+	return citeName{Family: strings.Join(words[i:], " "), Given: strings.Join(words[:i], " ")}
+}
+
+func startsLower(word string) bool {
+	r, _ := utf8.DecodeRuneInString(word)
+	return unicode.IsLower(r)
+}
+
+// inverted writes a name family name first, as in "Le Guin, Ursula K.".
+func (n citeName) inverted() string {
+	if n.Literal != "" {
+		return n.Literal
+	}
+	name := n.Family
+	if n.Given != "" {
+		name += ", " + n.Given
+	}
+	if n.Suffix != "" {
+		name += ", " + n.Suffix
+	}
+	return name
+}
+
+// direct writes a name in reading order, as in "Ursula K. Le Guin".
+func (n citeName) direct() string {
+	if n.Literal != "" {
+		return n.Literal
+	}
+	name := strings.TrimSpace(n.Given + " " + n.Family)
+	if n.Suffix != "" {
+		name += ", " + n.Suffix
+	}
+	return name
+}
+
+// initials shortens given names to initials, as in "J.-P." for "Jean-Paul"
+// and "J. R. R." for "J.R.R.".
+func initials(given string) string {
+	var out []string
+	for _, word := range strings.FieldsFunc(given, func(r rune) bool { return r == ' ' || r == '.' }) {
+		var parts []string
+		for _, part := range strings.Split(word, "-") {
+			if r, _ := utf8.DecodeRuneInString(part); r != utf8.RuneError {
+				parts = append(parts, string(unicode.ToUpper(r))+".")
+			}
+		}
+		if len(parts) > 0 {
+			out = append(out, strings.Join(parts, "-"))
+		}
+	}
+	return strings.Join(out, " ")
+}
@@ -0,0 +1,334 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.test/model"
+)
+
+// citationFormat writes citations either in a reference manager format,
+// or as a bibliography in a citation style.
+type citationFormat struct {
+	contentType string
+	write       func(books []citedBook) string
+	style       func(book citedBook, m citeMarkup) string
+}
+
+var citationFormats = map[string]citationFormat{
+	CitationBibTeX:  {contentType: "application/x-bibtex; charset=utf-8", write: bibtex},
+	CitationRIS:     {contentType: "application/x-research-info-systems; charset=utf-8", write: ris},
+	CitationCSLJSON: {contentType: "application/vnd.citationstyles.csl+json", write: cslJSON},
+	CitationAPA:     {style: apa},
+	CitationMLA:     {style: mla},
+	CitationChicago: {style: chicago},
+}
+
+// citeMarkup writes the parts of a formatted citation as plain text or as
+// HTML.
+type citeMarkup struct {
+	html bool
+}
+
+func (m citeMarkup) text(s string) string {
+	if m.html {
+		return html.EscapeString(s)
+	}
+	return s
+}
+
+// title writes a title in italics, followed by a full stop unless it ends
+// with one already.
+func (m citeMarkup) title(s string) string {
+	stop := withStop(s)[len(s):]
+	if m.html {
+		return "<i>" + html.EscapeString(s) + "</i>" + stop
+	}
+	return s + stop
+}
+
+// withStop ends a sentence with a full stop unless it ends with one, or
+// with a question or exclamation mark.
+func withStop(s string) string {
+	if s == "" || strings.HasSuffix(s, ".") || strings.HasSuffix(s, "?") || strings.HasSuffix(s, "!") {
+		return s
+	}
+	return s + "."
+}
+
+// bibliography writes one entry per line, sorted as a bibliography is.
+// HTML entries are paragraphs.
+func bibliography(books []citedBook, style func(book citedBook, m citeMarkup) string, asHTML bool) string {
+	type entry struct{ key, text string }
+	entries := make([]entry, len(books))
+	for i, book := range books {
+		entries[i] = entry{strings.ToLower(style(book, citeMarkup{})), style(book, citeMarkup{asHTML})}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].key < entries[j].key })
+	var out strings.Builder
+	for _, entry := range entries {
+		if asHTML {
+			fmt.Fprintf(&out, "<p>%s</p>\n", entry.text)
+		} else {
+			out.WriteString(entry.text + "\n")
+		}
+	}
+	return out.String()
+}
+
+// apa writes an APA (7th edition) reference, as in
+// "Herbert, F. (1965). Dune."
+func apa(book citedBook, m citeMarkup) string {
+	date := "(n.d.)."
+	if book.Year > 0 {
+		date = fmt.Sprintf("(%d).", book.Year)
+	}
+	names := make([]string, len(book.Authors))
+	for i, name := range book.Authors {
+		names[i] = name.inverted()
+		if name.Literal == "" && name.Given != "" {
+			names[i] = name.Family + ", " + initials(name.Given)
+			if name.Suffix != "" {
+				names[i] += ", " + name.Suffix
+			}
+		}
+	}
+	var authors string
+	switch n := len(names); {
+	case n == 0:
+		return m.title(book.Title) + " " + date
+	case n == 1:
+		authors = names[0]
+	case n <= 20:
+		authors = strings.Join(names[:n-1], ", ") + ", & " + names[n-1]
+	default:
+		authors = strings.Join(names[:19], ", ") + ", . . . " + names[n-1]
+	}
+	return m.text(withStop(authors)) + " " + date + " " + m.title(book.Title)
+}
+
+// mla writes an MLA (9th edition) works cited entry, as in
+// "Herbert, Frank. Dune. 1965."
+func mla(book citedBook, m citeMarkup) string {
+	var authors string
+	switch n := len(book.Authors); {
+	case n == 1:
+		authors = book.Authors[0].inverted()
+	case n == 2:
+		authors = book.Authors[0].inverted() + ", and " + book.Authors[1].direct()
+	case n > 2:
+		authors = book.Authors[0].inverted() + ", et al"
+	}
+	entry := m.title(book.Title)
+	if authors != "" {
+		entry = m.text(withStop(authors)) + " " + entry
+	}
+	if book.Year > 0 {
+		entry += fmt.Sprintf(" %d.", book.Year)
+	}
+	return entry
+}
+
+// chicago writes a Chicago (17th edition) bibliography entry, as in
+// "Herbert, Frank. Dune. 1965."
+func chicago(book citedBook, m citeMarkup) string {
+	names := book.Authors
+	etAl := len(names) > 10
+	if etAl {
+		names = names[:7]
+	}
+	var authors string
+	for i, name := range names {
+		switch {
+		case i == 0:
+			authors = name.inverted()
+		case i == len(names)-1 && !etAl:
+			authors += ", and " + name.direct()
+		default:
+			authors += ", " + name.direct()
+		}
+	}
+	if etAl {
+		authors += ", et al"
+	}
+	year := "n.d."
+	if book.Year > 0 {
+		year = strconv.Itoa(book.Year) + "."
+	}
+	entry := m.title(book.Title) + " " + year
+	if authors != "" {
+		entry = m.text(withStop(authors)) + " " + entry
+	}
+	return entry
+}
+
+var bibtexEscaper = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`&`, `\&`,
+	`%`, `\%`,
+	`$`, `\$`,
+	`#`, `\#`,
+	`_`, `\_`,
+	`~`, `\textasciitilde{}`,
+	`^`, `\textasciicircum{}`,
+)
+
+// bibtex writes BibTeX entries with keys such as "herbert1965dune". Keys
+// that would repeat get a letter.
+func bibtex(books []citedBook) string {
+	seen := make(map[string]int)
+	var out strings.Builder
+	for i, book := range books {
+		key := bibtexKey(book)
+		if n := seen[key]; n > 0 {
+			seen[key]++
+			key += string(rune('a' + n%26))
+		} else {
+			seen[key] = 1
+		}
+		entryType := "book"
+		if book.MaterialType != "" && book.MaterialType != model.MaterialTypeBook {
+			entryType = "misc"
+		}
+		names := make([]string, len(book.Authors))
+		for i, name := range book.Authors {
+			if name.Literal != "" {
+				names[i] = "{" + bibtexEscaper.Replace(name.Literal) + "}"
+			} else {
+				names[i] = bibtexEscaper.Replace(name.Family)
+				if name.Suffix != "" {
+					names[i] += ", " + bibtexEscaper.Replace(name.Suffix)
+				}
+				if name.Given != "" {
+					names[i] += ", " + bibtexEscaper.Replace(name.Given)
+				}
+			}
+		}
+		if i > 0 {
+			out.WriteString("\n")
+		}
+		fmt.Fprintf(&out, "@%s{%s,\n", entryType, key)
+		field := func(name, value string) {
+			if value != "" {
+				fmt.Fprintf(&out, "  %s = {%s},\n", name, value)
+			}
+		}
+		field("author", strings.Join(names, " and "))
+		field("title", bibtexEscaper.Replace(book.Title))
+		if book.Year > 0 {
+			field("year", strconv.Itoa(book.Year))
+		}
+		field("isbn", bibtexEscaper.Replace(book.ISBN))
+		out.WriteString("}\n")
+	}
+	return out.String()
+}
+
+// bibtexKey makes a key from the first author's family name, the year and
+// the first word of the title that is not an article.
+func bibtexKey(book citedBook) string {
+	var key strings.Builder
+	keep := func(s string) {
+		for _, r := range strings.ToLower(s) {
+			if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+				key.WriteRune(r)
+			}
+		}
+	}
+	if len(book.Authors) > 0 {
+		name := book.Authors[0]
+		if name.Literal != "" {
+			keep(strings.Fields(name.Literal)[0])
+		} else {
+			keep(name.Family)
+		}
+	}
+	if book.Year > 0 {
+		keep(strconv.Itoa(book.Year))
+	}
+	for _, word := range strings.Fields(book.Title) {
+		if lower := strings.ToLower(word); lower != "a" && lower != "an" && lower != "the" {
+			keep(word)
+			break
+		}
+	}
+	if key.Len() == 0 {
+		return fmt.Sprintf("book%d", book.ID)
+	}
+	return key.String()
+}
+
+// ris writes RIS records, one tag per line.
+func ris(books []citedBook) string {
+	clean := strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ")
+	var out strings.Builder
+	tag := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(&out, "%s  - %s\r\n", name, clean.Replace(value))
+		}
+	}
+	for _, book := range books {
+		entryType := "BOOK"
+		if book.MaterialType != "" && book.MaterialType != model.MaterialTypeBook {
+			entryType = "GEN"
+		}
+		tag("TY", entryType)
+		for _, name := range book.Authors {
+			tag("AU", name.inverted())
+		}
+		tag("TI", book.Title)
+		if book.Year > 0 {
+			tag("PY", strconv.Itoa(book.Year))
+		}
+		tag("SN", book.ISBN)
+		out.WriteString("ER  - \r\n")
+	}
+	return out.String()
+}
+
+type cslName struct {
+	Family  string `json:"family,omitempty"`
+	Given   string `json:"given,omitempty"`
+	Suffix  string `json:"suffix,omitempty"`
+	Literal string `json:"literal,omitempty"`
+}
+
+type cslDate struct {
+	DateParts [][]int `json:"date-parts"`
+}
+
+type cslItem struct {
+	ID     uint      `json:"id"`
+	Type   string    `json:"type"`
+	Title  string    `json:"title,omitempty"`
+	Author []cslName `json:"author,omitempty"`
+	Issued *cslDate  `json:"issued,omitempty"`
+	ISBN   string    `json:"ISBN,omitempty"`
+}
+
+// cslJSON writes an array of CSL-JSON items, the input format of citeproc
+// processors.
+func cslJSON(books []citedBook) string {
+	items := make([]cslItem, len(books))
+	for i, book := range books {
+		item := cslItem{ID: book.ID, Type: "book", Title: book.Title, ISBN: book.ISBN}
+		if book.MaterialType != "" && book.MaterialType != model.MaterialTypeBook {
+			item.Type = "document"
+		}
+		for _, name := range book.Authors {
+			item.Author = append(item.Author, cslName(name))
+		}
+		if len(book.Date) > 0 {
+			item.Issued = &cslDate{[][]int{book.Date}}
+		}
+		items[i] = item
+	}
+	data, _ := json.MarshalIndent(items, "", "  ")
+	return string(data) + "\n"
+}
@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"strings"
+	"testing"
+
+	"go.test/model"
+	util "go.test/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAuthors(t *testing.T) {
+	assert.Equal(t, []citeName{{Family: "Herbert", Given: "Frank"}}, parseAuthors("Frank Herbert"))
+	assert.Equal(t, []citeName{{Family: "Le Guin", Given: "Ursula K."}}, parseAuthors("Le Guin, Ursula K."))
+	assert.Equal(t, []citeName{{Family: "van Beethoven", Given: "Ludwig"}}, parseAuthors("Ludwig van Beethoven"))
+	assert.Equal(t, []citeName{{Family: "King", Given: "Martin Luther", Suffix: "Jr."}, {Literal: "Homer"}}, parseAuthors("Martin Luther King, Jr. & Homer"))
+	assert.Equal(t, []citeName{{Family: "Pratchett", Given: "Terry"}, {Family: "Gaiman", Given: "Neil"}}, parseAuthors("Pratchett, Terry; Gaiman, Neil"))
+	assert.Empty(t, parseAuthors(" "))
+}
+
+func TestInitials(t *testing.T) {
+	assert.Equal(t, "F.", initials("Frank"))
+	assert.Equal(t, "J.-P.", initials("Jean-Paul"))
+	assert.Equal(t, "J. R. R.", initials("J.R.R."))
+	assert.Equal(t, "Ü. K.", initials("ürsula K."))
+}
+
+func TestCitationStyles(t *testing.T) {
+	dune := newCitedBook(&model.Book{ID: 1, Title: "Dune", Author: "Frank Herbert", PublishedDate: model.PartialDate{Year: 1965, Month: 8, Day: 1}, ISBN: "9780441172719"})
+	pair := newCitedBook(&model.Book{ID: 2, Title: "Good Omens", Author: "Pratchett, Terry; Gaiman, Neil", PublishedDate: model.PartialDate{Year: 1990}})
+	many := newCitedBook(&model.Book{ID: 3, Title: "Why & How?", Author: "A, Ann; B, Bob; C, Cy"})
+	anon := newCitedBook(&model.Book{ID: 4, Title: "Beowulf"})
+
+	plain := citeMarkup{}
+	assert.Equal(t, "Herbert, F. (1965). Dune.", apa(dune, plain))
+	assert.Equal(t, "Pratchett, T., & Gaiman, N. (1990). Good Omens.", apa(pair, plain))
+	assert.Equal(t, "A, A., B, B., & C, C. (n.d.). Why & How?", apa(many, plain))
+	assert.Equal(t, "Beowulf. (n.d.).", apa(anon, plain))
+
+	assert.Equal(t, "Herbert, Frank. Dune. 1965.", mla(dune, plain))
+	assert.Equal(t, "Pratchett, Terry, and Neil Gaiman. Good Omens. 1990.", mla(pair, plain))
+	assert.Equal(t, "A, Ann, et al. Why & How?", mla(many, plain))
+
+	assert.Equal(t, "Herbert, Frank. Dune. 1965.", chicago(dune, plain))
+	assert.Equal(t, "A, Ann, Bob B, and Cy C. Why & How? n.d.", chicago(many, plain))
+	assert.Equal(t, "Beowulf. n.d.", chicago(anon, plain))
+
+	assert.Equal(t, "A, A., B, B., &amp; C, C. (n.d.). <i>Why &amp; How?</i>", apa(many, citeMarkup{html: true}))
+	assert.Equal(t, "<p>Herbert, F. (1965). <i>Dune</i>.</p>\n<p>Pratchett, T., &amp; Gaiman, N. (1990). <i>Good Omens</i>.</p>\n",
+		bibliography([]citedBook{pair, dune}, apa, true))
+}
+
+func TestBibTeX(t *testing.T) {
+	dune := newCitedBook(&model.Book{ID: 1, Title: "The Dune_Saga: 100% {real}", Author: "Herbert, Frank; Barnes & Noble Inc", PublishedDate: model.PartialDate{Year: 1965}})
+	dune.Authors[1] = citeName{Literal: "Barnes & Noble"}
+	assert.Equal(t, `@book{herbert1965dunesaga,
+  author = {Herbert, Frank and {Barnes \& Noble}},
+  title = {The Dune\_Saga: 100\% \{real\}},
+  year = {1965},
+}
+
+@book{herbert1965dunesagab,
+  author = {Herbert, Frank and {Barnes \& Noble}},
+  title = {The Dune\_Saga: 100\% \{real\}},
+  year = {1965},
+}
+`, bibtex([]citedBook{dune, dune}))
+	assert.Equal(t, "@misc{book7,\n  title = {???},\n}\n", bibtex([]citedBook{newCitedBook(&model.Book{ID: 7, Title: "???", MaterialType: "dvd"})}))
+}
+
+func TestRISAndCSLJSON(t *testing.T) {
+	dune := newCitedBook(&model.Book{ID: 1, Title: "Dune\nSaga", Author: "Frank Herbert", PublishedDate: model.PartialDate{Year: 1965, Month: 8}, ISBN: "9780441172719"})
+	assert.Equal(t, "TY  - BOOK\r\nAU  - Herbert, Frank\r\nTI  - Dune Saga\r\nPY  - 1965\r\nSN  - 9780441172719\r\nER  - \r\n", ris([]citedBook{dune}))
+
+	csl := cslJSON([]citedBook{dune})
+	assert.Contains(t, csl, `"author": [
+      {
+        "family": "Herbert",
+        "given": "Frank"
+      }
+    ]`)
+	assert.Contains(t, strings.Join(strings.Fields(csl), ""), `"issued":{"date-parts":[[1965,8]]}`)
+}
+
+func TestBookAuthorsFromMARC(t *testing.T) {
+	record := &util.MARCRecord{Leader: util.MARCLeader, Fields: []util.MARCField{
+		{Tag: "100", Indicators: [2]byte{'1', ' '}, Subfields: []util.MARCSubfield{{Code: 'a', Value: "Herbert, Frank,"}}},
+		{Tag: "700", Indicators: [2]byte{'1', ' '}, Subfields: []util.MARCSubfield{{Code: 'a', Value: "Anderson, Kevin J.,"}, {Code: 'e', Value: "author."}}},
+		{Tag: "700", Indicators: [2]byte{'1', ' '}, Subfields: []util.MARCSubfield{{Code: 'a', Value: "Schoenherr, John,"}, {Code: 'e', Value: "illustrator."}}},
+		{Tag: "710", Indicators: [2]byte{'2', ' '}, Subfields: []util.MARCSubfield{{Code: 'a', Value: "Dune Estate."}}},
+	}}
+	data, _ := util.MarshalMARC(record)
+	book := &model.Book{Author: "Herbert, Frank", MARC: data}
+	assert.Equal(t, []citeName{{Family: "Herbert", Given: "Frank"}, {Family: "Anderson", Given: "Kevin J."}, {Literal: "Dune Estate"}}, bookAuthors(book))
+
+	book.Author = "Brian Herbert"
+	assert.Equal(t, []citeName{{Family: "Herbert", Given: "Brian"}}, bookAuthors(book))
+}
@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"errors"
+	"strings"
+
+	"go.test/model"
+	"go.test/repository"
+)
+
+var (
+	ErrInvalidCopyStatus = errors.New("invalid copy status")
+	ErrCopyStatusManaged = errors.New("on_loan and on_hold are set by checkouts, returns and holds, not by hand")
+	ErrBarcodeRequired   = errors.New("a barcode is required")
+	ErrCopyInUse         = repository.ErrCopyInUse
+	ErrBarcodeTaken      = repository.ErrBarcodeTaken
+)
+
+type CopyUsecase interface {
+	GetCopiesByBook(bookID uint) ([]model.Copy, error)
+	GetCopyByID(id uint) (*model.Copy, error)
+	CreateCopy(bookCopy *model.Copy) error
+	UpdateCopy(bookCopy *model.Copy) error
+	DeleteCopy(id uint) error
+	GetAvailability(bookID uint) (*model.Availability, error)
+}
+
+type copyUsecase struct {
+	copyRepo repository.CopyRepository
+	bookRepo repository.BookRepository
+}
+
+func NewCopyUsecase(copyRepo repository.CopyRepository, bookRepo repository.BookRepository) CopyUsecase {
+	return &copyUsecase{copyRepo, bookRepo}
+}
+
+func (u *copyUsecase) GetCopiesByBook(bookID uint) ([]model.Copy, error) {
+	if _, err := u.bookRepo.GetByID(bookID); err != nil {
+		return nil, err
+	}
+	return u.copyRepo.GetByBookID(bookID)
+}
+
+func (u *copyUsecase) GetCopyByID(id uint) (*model.Copy, error) {
+	return u.copyRepo.GetByID(id)
+}
+
+// CreateCopy adds a copy of a book. Every copy needs a barcode of its own,
+// as checkouts find copies by it.
+func (u *copyUsecase) CreateCopy(bookCopy *model.Copy) error {
+	bookCopy.Barcode = strings.TrimSpace(bookCopy.Barcode)
+	if bookCopy.Barcode == "" {
+		return ErrBarcodeRequired
+	}
+	if bookCopy.Status == "" {
+		bookCopy.Status = model.CopyStatusAvailable
+	}
+	if !validCopyStatus(bookCopy.Status) {
+		return ErrInvalidCopyStatus
+	}
+	if managedCopyStatus(bookCopy.Status) {
+		return ErrCopyStatusManaged
+	}
+	if _, err := u.bookRepo.GetByID(bookCopy.BookID); err != nil {
+		return err
+	}
+	return u.copyRepo.Create(bookCopy)
+}
+
+// UpdateCopy saves a copy. Its status cannot be changed into or out of
+// the statuses circulation sets, which would leave a copy on loan or on hold
+// without a loan or hold, or lend out a copy that is still on loan or set
+// aside for a patron.
+func (u *copyUsecase) UpdateCopy(bookCopy *model.Copy) error {
+	bookCopy.Barcode = strings.TrimSpace(bookCopy.Barcode)
+	if bookCopy.Barcode == "" {
+		return ErrBarcodeRequired
+	}
+	if !validCopyStatus(bookCopy.Status) {
+		return ErrInvalidCopyStatus
+	}
+	return u.copyRepo.Update(bookCopy, func(current *model.Copy) error {
+		if bookCopy.Status != current.Status && (managedCopyStatus(bookCopy.Status) || managedCopyStatus(current.Status)) {
+			return ErrCopyStatusManaged
+		}
+		if bookCopy.BookID == 0 {
+			bookCopy.BookID = current.BookID
+		}
+		return nil
+	})
+}
+
+// DeleteCopy deletes a copy that is not out on loan or set aside for a
+// hold.
+func (u *copyUsecase) DeleteCopy(id uint) error {
+	return u.copyRepo.Delete(id)
+}
+
+func (u *copyUsecase) GetAvailability(bookID uint) (*model.Availability, error) {
+	availability, err := u.copyRepo.GetAvailability([]uint{bookID})
+	if err != nil {
+		return nil, err
+	}
+	return availability[bookID], nil
+}
+
+func validCopyStatus(status string) bool {
+	switch status {
+	case model.CopyStatusAvailable, model.CopyStatusOnLoan, model.CopyStatusLost, model.CopyStatusInRepair, model.CopyStatusOnHold:
+		return true
+	}
+	return false
+}
+
+// managedCopyStatus reports whether a status is only set by circulation.
+func managedCopyStatus(status string) bool {
+	return status == model.CopyStatusOnLoan || status == model.CopyStatusOnHold
+}
@@ -0,0 +1,205 @@
+package usecase
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+
+	"go.test/model"
+	"go.test/repository"
+	"go.test/storage"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+	"gorm.io/gorm"
+)
+
+// maxCoverPixels keeps a small file that claims to be a huge image from
+// being decoded into gigabytes of memory.
+const maxCoverPixels = 40_000_000
+
+var (
+	ErrCoverTooLarge    = errors.New("cover image is too large")
+	ErrUnsupportedImage = errors.New("cover must be a JPEG, PNG or WebP image")
+	ErrUnknownCoverSize = errors.New("size must be small, medium, large or original")
+)
+
+// coverTypes are the image types a cover can be uploaded in, as
+// http.DetectContentType names them.
+var coverTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/webp": true,
+}
+
+type CoverUsecase interface {
+	SetCover(bookID uint, r io.Reader) (*model.Cover, error)
+	GetCover(bookID uint) (*model.Cover, error)
+	OpenCover(cover *model.Cover, size string) (io.ReadCloser, error)
+	DeleteCover(bookID uint) error
+}
+
+type coverUsecase struct {
+	coverRepo repository.CoverRepository
+	bookRepo  repository.BookRepository
+	store     storage.BlobStore
+	maxBytes  int64
+}
+
+// NewCoverUsecase returns a CoverUsecase that keeps cover files in store and
+// turns down uploads of more than maxBytes.
+func NewCoverUsecase(coverRepo repository.CoverRepository, bookRepo repository.BookRepository, store storage.BlobStore, maxBytes int64) CoverUsecase {
+	return &coverUsecase{coverRepo, bookRepo, store, maxBytes}
+}
+
+// SetCover replaces the cover of a book. The image type is told from its
+// content rather than from what the client says it is. The original is kept
+// as uploaded, along with a thumbnail in each of model.CoverSizes.
+func (u *coverUsecase) SetCover(bookID uint, r io.Reader) (*model.Cover, error) {
+	if _, err := u.bookRepo.GetByID(bookID); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(io.LimitReader(r, u.maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > u.maxBytes {
+		return nil, ErrCoverTooLarge
+	}
+	contentType := http.DetectContentType(data)
+	if !coverTypes[contentType] {
+		return nil, ErrUnsupportedImage
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+	if config.Width*config.Height > maxCoverPixels {
+		return nil, ErrCoverTooLarge
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+
+	sum := sha256.Sum256(data)
+	cover := &model.Cover{
+		BookID:        bookID,
+		Version:       hex.EncodeToString(sum[:8]),
+		ContentType:   contentType,
+		ThumbnailType: "image/jpeg",
+		Width:         config.Width,
+		Height:        config.Height,
+		Size:          int64(len(data)),
+	}
+	if !opaque(img) {
+		cover.ThumbnailType = "image/png"
+	}
+	if err := u.store.Put(cover.Key(model.CoverSizeOriginal), bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	for size, side := range model.CoverSizes {
+		var thumbnail bytes.Buffer
+		if err := encodeThumbnail(&thumbnail, fit(img, side), cover.ThumbnailType); err != nil {
+			return nil, err
+		}
+		if err := u.store.Put(cover.Key(size), bytes.NewReader(thumbnail.Bytes())); err != nil {
+			return nil, err
+		}
+	}
+
+	previous, err := u.coverRepo.GetByBookID(bookID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if err := u.coverRepo.Save(cover); err != nil {
+		return nil, err
+	}
+	if previous != nil && previous.Version != cover.Version {
+		u.deleteFiles(previous)
+	}
+	cover.SetURL()
+	return cover, nil
+}
+
+func (u *coverUsecase) GetCover(bookID uint) (*model.Cover, error) {
+	cover, err := u.coverRepo.GetByBookID(bookID)
+	if err != nil {
+		return nil, err
+	}
+	cover.SetURL()
+	return cover, nil
+}
+
+// OpenCover returns the file of a cover in a size, the original if size is
+// empty.
+func (u *coverUsecase) OpenCover(cover *model.Cover, size string) (io.ReadCloser, error) {
+	if size == "" {
+		size = model.CoverSizeOriginal
+	}
+	if _, ok := model.CoverSizes[size]; !ok && size != model.CoverSizeOriginal {
+		return nil, ErrUnknownCoverSize
+	}
+	return u.store.Get(cover.Key(size))
+}
+
+func (u *coverUsecase) DeleteCover(bookID uint) error {
+	cover, err := u.coverRepo.GetByBookID(bookID)
+	if err != nil {
+		return err
+	}
+	if err := u.coverRepo.Delete(bookID); err != nil {
+		return err
+	}
+	u.deleteFiles(cover)
+	return nil
+}
+
+// deleteFiles removes the files of a cover that is no longer in use. A file
+// that cannot be removed is only wasted space, so errors are ignored.
+func (u *coverUsecase) deleteFiles(cover *model.Cover) {
+	u.store.Delete(cover.Key(model.CoverSizeOriginal))
+	for size := range model.CoverSizes {
+		u.store.Delete(cover.Key(size))
+	}
+}
+
+// fit scales an image down so that its longest side is at most side pixels.
+// Smaller images are left as they are.
+func fit(img image.Image, side int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= side && height <= side {
+		return img
+	}
+	if width >= height {
+		width, height = side, max(1, height*side/width)
+	} else {
+		width, height = max(1, width*side/height), side
+	}
+	scaled := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(scaled, scaled.Bounds(), img, bounds, draw.Src, nil)
+	return scaled
+}
+
+func encodeThumbnail(w io.Writer, img image.Image, contentType string) error {
+	if contentType == "image/png" {
+		return png.Encode(w, img)
+	}
+	return jpeg.Encode(w, img, &jpeg.Options{Quality: 85})
+}
+
+// opaque reports whether an image has no transparent pixels, so that its
+// thumbnails can be JPEG.
+func opaque(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+	return true
+}
@@ -0,0 +1,33 @@
+package usecase
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFit(t *testing.T) {
+	tall := image.NewRGBA(image.Rect(0, 0, 400, 1000))
+	assert.Equal(t, image.Rect(0, 0, 60, 150), fit(tall, 150).Bounds())
+
+	wide := image.NewRGBA(image.Rect(0, 0, 1000, 3))
+	assert.Equal(t, image.Rect(0, 0, 300, 1), fit(wide, 300).Bounds())
+
+	small := image.NewRGBA(image.Rect(0, 0, 100, 120))
+	assert.Same(t, small, fit(small, 150), "images are never scaled up")
+}
+
+func TestOpaque(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	for x := 0; x < 2; x++ {
+		for y := 0; y < 2; y++ {
+			img.Set(x, y, color.White)
+		}
+	}
+	assert.True(t, opaque(img))
+	img.Set(1, 1, color.Transparent)
+	assert.False(t, opaque(img))
+	assert.True(t, opaque(image.NewYCbCr(image.Rect(0, 0, 2, 2), image.YCbCrSubsampleRatio420)))
+}
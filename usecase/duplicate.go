@@ -0,0 +1,239 @@
+package usecase
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"go.test/model"
+	"go.test/repository"
+	util "go.test/utils"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultDuplicateScore = 0.7
+	defaultDuplicateLimit = 100
+	maxDuplicateLimit     = 1000
+)
+
+var (
+	ErrInvalidDuplicateFilter = errors.New("min_score must be between 0 and 1 and limit between 1 and 1000")
+	ErrMergeSameBook          = errors.New("a book cannot be merged into itself")
+	ErrInvalidMergeField      = errors.New(`fields must map title, author, isbn, published_date or material_type to "target" or "source"`)
+)
+
+// Weights of the fields in a duplicate score. The ISBN does not have one:
+// books with the same ISBN score at least 0.5 whatever their other fields.
+const (
+	titleWeight  = 0.6
+	authorWeight = 0.3
+	yearWeight   = 0.1
+)
+
+// titleArticles are left off the start of titles, which catalogers do not
+// always agree on.
+var titleArticles = []string{"the ", "a ", "an "}
+
+type DuplicateUsecase interface {
+	FindDuplicates(filter *model.DuplicateFilter) ([]model.DuplicatePair, error)
+	MergeBooks(req *model.MergeRequest, actor string) (*model.MergeResult, error)
+}
+
+type duplicateUsecase struct {
+	bookRepo repository.BookRepository
+	covers   CoverUsecase
+}
+
+// NewDuplicateUsecase returns a DuplicateUsecase. Covers of merged books
+// are moved through covers.
+func NewDuplicateUsecase(bookRepo repository.BookRepository, covers CoverUsecase) DuplicateUsecase {
+	return &duplicateUsecase{bookRepo, covers}
+}
+
+// FindDuplicates returns the pairs of books that score at least
+// filter.MinScore, best first. Only books that share an ISBN or the start
+// of their title are compared, so that the catalog is not compared with
+// itself pair by pair.
+func (u *duplicateUsecase) FindDuplicates(filter *model.DuplicateFilter) ([]model.DuplicatePair, error) {
+	if filter.MinScore == 0 {
+		filter.MinScore = defaultDuplicateScore
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultDuplicateLimit
+	}
+	if filter.MinScore < 0 || filter.MinScore > 1 || filter.Limit < 1 || filter.Limit > maxDuplicateLimit {
+		return nil, ErrInvalidDuplicateFilter
+	}
+	books, err := u.bookRepo.GetAll(&model.BookFilter{})
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]bookKey, len(books))
+	groups := make(map[string][]int)
+	for i := range books {
+		keys[i] = newBookKey(&books[i])
+		if keys[i].isbn != "" {
+			groups["isbn:"+keys[i].isbn] = append(groups["isbn:"+keys[i].isbn], i)
+		}
+		if prefix := titlePrefix(keys[i].title); prefix != "" {
+			groups["title:"+prefix] = append(groups["title:"+prefix], i)
+		}
+	}
+	type pairKey struct{ a, b int }
+	seen := make(map[pairKey]bool)
+	pairs := []model.DuplicatePair{}
+	for _, group := range groups {
+		for x := 0; x < len(group); x++ {
+			for y := x + 1; y < len(group); y++ {
+				a, b := group[x], group[y]
+				if seen[pairKey{a, b}] {
+					continue
+				}
+				seen[pairKey{a, b}] = true
+				score, matches := scoreDuplicate(&keys[a], &keys[b])
+				if score >= filter.MinScore {
+					pairs = append(pairs, model.DuplicatePair{Books: [2]model.Book{books[a], books[b]}, Score: score, Matches: matches})
+				}
+			}
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].Score != pairs[j].Score {
+			return pairs[i].Score > pairs[j].Score
+		}
+		if pairs[i].Books[0].ID != pairs[j].Books[0].ID {
+			return pairs[i].Books[0].ID < pairs[j].Books[0].ID
+		}
+		return pairs[i].Books[1].ID < pairs[j].Books[1].ID
+	})
+	if len(pairs) > filter.Limit {
+		pairs = pairs[:filter.Limit]
+	}
+	return pairs, nil
+}
+
+// bookKey is a book as it is compared with others.
+type bookKey struct {
+	isbn   string // ISBN-13, if the book has a valid ISBN
+	title  string // folded, without a leading article
+	author string // folded, with the words sorted
+	year   int
+}
+
+func newBookKey(book *model.Book) bookKey {
+	key := bookKey{
+		title:  util.FoldText(book.Title),
+		author: util.SortWords(util.FoldText(book.Author)),
+		year:   book.PublishedDate.Year,
+	}
+	if isbn, ok := util.ISBN13(book.ISBN); ok {
+		key.isbn = isbn
+	}
+	for _, article := range titleArticles {
+		if strings.HasPrefix(key.title, article) {
+			key.title = key.title[len(article):]
+			break
+		}
+	}
+	return key
+}
+
+// titlePrefix is the start of a folded title that books are grouped by for
+// comparison.
+func titlePrefix(title string) string {
+	letters := []rune(strings.ReplaceAll(title, " ", ""))
+	return string(letters[:min(len(letters), 4)])
+}
+
+// scoreDuplicate scores how likely two books are to be one: the weighted
+// similarity of the fields both have, raised to at least 0.5 by a shared
+// ISBN and lowered by different ones, which may be different editions.
+func scoreDuplicate(a, b *bookKey) (float64, model.DuplicateMatch) {
+	matches := model.DuplicateMatch{Title: round2(util.Similarity(a.title, b.title))}
+	score, weight := titleWeight*matches.Title, titleWeight
+	if a.author != "" && b.author != "" {
+		author := round2(util.Similarity(a.author, b.author))
+		matches.Author = &author
+		score, weight = score+authorWeight*author, weight+authorWeight
+	}
+	if a.year != 0 && b.year != 0 {
+		year := 0.0
+		switch diff := a.year - b.year; {
+		case diff == 0:
+			year = 1
+		case diff == 1 || diff == -1:
+			year = 0.5
+		}
+		matches.Year = &year
+		score, weight = score+yearWeight*year, weight+yearWeight
+	}
+	score /= weight
+	if a.isbn != "" && b.isbn != "" {
+		same := a.isbn == b.isbn
+		matches.ISBN = &same
+		if same {
+			score = 0.5 + score/2
+		} else {
+			score *= 0.8
+		}
+	}
+	return round2(score), matches
+}
+
+// MergeBooks merges the source book of req into its target, taking each
+// field from the side req chooses. The target keeps its cover, or takes
+// the source's if it has none.
+func (u *duplicateUsecase) MergeBooks(req *model.MergeRequest, actor string) (*model.MergeResult, error) {
+	if req.TargetID == req.SourceID {
+		return nil, ErrMergeSameBook
+	}
+	known := (&model.Book{}).Fields()
+	for field, side := range req.Fields {
+		if _, ok := known[field]; !ok || (side != model.MergeTarget && side != model.MergeSource) {
+			return nil, ErrInvalidMergeField
+		}
+	}
+	book, moved, err := u.bookRepo.Merge(req.TargetID, req.SourceID, req.TargetVersion, req.SourceVersion, actor, func(target, source *model.Book) error {
+		fields := source.Fields()
+		chosen := make(map[string]interface{})
+		for field, side := range req.Fields {
+			if side == model.MergeSource {
+				chosen[field] = fields[field]
+			}
+		}
+		target.SetFields(chosen)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	moved["cover"] = 0
+	if u.moveCover(req.SourceID, req.TargetID) {
+		moved["cover"] = 1
+	}
+	return &model.MergeResult{Book: book, Moved: moved}, nil
+}
+
+// moveCover gives the target the source's cover if it has none, and
+// reports whether it did. Covers are stored under their book, so the cover
+// is set again rather than moved. The books are merged by then, so a cover
+// that cannot be moved stays with the source in the trash rather than
+// failing the merge.
+func (u *duplicateUsecase) moveCover(from, to uint) bool {
+	if _, err := u.covers.GetCover(to); !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false
+	}
+	cover, err := u.covers.GetCover(from)
+	if err != nil {
+		return false
+	}
+	file, err := u.covers.OpenCover(cover, model.CoverSizeOriginal)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+	_, err = u.covers.SetCover(to, file)
+	return err == nil
+}
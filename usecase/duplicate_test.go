@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"testing"
+
+	"go.test/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScoreDuplicate(t *testing.T) {
+	key := func(title, author, isbn string, year int) bookKey {
+		return newBookKey(&model.Book{Title: title, Author: author, ISBN: isbn, PublishedDate: model.PartialDate{Year: year}})
+	}
+	dune := key("Dune", "Frank Herbert", "0-441-17271-7", 1965)
+
+	score, matches := scoreDuplicate(&dune, &dune)
+	assert.Equal(t, 1.0, score)
+	assert.True(t, *matches.ISBN)
+
+	// The ISBN-13 of the same book, with the author written the other way
+	// round and a stray article.
+	same := key("The Dune.", "Herbert, Frank", "978-0-441-17271-9", 1965)
+	score, _ = scoreDuplicate(&dune, &same)
+	assert.Equal(t, 1.0, score)
+
+	typo := key("Dnue", "Frank Herbert", "", 1966)
+	score, matches = scoreDuplicate(&dune, &typo)
+	assert.Nil(t, matches.ISBN)
+	assert.Equal(t, 0.5, matches.Title)
+	assert.Equal(t, 0.5, *matches.Year)
+	assert.Equal(t, 0.65, score)
+
+	sequel := key("Dune Messiah", "Frank Herbert", "978-0-399-12726-7", 1969)
+	score, matches = scoreDuplicate(&dune, &sequel)
+	assert.False(t, *matches.ISBN)
+	assert.Less(t, score, 0.5)
+
+	bare := key("Dune", "", "", 0)
+	score, matches = scoreDuplicate(&dune, &bare)
+	assert.Nil(t, matches.Author)
+	assert.Nil(t, matches.Year)
+	assert.Equal(t, 1.0, score, "fields one book does not have do not count")
+}
+
+func TestTitlePrefix(t *testing.T) {
+	assert.Equal(t, "dune", titlePrefix("dune messiah"))
+	assert.Equal(t, "itw", titlePrefix("it w"))
+	assert.Equal(t, "", titlePrefix(""))
+	assert.Equal(t, "lord", newBookKey(&model.Book{Title: "The Lord of the Rings"}).title[:4])
+}
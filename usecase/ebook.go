@@ -0,0 +1,275 @@
+package usecase
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"go.test/model"
+	"go.test/repository"
+	"go.test/storage"
+	util "go.test/utils"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrEbookTooLarge    = errors.New("ebook file is too large")
+	ErrUnsupportedEbook = errors.New("ebook must be an EPUB or PDF file")
+	ErrNoActiveLoan     = errors.New("the book has to be on loan to you to download it")
+)
+
+// epubSignature starts every EPUB file: a ZIP archive whose first entry is
+// the uncompressed "mimetype" file.
+const epubSignature = "mimetypeapplication/epub+zip"
+
+type EbookUsecase interface {
+	AddEbook(bookID uint, r io.Reader, filename, actor string) (*model.EbookUpload, error)
+	GetEbooks(bookID uint) ([]model.Ebook, error)
+	DeleteEbook(id uint) error
+	GetDownload(id uint, username, role string) (*model.EbookDownload, error)
+	OpenDownload(id uint, expires, signature string) (*model.Ebook, io.ReadSeekCloser, error)
+}
+
+type ebookUsecase struct {
+	ebookRepo   repository.EbookRepository
+	bookRepo    repository.BookRepository
+	loanRepo    repository.LoanRepository
+	userRepo    repository.UserRepository
+	covers      CoverUsecase
+	files       *storage.ContentStore
+	signer      *storage.URLSigner
+	maxBytes    int64
+	urlLifetime time.Duration
+}
+
+// NewEbookUsecase returns an EbookUsecase that keeps ebook files in files,
+// turns down uploads of more than maxBytes and hands out download
+// addresses, signed by signer, that work for urlLifetime. Covers found in
+// uploaded files are set through covers.
+func NewEbookUsecase(ebookRepo repository.EbookRepository, bookRepo repository.BookRepository, loanRepo repository.LoanRepository, userRepo repository.UserRepository,
+	covers CoverUsecase, files *storage.ContentStore, signer *storage.URLSigner, maxBytes int64, urlLifetime time.Duration) EbookUsecase {
+	return &ebookUsecase{ebookRepo, bookRepo, loanRepo, userRepo, covers, files, signer, maxBytes, urlLifetime}
+}
+
+// AddEbook attaches a file to a book. Its format is told from its content
+// rather than from its name or the type the client gives. The title,
+// authors, ISBN and date in the file's metadata make up a draft of the
+// book, and its cover becomes the book's cover if the book has none.
+func (u *ebookUsecase) AddEbook(bookID uint, r io.Reader, filename, actor string) (*model.EbookUpload, error) {
+	book, err := u.bookRepo.GetByID(bookID)
+	if err != nil {
+		return nil, err
+	}
+	head := make([]byte, 30+len(epubSignature))
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	head = head[:n]
+	format := ebookFormat(head)
+	if format == "" {
+		return nil, ErrUnsupportedEbook
+	}
+	// The metadata readers need to seek, so the file is kept on disk until
+	// it is stored.
+	spool, err := os.CreateTemp("", "ebook-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(spool.Name())
+	defer spool.Close()
+	size, err := io.Copy(spool, &sizeLimit{io.MultiReader(bytes.NewReader(head), r), u.maxBytes})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	sum, _, err := u.files.Put(spool)
+	if err != nil {
+		return nil, err
+	}
+	ebook := &model.Ebook{
+		BookID:    bookID,
+		Format:    format,
+		Filename:  ebookFilename(filename, format, bookID),
+		Size:      size,
+		Checksum:  sum,
+		CreatedBy: actor,
+	}
+	if err := u.ebookRepo.Create(ebook); err != nil {
+		return nil, err
+	}
+	upload := &model.EbookUpload{Ebook: *ebook, Draft: book, Changes: []model.FieldChange{}}
+	// The metadata is a convenience: a file it cannot be read from is still
+	// a good ebook.
+	metadata, err := readEbookMetadata(format, spool, size)
+	if err != nil {
+		return upload, nil
+	}
+	upload.Draft = ebookDraft(book, metadata)
+	upload.Changes = model.DiffFields(book.Fields(), upload.Draft.Fields())
+	if len(metadata.Cover) > 0 {
+		if upload.Cover, err = u.setMissingCover(bookID, metadata.Cover); err != nil {
+			return nil, err
+		}
+	}
+	return upload, nil
+}
+
+func readEbookMetadata(format string, r io.ReaderAt, size int64) (*util.EbookMetadata, error) {
+	if format == model.EbookFormatPDF {
+		return util.ReadPDFMetadata(r, size)
+	}
+	return util.ReadEPUBMetadata(r, size)
+}
+
+// ebookDraft returns a copy of book with the fields metadata gives.
+func ebookDraft(book *model.Book, metadata *util.EbookMetadata) *model.Book {
+	draft := *book
+	if metadata.Title != "" {
+		draft.Title = metadata.Title
+	}
+	if len(metadata.Authors) > 0 {
+		draft.Author = strings.Join(metadata.Authors, "; ")
+	}
+	if metadata.ISBN != "" {
+		draft.ISBN = metadata.ISBN
+	}
+	if date, err := model.ParseLegacyDate(metadata.Published); err == nil && !date.IsZero() {
+		draft.PublishedDate = date
+	}
+	return &draft
+}
+
+// setMissingCover makes image the cover of a book that has none. Covers
+// that are not images the cover usecase takes are skipped.
+func (u *ebookUsecase) setMissingCover(bookID uint, image []byte) (*model.Cover, error) {
+	if _, err := u.covers.GetCover(bookID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	cover, err := u.covers.SetCover(bookID, bytes.NewReader(image))
+	if errors.Is(err, ErrUnsupportedImage) || errors.Is(err, ErrCoverTooLarge) {
+		return nil, nil
+	}
+	return cover, err
+}
+
+func (u *ebookUsecase) GetEbooks(bookID uint) ([]model.Ebook, error) {
+	if _, err := u.bookRepo.GetByID(bookID); err != nil {
+		return nil, err
+	}
+	return u.ebookRepo.GetByBookID(bookID)
+}
+
+// DeleteEbook removes an ebook, and its file unless another ebook has the
+// same one.
+func (u *ebookUsecase) DeleteEbook(id uint) error {
+	ebook, err := u.ebookRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if err := u.ebookRepo.Delete(id); err != nil {
+		return err
+	}
+	shared, err := u.ebookRepo.CountByChecksum(ebook.Checksum)
+	if err != nil || shared > 0 {
+		return err
+	}
+	return u.files.Delete(ebook.Checksum)
+}
+
+// GetDownload returns an address the ebook can be downloaded from without a
+// token for a while. Staff can download every ebook; other users only those
+// of books they have on loan.
+func (u *ebookUsecase) GetDownload(id uint, username, role string) (*model.EbookDownload, error) {
+	ebook, err := u.ebookRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := u.bookRepo.GetByID(ebook.BookID); err != nil {
+		return nil, err
+	}
+	if !util.HasRole(role, "supervisor") {
+		user, err := u.userRepo.GetByUsername(username)
+		if err != nil {
+			return nil, err
+		}
+		onLoan, err := u.loanRepo.HasActiveLoan(user.ID, ebook.BookID)
+		if err != nil {
+			return nil, err
+		}
+		if !onLoan {
+			return nil, ErrNoActiveLoan
+		}
+	}
+	expires := time.Now().Add(u.urlLifetime).Truncate(time.Second)
+	return &model.EbookDownload{URL: u.signer.Sign(downloadPath(id), expires), ExpiresAt: expires}, nil
+}
+
+// OpenDownload returns the file of an ebook if the signature of its
+// download address is good and has not expired.
+func (u *ebookUsecase) OpenDownload(id uint, expires, signature string) (*model.Ebook, io.ReadSeekCloser, error) {
+	if err := u.signer.Verify(downloadPath(id), expires, signature, time.Now()); err != nil {
+		return nil, nil, err
+	}
+	ebook, err := u.ebookRepo.GetByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	file, err := u.files.Get(ebook.Checksum)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ebook, file.(io.ReadSeekCloser), nil
+}
+
+func downloadPath(id uint) string {
+	return fmt.Sprintf("ebooks/%d/download", id)
+}
+
+// ebookFormat tells the format of an ebook from its first bytes.
+func ebookFormat(head []byte) string {
+	switch {
+	case bytes.HasPrefix(head, []byte("%PDF-")):
+		return model.EbookFormatPDF
+	case bytes.HasPrefix(head, []byte("PK\x03\x04")) && len(head) >= 30+len(epubSignature) &&
+		string(head[30:30+len(epubSignature)]) == epubSignature:
+		return model.EbookFormatEPUB
+	}
+	return ""
+}
+
+// ebookFilename keeps the last element of an uploaded file name, with the
+// extension of its format.
+func ebookFilename(filename, format string, bookID uint) string {
+	name := path.Base(strings.ReplaceAll(filename, `\`, "/"))
+	if name == "." || name == "/" {
+		name = fmt.Sprintf("book-%d", bookID)
+	}
+	if !strings.EqualFold(path.Ext(name), "."+format) {
+		name += "." + format
+	}
+	return name
+}
+
+// sizeLimit fails reads once more than limit bytes have been read.
+type sizeLimit struct {
+	r     io.Reader
+	limit int64
+}
+
+func (l *sizeLimit) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.limit -= int64(n)
+	if l.limit < 0 {
+		return n, ErrEbookTooLarge
+	}
+	return n, err
+}
@@ -0,0 +1,42 @@
+package usecase
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"go.test/model"
+	util "go.test/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEbookFormat(t *testing.T) {
+	epub := "PK\x03\x04" + strings.Repeat("\x00", 26) + "mimetypeapplication/epub+zip"
+	assert.Equal(t, model.EbookFormatEPUB, ebookFormat([]byte(epub)))
+	assert.Equal(t, model.EbookFormatPDF, ebookFormat([]byte("%PDF-1.7\n")))
+	assert.Equal(t, "", ebookFormat([]byte("PK\x03\x04"+strings.Repeat("\x00", 26)+"word/document.xml")), "other ZIP files")
+	assert.Equal(t, "", ebookFormat([]byte("PK\x03\x04")))
+	assert.Equal(t, "", ebookFormat(nil))
+}
+
+func TestEbookFilename(t *testing.T) {
+	assert.Equal(t, "dune.epub", ebookFilename("dune.epub", model.EbookFormatEPUB, 1))
+	assert.Equal(t, "Dune.PDF", ebookFilename(`C:\Books\Dune.PDF`, model.EbookFormatPDF, 1))
+	assert.Equal(t, "dune.zip.epub", ebookFilename("../dune.zip", model.EbookFormatEPUB, 1))
+	assert.Equal(t, "book-7.pdf", ebookFilename("", model.EbookFormatPDF, 7))
+}
+
+func TestSizeLimit(t *testing.T) {
+	_, err := io.ReadAll(&sizeLimit{strings.NewReader("12345"), 5})
+	assert.NoError(t, err)
+	_, err = io.ReadAll(&sizeLimit{strings.NewReader("123456"), 5})
+	assert.ErrorIs(t, err, ErrEbookTooLarge)
+}
+
+func TestEbookDraft(t *testing.T) {
+	book := &model.Book{ID: 3, Title: "Dune", Author: "Herbert", PublishedDate: model.PartialDate{Year: 1965}, MaterialType: "book", Version: 4}
+	draft := ebookDraft(book, &util.EbookMetadata{Authors: []string{"Frank Herbert", "Brian Herbert"}, ISBN: "9780441172719"})
+	assert.Equal(t, &model.Book{ID: 3, Title: "Dune", Author: "Frank Herbert; Brian Herbert", ISBN: "9780441172719", PublishedDate: model.PartialDate{Year: 1965}, MaterialType: "book", Version: 4}, draft)
+	assert.Equal(t, "Herbert", book.Author, "the book is left as it is")
+}
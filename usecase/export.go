@@ -0,0 +1,204 @@
+package usecase
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"time"
+
+	"go.test/model"
+	"go.test/repository"
+	util "go.test/utils"
+)
+
+var (
+	ErrUnknownExportFormat = errors.New("format must be csv, json, ndjson, marc or marcxml")
+	ErrInvalidSince        = errors.New("since must be an RFC 3339 time such as 2024-01-31T00:00:00Z")
+)
+
+var exportContentTypes = map[string]string{
+	model.ImportFormatCSV:     "text/csv; charset=utf-8",
+	model.ImportFormatJSON:    "application/json",
+	model.ImportFormatNDJSON:  "application/x-ndjson",
+	model.ImportFormatMARC:    "application/marc",
+	model.ImportFormatMARCXML: "application/marcxml+xml",
+}
+
+// exportColumns are the fields of an exported book, in CSV column order.
+var exportColumns = []string{"id", "title", "author", "isbn", "published_date", "material_type", "version", "updated_at"}
+
+type ExportUsecase interface {
+	ExportBooks(req *model.ExportRequest, open func(format, contentType string) io.Writer) error
+}
+
+type exportUsecase struct {
+	bookRepo repository.BookRepository
+}
+
+func NewExportUsecase(bookRepo repository.BookRepository) ExportUsecase {
+	return &exportUsecase{bookRepo}
+}
+
+// ExportBooks writes the books that match the request, in CSV unless the
+// request asks for another format. The request is checked before open is
+// called for the writer to stream the export to, so that errors can still
+// be answered normally; errors after that leave the export cut short.
+func (u *exportUsecase) ExportBooks(req *model.ExportRequest, open func(format, contentType string) io.Writer) error {
+	var since time.Time
+	if req.Since != "" {
+		var err error
+		if since, err = time.Parse(time.RFC3339, req.Since); err != nil {
+			return ErrInvalidSince
+		}
+	}
+	format := req.Format
+	if format == "" {
+		format = model.ImportFormatCSV
+	}
+	contentType, ok := exportContentTypes[format]
+	if !ok {
+		return ErrUnknownExportFormat
+	}
+	writer := newBookWriter(format, open(format, contentType))
+	if err := u.bookRepo.Export(&req.BookFilter, since, writer.Write); err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+type exportedBook struct {
+	ID            uint       `json:"id"`
+	Title         string     `json:"title"`
+	Author        string     `json:"author"`
+	ISBN          string     `json:"isbn"`
+	PublishedDate string     `json:"published_date"`
+	MaterialType  string     `json:"material_type"`
+	Version       int        `json:"version"`
+	UpdatedAt     *time.Time `json:"updated_at"`
+}
+
+func exportBook(book *model.Book) *exportedBook {
+	exported := &exportedBook{
+		ID:            book.ID,
+		Title:         book.Title,
+		Author:        book.Author,
+		ISBN:          book.ISBN,
+		PublishedDate: book.PublishedDate.String(),
+		MaterialType:  book.MaterialType,
+		Version:       book.Version,
+	}
+	if !book.UpdatedAt.IsZero() {
+		updatedAt := book.UpdatedAt.UTC()
+		exported.UpdatedAt = &updatedAt
+	}
+	return exported
+}
+
+// bookWriter writes books in one export format. Close finishes the export
+// but leaves the underlying writer open.
+type bookWriter interface {
+	Write(book *model.Book) error
+	Close() error
+}
+
+func newBookWriter(format string, w io.Writer) bookWriter {
+	switch format {
+	case model.ImportFormatJSON:
+		return &jsonBookWriter{w: w}
+	case model.ImportFormatNDJSON:
+		return &ndjsonBookWriter{json.NewEncoder(w)}
+	case model.ImportFormatMARC:
+		return &marcBookWriter{w}
+	case model.ImportFormatMARCXML:
+		return &marcXMLBookWriter{util.NewMARCXMLWriter(w)}
+	}
+	return &csvBookWriter{w: csv.NewWriter(w)}
+}
+
+type csvBookWriter struct {
+	w      *csv.Writer
+	header bool
+}
+
+func (b *csvBookWriter) Write(book *model.Book) error {
+	if err := b.writeHeader(); err != nil {
+		return err
+	}
+	exported := exportBook(book)
+	var updatedAt string
+	if exported.UpdatedAt != nil {
+		updatedAt = exported.UpdatedAt.Format(time.RFC3339Nano)
+	}
+	return b.w.Write([]string{
+		strconv.FormatUint(uint64(exported.ID), 10),
+		exported.Title,
+		exported.Author,
+		exported.ISBN,
+		exported.PublishedDate,
+		exported.MaterialType,
+		strconv.Itoa(exported.Version),
+		updatedAt,
+	})
+}
+
+func (b *csvBookWriter) Close() error {
+	if err := b.writeHeader(); err != nil {
+		return err
+	}
+	b.w.Flush()
+	return b.w.Error()
+}
+
+func (b *csvBookWriter) writeHeader() error {
+	if b.header {
+		return nil
+	}
+	b.header = true
+	return b.w.Write(exportColumns)
+}
+
+// jsonBookWriter writes a JSON array with one book per line.
+type jsonBookWriter struct {
+	w     io.Writer
+	count int
+}
+
+func (b *jsonBookWriter) Write(book *model.Book) error {
+	data, err := json.Marshal(exportBook(book))
+	if err != nil {
+		return err
+	}
+	separator := ",\n"
+	if b.count == 0 {
+		separator = "[\n"
+	}
+	b.count++
+	if _, err := io.WriteString(b.w, separator); err != nil {
+		return err
+	}
+	_, err = b.w.Write(data)
+	return err
+}
+
+func (b *jsonBookWriter) Close() error {
+	end := "\n]\n"
+	if b.count == 0 {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(b.w, end)
+	return err
+}
+
+type ndjsonBookWriter struct {
+	encoder *json.Encoder
+}
+
+func (b *ndjsonBookWriter) Write(book *model.Book) error {
+	return b.encoder.Encode(exportBook(book))
+}
+
+func (b *ndjsonBookWriter) Close() error {
+	return nil
+}
@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"go.test/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeAll(t *testing.T, format string, books ...*model.Book) string {
+	t.Helper()
+	var out bytes.Buffer
+	writer := newBookWriter(format, &out)
+	for _, book := range books {
+		assert.NoError(t, writer.Write(book))
+	}
+	assert.NoError(t, writer.Close())
+	return out.String()
+}
+
+func TestBookWriters(t *testing.T) {
+	updatedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.FixedZone("WIB", 7*60*60))
+	dune := &model.Book{ID: 1, Title: "Dune", Author: "Frank Herbert", ISBN: "9780441172719", Version: 2, UpdatedAt: updatedAt}
+	odd := &model.Book{ID: 2, Title: "Odd, \"Title\"", Version: 1}
+
+	assert.Equal(t, "id,title,author,isbn,published_date,material_type,version,updated_at\n"+
+		"1,Dune,Frank Herbert,9780441172719,,,2,2024-03-01T05:00:00Z\n"+
+		"2,\"Odd, \"\"Title\"\"\",,,,,1,\n", writeAll(t, model.ImportFormatCSV, dune, odd))
+	assert.Equal(t, "id,title,author,isbn,published_date,material_type,version,updated_at\n", writeAll(t, model.ImportFormatCSV))
+
+	var books []map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(writeAll(t, model.ImportFormatJSON, dune, odd)), &books))
+	assert.Len(t, books, 2)
+	assert.Equal(t, "2024-03-01T05:00:00Z", books[0]["updated_at"])
+	assert.Nil(t, books[1]["updated_at"])
+	assert.Equal(t, "[]\n", writeAll(t, model.ImportFormatJSON))
+
+	assert.Equal(t, `{"id":1,"title":"Dune","author":"Frank Herbert","isbn":"9780441172719","published_date":"","material_type":"","version":2,"updated_at":"2024-03-01T05:00:00Z"}`+"\n",
+		writeAll(t, model.ImportFormatNDJSON, dune))
+	assert.Equal(t, "", writeAll(t, model.ImportFormatNDJSON))
+}
+
+func TestExportCanBeImported(t *testing.T) {
+	book := &model.Book{ID: 1, Title: "Odd, \"Title\"", Author: "Anon"}
+	for _, format := range []string{model.ImportFormatCSV, model.ImportFormatJSON, model.ImportFormatNDJSON} {
+		records, rejected := readAll(t, format, writeAll(t, format, book))
+		assert.Empty(t, rejected, format)
+		if assert.Len(t, records, 1, format) {
+			assert.Equal(t, "Odd, \"Title\"", records[0]["title"], format)
+			assert.Equal(t, "Anon", records[0]["author"], format)
+		}
+	}
+}
+
+func TestExportBooksChecksRequest(t *testing.T) {
+	u := NewExportUsecase(nil)
+	open := func(format, contentType string) io.Writer {
+		t.Fatalf("opened a %s export for a bad request", format)
+		return nil
+	}
+	assert.ErrorIs(t, u.ExportBooks(&model.ExportRequest{Format: "xml"}, open), ErrUnknownExportFormat)
+	assert.ErrorIs(t, u.ExportBooks(&model.ExportRequest{Since: "yesterday"}, open), ErrInvalidSince)
+}
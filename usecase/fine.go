@@ -0,0 +1,211 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.test/model"
+	"go.test/repository"
+)
+
+var (
+	ErrFinesOutstanding = errors.New("outstanding fines must be paid before borrowing")
+	ErrInvalidAmount    = repository.ErrInvalidAmount
+	ErrChargeWaived     = repository.ErrChargeWaived
+	ErrNothingOwed      = repository.ErrNothingOwed
+)
+
+type FineUsecase interface {
+	GetAccount(userID uint) (*model.FineAccount, error)
+	GetMyAccount(username string) (*model.FineAccount, error)
+	OverdueCharge(loan *model.Loan, returnedAt time.Time) (*model.FineEntry, error)
+	CanBorrow(userID uint) error
+	Pay(username string, req *model.PaymentRequest) (*model.FineEntry, error)
+	RecordPayment(userID uint, req *model.PaymentRequest, actor string) (*model.FineEntry, error)
+	Waive(chargeID uint, reason, actor string) (*model.FineEntry, error)
+}
+
+type fineUsecase struct {
+	fineRepo       repository.FineRepository
+	loanRepo       repository.LoanRepository
+	copyRepo       repository.CopyRepository
+	userRepo       repository.UserRepository
+	policyUsecase  PolicyUsecase
+	provider       PaymentProvider
+	blockThreshold int64
+}
+
+// NewFineUsecase builds the fines ledger. Users whose balance, including
+// fines still accruing on overdue loans, is above blockThreshold cents
+// cannot borrow.
+func NewFineUsecase(fineRepo repository.FineRepository, loanRepo repository.LoanRepository, copyRepo repository.CopyRepository, userRepo repository.UserRepository, policyUsecase PolicyUsecase, provider PaymentProvider, blockThreshold int64) FineUsecase {
+	return &fineUsecase{fineRepo, loanRepo, copyRepo, userRepo, policyUsecase, provider, blockThreshold}
+}
+
+func (u *fineUsecase) GetAccount(userID uint) (*model.FineAccount, error) {
+	user, err := u.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	account, err := u.summary(user)
+	if err != nil {
+		return nil, err
+	}
+	if account.Entries, err = u.fineRepo.GetByUserID(user.ID); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+func (u *fineUsecase) GetMyAccount(username string) (*model.FineAccount, error) {
+	user, err := u.userRepo.GetByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	return u.GetAccount(user.ID)
+}
+
+// OverdueCharge returns the overdue fine for a loan returned at returnedAt,
+// or nil if there is none. It is posted by the return itself.
+func (u *fineUsecase) OverdueCharge(loan *model.Loan, returnedAt time.Time) (*model.FineEntry, error) {
+	policy, err := u.policyFor(loan)
+	if err != nil {
+		return nil, err
+	}
+	amount := policy.Fine(loan.DueAt, returnedAt)
+	if amount == 0 {
+		return nil, nil
+	}
+	days := int(returnedAt.Sub(loan.DueAt).Hours() / 24)
+	return &model.FineEntry{
+		UserID: loan.UserID,
+		Type:   model.FineEntryCharge,
+		Amount: amount,
+		LoanID: &loan.ID,
+		Reason: fmt.Sprintf("returned %d day(s) late", days),
+		Actor:  "system",
+	}, nil
+}
+
+func (u *fineUsecase) CanBorrow(userID uint) error {
+	user, err := u.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+	account, err := u.summary(user)
+	if err != nil {
+		return err
+	}
+	if account.Blocked {
+		return ErrFinesOutstanding
+	}
+	return nil
+}
+
+// Pay takes an online payment from the current user through the payment
+// provider. The payment is recorded as pending before the provider is
+// charged, so it counts against the balance while the charge is in flight,
+// and is then settled with the provider's reference or marked as failed.
+func (u *fineUsecase) Pay(username string, req *model.PaymentRequest) (*model.FineEntry, error) {
+	user, err := u.userRepo.GetByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	payment := &model.FineEntry{
+		UserID: user.ID,
+		Amount: req.Amount,
+		Reason: "online payment",
+		Actor:  username,
+		Status: model.FinePaymentPending,
+	}
+	if err := u.fineRepo.AddPayment(payment); err != nil {
+		return nil, err
+	}
+	reference, err := u.provider.Charge(user.ID, req.Amount, req.Token)
+	if err != nil {
+		payment.Status = model.FinePaymentFailed
+		return nil, errors.Join(err, u.fineRepo.SettlePayment(payment))
+	}
+	payment.Status = ""
+	payment.Reference = reference
+	if err := u.fineRepo.SettlePayment(payment); err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+// RecordPayment records a payment taken by staff, for example at the desk.
+func (u *fineUsecase) RecordPayment(userID uint, req *model.PaymentRequest, actor string) (*model.FineEntry, error) {
+	if _, err := u.userRepo.GetByID(userID); err != nil {
+		return nil, err
+	}
+	payment := &model.FineEntry{
+		UserID: userID,
+		Amount: req.Amount,
+		Reason: req.Reason,
+		Actor:  actor,
+	}
+	if err := u.fineRepo.AddPayment(payment); err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+func (u *fineUsecase) Waive(chargeID uint, reason, actor string) (*model.FineEntry, error) {
+	if reason == "" {
+		return nil, ErrReasonRequired
+	}
+	waiver := &model.FineEntry{
+		WaivedID: &chargeID,
+		Reason:   reason,
+		Actor:    actor,
+	}
+	if err := u.fineRepo.Waive(waiver); err != nil {
+		return nil, err
+	}
+	return waiver, nil
+}
+
+// summary computes the balance of a user and the fines accruing on their
+// overdue loans, without loading the ledger.
+func (u *fineUsecase) summary(user *model.User) (*model.FineAccount, error) {
+	balance, err := u.fineRepo.GetBalance(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	loans, err := u.loanRepo.GetByUserID(user.ID, true)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	var accruing int64
+	for i := range loans {
+		if !loans[i].DueAt.Before(now) {
+			continue
+		}
+		policy, err := u.policyFor(&loans[i])
+		if err != nil {
+			return nil, err
+		}
+		accruing += policy.Fine(loans[i].DueAt, now)
+	}
+	return &model.FineAccount{
+		UserID:   user.ID,
+		Balance:  balance,
+		Accruing: accruing,
+		Blocked:  balance+accruing > u.blockThreshold,
+	}, nil
+}
+
+func (u *fineUsecase) policyFor(loan *model.Loan) (*model.PolicyDecision, error) {
+	borrower, err := u.userRepo.GetByID(loan.UserID)
+	if err != nil {
+		return nil, err
+	}
+	bookCopy, err := u.copyRepo.GetByID(loan.CopyID)
+	if err != nil {
+		return nil, err
+	}
+	return u.policyUsecase.EvaluateCheckout(borrower, bookCopy)
+}
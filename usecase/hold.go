@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"errors"
+	"time"
+
+	"go.test/model"
+	"go.test/repository"
+	util "go.test/utils"
+)
+
+var (
+	ErrHoldExists      = errors.New("you already have an active hold on this book")
+	ErrCopiesAvailable = errors.New("a copy of this book is available, no hold is needed")
+	ErrHoldClosed      = repository.ErrHoldClosed
+)
+
+type HoldUsecase interface {
+	PlaceHold(bookID uint, username string) (*model.Hold, error)
+	CancelHold(id uint, username, role string) error
+	GetMyHolds(username string) ([]model.Hold, error)
+	GetQueue(bookID uint) ([]model.Hold, error)
+	ProcessHolds() (int, int, error)
+}
+
+type holdUsecase struct {
+	holdRepo   repository.HoldRepository
+	copyRepo   repository.CopyRepository
+	bookRepo   repository.BookRepository
+	userRepo   repository.UserRepository
+	holdPickup time.Duration
+}
+
+func NewHoldUsecase(holdRepo repository.HoldRepository, copyRepo repository.CopyRepository, bookRepo repository.BookRepository, userRepo repository.UserRepository, holdPickup time.Duration) HoldUsecase {
+	return &holdUsecase{holdRepo, copyRepo, bookRepo, userRepo, holdPickup}
+}
+
+// PlaceHold puts the user at the back of the queue for a book. Holds are only
+// taken when no copy of the book is on the shelf.
+func (u *holdUsecase) PlaceHold(bookID uint, username string) (*model.Hold, error) {
+	user, err := u.userRepo.GetByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := u.bookRepo.GetByID(bookID); err != nil {
+		return nil, err
+	}
+	availability, err := u.copyRepo.GetAvailability([]uint{bookID})
+	if err != nil {
+		return nil, err
+	}
+	if availability[bookID].Available > 0 {
+		return nil, ErrCopiesAvailable
+	}
+	active, err := u.holdRepo.GetByUserID(user.ID, true)
+	if err != nil {
+		return nil, err
+	}
+	for _, hold := range active {
+		if hold.BookID == bookID {
+			return nil, ErrHoldExists
+		}
+	}
+
+	hold := &model.Hold{
+		BookID: bookID,
+		UserID: user.ID,
+		Status: model.HoldStatusWaiting,
+	}
+	if err := u.holdRepo.Create(hold); err != nil {
+		return nil, err
+	}
+	if hold.Position, err = u.holdRepo.GetPosition(hold); err != nil {
+		return nil, err
+	}
+	return hold, nil
+}
+
+// CancelHold cancels a hold on behalf of its owner or a supervisor.
+func (u *holdUsecase) CancelHold(id uint, username, role string) error {
+	hold, err := u.holdRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if !util.HasRole(role, "supervisor") {
+		user, err := u.userRepo.GetByUsername(username)
+		if err != nil {
+			return err
+		}
+		if hold.UserID != user.ID {
+			return ErrForbidden
+		}
+	}
+	return u.holdRepo.Cancel(id, time.Now(), u.holdPickup)
+}
+
+// GetMyHolds returns the user's active holds together with their position
+// in each queue. Holds that are ready for pickup have position zero.
+func (u *holdUsecase) GetMyHolds(username string) ([]model.Hold, error) {
+	user, err := u.userRepo.GetByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	holds, err := u.holdRepo.GetByUserID(user.ID, true)
+	if err != nil {
+		return nil, err
+	}
+	for i := range holds {
+		if holds[i].Status != model.HoldStatusWaiting {
+			continue
+		}
+		if holds[i].Position, err = u.holdRepo.GetPosition(&holds[i]); err != nil {
+			return nil, err
+		}
+	}
+	return holds, nil
+}
+
+func (u *holdUsecase) GetQueue(bookID uint) ([]model.Hold, error) {
+	holds, err := u.holdRepo.GetQueue(bookID)
+	if err != nil {
+		return nil, err
+	}
+	position := 0
+	for i := range holds {
+		if holds[i].Status == model.HoldStatusWaiting {
+			position++
+			holds[i].Position = position
+		}
+	}
+	return holds, nil
+}
+
+// ProcessHolds expires holds that were not picked up in time and sets aside
+// copies that became available for waiting holds. It returns the number of
+// expired holds and the number of holds that became ready.
+func (u *holdUsecase) ProcessHolds() (int, int, error) {
+	now := time.Now()
+	expired, err := u.holdRepo.ExpireReady(now, u.holdPickup)
+	if err != nil {
+		return expired, 0, err
+	}
+	assigned, err := u.holdRepo.AssignAvailable(now, u.holdPickup)
+	return expired, assigned, err
+}
@@ -0,0 +1,306 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"go.test/model"
+	"go.test/repository"
+	util "go.test/utils"
+)
+
+var (
+	ErrUnknownImportFormat = errors.New("format must be csv, json, ndjson, marc or marcxml")
+	ErrUnknownImportMode   = errors.New("mode must be atomic or best_effort")
+	ErrInvalidMapping      = errors.New(`mapping must be a list of "column:field" pairs, where field is title, author, isbn, published_date or material_type`)
+	ErrMalformedImport     = errors.New("the upload cannot be read")
+)
+
+// maxImportValue caps the length of an imported value.
+const maxImportValue = 255
+
+type ImportUsecase interface {
+	ImportBooks(req *model.ImportRequest, data io.Reader, size int64, actor string) (*model.ImportJob, error)
+	GetJob(id uint) (*model.ImportJob, error)
+	GetRows(id uint, action string) ([]model.ImportRow, error)
+}
+
+type importUsecase struct {
+	importRepo repository.ImportRepository
+	syncLimit  int64
+}
+
+// NewImportUsecase returns an ImportUsecase that imports uploads of up to
+// syncLimit bytes while the client waits, and larger ones in the background.
+func NewImportUsecase(importRepo repository.ImportRepository, syncLimit int64) ImportUsecase {
+	return &importUsecase{importRepo, syncLimit}
+}
+
+// ImportBooks starts an import of the books in data, which holds size bytes
+// or -1 if that is not known. Small uploads are imported right away and come
+// back with their row reports. Others are saved to a temporary file and
+// imported in the background; the job returned is still queued.
+func (u *importUsecase) ImportBooks(req *model.ImportRequest, data io.Reader, size int64, actor string) (*model.ImportJob, error) {
+	switch req.Format {
+	case model.ImportFormatCSV, model.ImportFormatJSON, model.ImportFormatNDJSON, model.ImportFormatMARC, model.ImportFormatMARCXML:
+	default:
+		return nil, ErrUnknownImportFormat
+	}
+	mode := req.Mode
+	if mode == "" {
+		mode = model.ImportModeBestEffort
+	}
+	if mode != model.ImportModeBestEffort && mode != model.ImportModeAtomic {
+		return nil, ErrUnknownImportMode
+	}
+	mapping, err := parseMapping(req.Mapping)
+	if err != nil {
+		return nil, err
+	}
+	job := &model.ImportJob{
+		Status: model.ImportStatusQueued,
+		Format: req.Format,
+		Mode:   mode,
+		DryRun: req.DryRun,
+		Upsert: req.Upsert,
+		Actor:  actor,
+		Size:   size,
+	}
+
+	if !req.Async && size >= 0 && size <= u.syncLimit {
+		if err := u.importRepo.CreateJob(job); err != nil {
+			return nil, err
+		}
+		u.run(job, data, mapping)
+		if job.Rows, err = u.importRepo.GetRows(job.ID, ""); err != nil {
+			return nil, err
+		}
+		setProgress(job)
+		return job, nil
+	}
+
+	file, err := os.CreateTemp("", "book-import-*")
+	if err != nil {
+		return nil, err
+	}
+	if job.Size, err = io.Copy(file, data); err == nil {
+		_, err = file.Seek(0, io.SeekStart)
+	}
+	if err == nil {
+		err = u.importRepo.CreateJob(job)
+	}
+	if err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return nil, err
+	}
+	queued := *job
+	go func() {
+		defer os.Remove(file.Name())
+		defer file.Close()
+		u.run(job, file, mapping)
+	}()
+	return &queued, nil
+}
+
+func (u *importUsecase) GetJob(id uint) (*model.ImportJob, error) {
+	job, err := u.importRepo.GetJob(id)
+	if err != nil {
+		return nil, err
+	}
+	setProgress(job)
+	return job, nil
+}
+
+// GetRows returns the row reports of an import. A non-empty action returns
+// only the rows with that outcome.
+func (u *importUsecase) GetRows(id uint, action string) ([]model.ImportRow, error) {
+	if _, err := u.importRepo.GetJob(id); err != nil {
+		return nil, err
+	}
+	return u.importRepo.GetRows(id, action)
+}
+
+// run imports the records in data and saves the outcome with the job.
+func (u *importUsecase) run(job *model.ImportJob, data io.Reader, mapping map[string]string) {
+	job.Status = model.ImportStatusRunning
+	err := u.importRepo.UpdateJob(job)
+	if err == nil {
+		err = u.importRecords(job, data, mapping)
+	}
+	now := time.Now()
+	job.FinishedAt = &now
+	switch {
+	case err != nil:
+		job.Status = model.ImportStatusFailed
+		job.Error = err.Error()
+	case job.Committed || job.DryRun:
+		job.Status = model.ImportStatusCompleted
+	default:
+		job.Status = model.ImportStatusRolledBack
+	}
+	if err := u.importRepo.UpdateJob(job); err != nil {
+		log.Printf("import %d: saving the outcome: %v", job.ID, err)
+	}
+}
+
+// importRecords imports the records in data. A panic on a malformed upload
+// fails the import instead of taking the server down with it, as queued
+// imports run outside of any request.
+func (u *importUsecase) importRecords(job *model.ImportJob, data io.Reader, mapping map[string]string) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("reading the upload failed: %v", p)
+		}
+	}()
+	counter := &countingReader{reader: data}
+	records, err := newRecordReader(job.Format, counter)
+	if err != nil {
+		return err
+	}
+	number := 0
+	next := func() (*model.ImportRow, error) {
+		record, err := records.Read()
+		job.BytesRead = counter.count
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		var rejected *recordError
+		if err != nil && !errors.As(err, &rejected) {
+			return nil, err
+		}
+		number++
+		row := &model.ImportRow{Number: number}
+		if rejected == nil {
+			err = mapRecord(row, record, mapping)
+		}
+		if err != nil {
+			row.Action = model.ImportRowRejected
+			row.Reason = err.Error()
+		} else if marc, ok := records.(*marcReader); ok {
+			row.MARC = marc.record
+		}
+		return row, nil
+	}
+	return u.importRepo.Import(job, next, mergeImported(job.Upsert))
+}
+
+// mapRecord fills in the book fields of a row from an uploaded record.
+// Columns without a mapping are ignored.
+func mapRecord(row *model.ImportRow, record map[string]string, mapping map[string]string) error {
+	fields := make(map[string]interface{})
+	for column, value := range record {
+		field, ok := mapping[normalizeColumn(column)]
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if len(value) > maxImportValue {
+			return fmt.Errorf("%s is longer than %d characters", column, maxImportValue)
+		}
+		fields[field] = value
+	}
+	if isbn, _ := fields["isbn"].(string); isbn != "" {
+		normalized, ok := util.NormalizeISBN(isbn)
+		if !ok {
+			return fmt.Errorf("%q is not a valid ISBN", isbn)
+		}
+		fields["isbn"] = normalized
+		row.ISBN = normalized
+	}
+	if published, _ := fields["published_date"].(string); published != "" {
+		date, err := model.ParseLegacyDate(published)
+		if err != nil {
+			return fmt.Errorf("%q is not a date", published)
+		}
+		fields["published_date"] = date.String()
+	}
+	row.Title, _ = fields["title"].(string)
+	row.Fields = fields
+	return nil
+}
+
+// mergeImported returns how rows are applied to the catalog. New books need
+// a title. Books that are already in the catalog are only updated when
+// upsert is set, and then only with the values the row has; their ISBN is
+// left as it is.
+func mergeImported(upsert bool) func(row *model.ImportRow, existing *model.Book) (*model.Book, error) {
+	return func(row *model.ImportRow, existing *model.Book) (*model.Book, error) {
+		if existing == nil {
+			book := &model.Book{}
+			book.SetFields(row.Fields)
+			if book.Title == "" {
+				return nil, errors.New("title is required")
+			}
+			return book, nil
+		}
+		if !upsert {
+			return nil, fmt.Errorf("book %d already has this ISBN", existing.ID)
+		}
+		changes := make(map[string]interface{}, len(row.Fields))
+		for field, value := range row.Fields {
+			// The book was found by its ISBN, so it is kept as it was
+			// written rather than replaced by the row's bare digits.
+			if value != "" && field != "isbn" {
+				changes[field] = value
+			}
+		}
+		existing.SetFields(changes)
+		return existing, nil
+	}
+}
+
+// parseMapping reads a column mapping such as "Writer:author,ISBN13:isbn".
+// Columns named after a book field, ignoring case, map to that field unless
+// the mapping says otherwise.
+func parseMapping(spec string) (map[string]string, error) {
+	known := (&model.Book{}).Fields()
+	mapping := make(map[string]string, len(known))
+	for field := range known {
+		mapping[field] = field
+	}
+	if strings.TrimSpace(spec) == "" {
+		return mapping, nil
+	}
+	for _, pair := range strings.Split(spec, ",") {
+		column, field, ok := strings.Cut(pair, ":")
+		field = strings.TrimSpace(field)
+		if _, known := known[field]; !ok || !known || strings.TrimSpace(column) == "" {
+			return nil, ErrInvalidMapping
+		}
+		mapping[normalizeColumn(column)] = field
+	}
+	return mapping, nil
+}
+
+func normalizeColumn(column string) string {
+	column = strings.ToLower(strings.TrimSpace(column))
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(column)
+}
+
+// setProgress fills in how far an import has got, as a percentage of the
+// upload read.
+func setProgress(job *model.ImportJob) {
+	switch {
+	case job.FinishedAt != nil:
+		job.Progress = 100
+	case job.Size > 0:
+		job.Progress = min(round2(100*float64(job.BytesRead)/float64(job.Size)), 99.99)
+	}
+}
+
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.count += int64(n)
+	return n, err
+}
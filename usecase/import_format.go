@@ -0,0 +1,178 @@
+package usecase
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"go.test/model"
+	util "go.test/utils"
+)
+
+// maxNDJSONLine caps the length of a single NDJSON record.
+const maxNDJSONLine = 1 << 20
+
+// recordReader reads the records of an upload one at a time, keyed by
+// column or property name. It returns io.EOF after the last record. A
+// *recordError rejects one record; reading can go on after it.
+type recordReader interface {
+	Read() (map[string]string, error)
+}
+
+type recordError struct {
+	reason string
+}
+
+func (e *recordError) Error() string {
+	return e.reason
+}
+
+func newRecordReader(format string, r io.Reader) (recordReader, error) {
+	switch format {
+	case model.ImportFormatCSV:
+		return newCSVReader(r)
+	case model.ImportFormatJSON:
+		return newJSONReader(r)
+	case model.ImportFormatNDJSON:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxNDJSONLine)
+		return &ndjsonReader{scanner}, nil
+	case model.ImportFormatMARC:
+		return &marcReader{read: util.NewMARCReader(r).Read}, nil
+	case model.ImportFormatMARCXML:
+		return &marcReader{read: util.NewMARCXMLReader(r).Read}, nil
+	}
+	return nil, ErrUnknownImportFormat
+}
+
+type csvReader struct {
+	reader *csv.Reader
+	header []string
+}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return &csvReader{reader: reader}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedImport, err)
+	}
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+	return &csvReader{reader, header}, nil
+}
+
+func (r *csvReader) Read() (map[string]string, error) {
+	if r.header == nil {
+		return nil, io.EOF
+	}
+	fields, err := r.reader.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return nil, &recordError{parseErr.Err.Error()}
+		}
+		return nil, err
+	}
+	if len(fields) != len(r.header) {
+		return nil, &recordError{fmt.Sprintf("expected %d fields, got %d", len(r.header), len(fields))}
+	}
+	record := make(map[string]string, len(fields))
+	for i, value := range fields {
+		record[r.header[i]] = value
+	}
+	return record, nil
+}
+
+// jsonReader reads the objects of a JSON array one by one, so that the
+// array does not have to fit in memory.
+type jsonReader struct {
+	decoder *json.Decoder
+}
+
+func newJSONReader(r io.Reader) (*jsonReader, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedImport, err)
+	}
+	if token != json.Delim('[') {
+		return nil, fmt.Errorf("%w: expected an array of objects", ErrMalformedImport)
+	}
+	return &jsonReader{decoder}, nil
+}
+
+func (r *jsonReader) Read() (map[string]string, error) {
+	if !r.decoder.More() {
+		if _, err := r.decoder.Token(); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrMalformedImport, err)
+		}
+		return nil, io.EOF
+	}
+	var object map[string]interface{}
+	if err := r.decoder.Decode(&object); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return nil, &recordError{"expected an object"}
+		}
+		return nil, fmt.Errorf("%w: %v", ErrMalformedImport, err)
+	}
+	return flattenRecord(object)
+}
+
+type ndjsonReader struct {
+	scanner *bufio.Scanner
+}
+
+func (r *ndjsonReader) Read() (map[string]string, error) {
+	for r.scanner.Scan() {
+		line := bytes.TrimSpace(r.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		decoder := json.NewDecoder(bytes.NewReader(line))
+		decoder.UseNumber()
+		var object map[string]interface{}
+		if err := decoder.Decode(&object); err != nil || object == nil {
+			return nil, &recordError{"expected a JSON object"}
+		}
+		return flattenRecord(object)
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedImport, err)
+	}
+	return nil, io.EOF
+}
+
+// flattenRecord turns the values of a JSON object into strings. Nested
+// objects and arrays reject the record.
+func flattenRecord(object map[string]interface{}) (map[string]string, error) {
+	record := make(map[string]string, len(object))
+	for name, value := range object {
+		switch value := value.(type) {
+		case nil:
+			record[name] = ""
+		case string:
+			record[name] = value
+		case json.Number:
+			record[name] = value.String()
+		case bool:
+			record[name] = strconv.FormatBool(value)
+		default:
+			return nil, &recordError{fmt.Sprintf("%s is not a plain value", name)}
+		}
+	}
+	return record, nil
+}
@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"go.test/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func readAll(t *testing.T, format, data string) ([]map[string]string, []string) {
+	t.Helper()
+	reader, err := newRecordReader(format, strings.NewReader(data))
+	assert.NoError(t, err)
+	var records []map[string]string
+	var rejected []string
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return records, rejected
+		}
+		var recordErr *recordError
+		if errors.As(err, &recordErr) {
+			rejected = append(rejected, recordErr.reason)
+			continue
+		}
+		assert.NoError(t, err)
+		records = append(records, record)
+	}
+}
+
+func TestRecordReaders(t *testing.T) {
+	records, rejected := readAll(t, model.ImportFormatCSV, "\ufefftitle,Writer\nDune,Frank Herbert\nEmma\n\"Odd, Title\",\"Anon\"\n")
+	assert.Equal(t, []map[string]string{
+		{"title": "Dune", "Writer": "Frank Herbert"},
+		{"title": "Odd, Title", "Writer": "Anon"},
+	}, records)
+	assert.Equal(t, []string{"expected 2 fields, got 1"}, rejected)
+
+	records, rejected = readAll(t, model.ImportFormatJSON, `[{"title":"Dune","isbn":9780441172719}, "nope", {"title":"Emma","tags":["x"]}, {"title":null}]`)
+	assert.Equal(t, []map[string]string{{"title": "Dune", "isbn": "9780441172719"}, {"title": ""}}, records)
+	assert.Equal(t, []string{"expected an object", "tags is not a plain value"}, rejected)
+
+	records, rejected = readAll(t, model.ImportFormatNDJSON, "{\"title\":\"Dune\"}\n\nnot json\n{\"title\":\"Emma\",\"read\":true}\n")
+	assert.Equal(t, []map[string]string{{"title": "Dune"}, {"title": "Emma", "read": "true"}}, records)
+	assert.Equal(t, []string{"expected a JSON object"}, rejected)
+
+	_, err := newRecordReader(model.ImportFormatJSON, strings.NewReader(`{"title":"Dune"}`))
+	assert.ErrorIs(t, err, ErrMalformedImport)
+}
+
+func TestMapRecord(t *testing.T) {
+	mapping, err := parseMapping("Writer:author, Year Published:published_date")
+	assert.NoError(t, err)
+
+	row := &model.ImportRow{}
+	err = mapRecord(row, map[string]string{"Title": " Dune ", "writer": "Frank Herbert", "year-published": "1965", "ISBN": "978-0-441-17271-9", "shelf": "B2"}, mapping)
+	assert.NoError(t, err)
+	assert.Equal(t, "9780441172719", row.ISBN)
+	assert.Equal(t, "Dune", row.Title)
+	assert.Equal(t, map[string]interface{}{"title": "Dune", "author": "Frank Herbert", "published_date": "1965", "isbn": "9780441172719"}, row.Fields)
+
+	err = mapRecord(&model.ImportRow{}, map[string]string{"isbn": "978-0-441-17271-8"}, mapping)
+	assert.EqualError(t, err, `"978-0-441-17271-8" is not a valid ISBN`)
+
+	row = &model.ImportRow{}
+	assert.NoError(t, mapRecord(row, map[string]string{"published_date": "August 1, 1965"}, mapping))
+	assert.Equal(t, "1965-08-01", row.Fields["published_date"])
+	err = mapRecord(&model.ImportRow{}, map[string]string{"published_date": "1960s"}, mapping)
+	assert.EqualError(t, err, `"1960s" is not a date`)
+
+	_, err = parseMapping("Writer:writer")
+	assert.ErrorIs(t, err, ErrInvalidMapping)
+	_, err = parseMapping("author")
+	assert.ErrorIs(t, err, ErrInvalidMapping)
+}
+
+func TestMergeImported(t *testing.T) {
+	row := &model.ImportRow{Fields: map[string]interface{}{"title": "", "author": "Frank Herbert"}}
+
+	_, err := mergeImported(false)(row, nil)
+	assert.EqualError(t, err, "title is required")
+
+	existing := &model.Book{ID: 3, Title: "Dune", Author: "Herbert"}
+	_, err = mergeImported(false)(row, existing)
+	assert.EqualError(t, err, "book 3 already has this ISBN")
+
+	book, err := mergeImported(true)(row, existing)
+	assert.NoError(t, err)
+	assert.Equal(t, &model.Book{ID: 3, Title: "Dune", Author: "Frank Herbert"}, book)
+
+	row.Fields["isbn"] = "9780441172719"
+	existing.ISBN = "978-0-441-17271-9"
+	book, err = mergeImported(true)(row, existing)
+	assert.NoError(t, err)
+	assert.Equal(t, "978-0-441-17271-9", book.ISBN)
+}
@@ -0,0 +1,235 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+
+	"go.test/model"
+	"go.test/repository"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrListNameRequired = errors.New("a list name is required")
+	ErrShelfLocked      = errors.New("built-in shelves cannot be renamed or deleted")
+	ErrAlreadyOnList    = repository.ErrAlreadyOnList
+	ErrNotOnList        = repository.ErrNotOnList
+)
+
+type ListUsecase interface {
+	GetMyLists(username string) ([]model.ReadingList, error)
+	GetList(id uint, username string) (*model.ReadingList, error)
+	GetSharedList(token string) (*model.ReadingList, error)
+	CreateList(list *model.ReadingList, username string) error
+	UpdateList(list *model.ReadingList, username string) error
+	DeleteList(id uint, username string) error
+	AddBook(listID uint, req *model.ListEntryRequest, username string) (*model.ListEntry, error)
+	UpdateEntry(listID, bookID uint, req *model.ListEntryRequest, username string) (*model.ListEntry, error)
+	RemoveBook(listID, bookID uint, username string) error
+}
+
+type listUsecase struct {
+	listRepo repository.ListRepository
+	bookRepo repository.BookRepository
+	userRepo repository.UserRepository
+}
+
+func NewListUsecase(listRepo repository.ListRepository, bookRepo repository.BookRepository, userRepo repository.UserRepository) ListUsecase {
+	return &listUsecase{listRepo, bookRepo, userRepo}
+}
+
+// GetMyLists returns the current user's shelves and custom lists, creating
+// the shelves on first use.
+func (u *listUsecase) GetMyLists(username string) ([]model.ReadingList, error) {
+	user, err := u.userRepo.GetByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.listRepo.EnsureShelves(user.ID); err != nil {
+		return nil, err
+	}
+	return u.listRepo.GetByUserID(user.ID)
+}
+
+// GetList returns a list with its entries. Other users' private lists are
+// reported as not found.
+func (u *listUsecase) GetList(id uint, username string) (*model.ReadingList, error) {
+	list, err := u.listRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if list.Public {
+		return list, nil
+	}
+	user, err := u.userRepo.GetByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	if list.UserID != user.ID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return list, nil
+}
+
+// GetSharedList returns a public list by its share token. Lists that have
+// been made private again are not found.
+func (u *listUsecase) GetSharedList(token string) (*model.ReadingList, error) {
+	list, err := u.listRepo.GetByShareToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if !list.Public {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return list, nil
+}
+
+func (u *listUsecase) CreateList(list *model.ReadingList, username string) error {
+	if list.Name == "" {
+		return ErrListNameRequired
+	}
+	user, err := u.userRepo.GetByUsername(username)
+	if err != nil {
+		return err
+	}
+	list.ID = 0
+	list.UserID = user.ID
+	list.Shelf = nil
+	list.ShareToken = nil
+	list.Entries = nil
+	if err := setShareToken(list); err != nil {
+		return err
+	}
+	return u.listRepo.Create(list)
+}
+
+// UpdateList changes the name, description and visibility of a list. Shelves
+// can only be made public or private.
+func (u *listUsecase) UpdateList(list *model.ReadingList, username string) error {
+	current, err := u.ownList(list.ID, username)
+	if err != nil {
+		return err
+	}
+	if current.Shelf != nil && list.Name != "" && list.Name != current.Name {
+		return ErrShelfLocked
+	}
+	if current.Shelf == nil {
+		if list.Name == "" {
+			return ErrListNameRequired
+		}
+		current.Name = list.Name
+	}
+	current.Description = list.Description
+	current.Public = list.Public
+	if err := setShareToken(current); err != nil {
+		return err
+	}
+	if err := u.listRepo.Update(current); err != nil {
+		return err
+	}
+	*list = *current
+	return nil
+}
+
+func (u *listUsecase) DeleteList(id uint, username string) error {
+	list, err := u.ownList(id, username)
+	if err != nil {
+		return err
+	}
+	if list.Shelf != nil {
+		return ErrShelfLocked
+	}
+	return u.listRepo.Delete(id)
+}
+
+// AddBook puts a book on one of the current user's lists. Putting a book on
+// the to-read, reading or read shelf takes it off the other two.
+func (u *listUsecase) AddBook(listID uint, req *model.ListEntryRequest, username string) (*model.ListEntry, error) {
+	list, err := u.ownList(listID, username)
+	if err != nil {
+		return nil, err
+	}
+	book, err := u.bookRepo.GetByID(req.BookID)
+	if err != nil {
+		return nil, err
+	}
+
+	var removeFrom []uint
+	if list.Shelf != nil && *list.Shelf != model.ShelfFavorites {
+		lists, err := u.listRepo.GetByUserID(list.UserID)
+		if err != nil {
+			return nil, err
+		}
+		for _, other := range lists {
+			if other.Shelf != nil && other.ID != list.ID && *other.Shelf != model.ShelfFavorites {
+				removeFrom = append(removeFrom, other.ID)
+			}
+		}
+	}
+
+	entry := &model.ListEntry{
+		ListID:   list.ID,
+		BookID:   book.ID,
+		Position: req.Position,
+	}
+	if req.Note != nil {
+		entry.Note = *req.Note
+	}
+	if err := u.listRepo.AddEntry(entry, removeFrom); err != nil {
+		return nil, err
+	}
+	entry.Book = book
+	return entry, nil
+}
+
+func (u *listUsecase) UpdateEntry(listID, bookID uint, req *model.ListEntryRequest, username string) (*model.ListEntry, error) {
+	if _, err := u.ownList(listID, username); err != nil {
+		return nil, err
+	}
+	return u.listRepo.UpdateEntry(listID, bookID, req.Position, req.Note)
+}
+
+func (u *listUsecase) RemoveBook(listID, bookID uint, username string) error {
+	if _, err := u.ownList(listID, username); err != nil {
+		return err
+	}
+	return u.listRepo.RemoveEntry(listID, bookID)
+}
+
+// ownList returns a list of the current user. Other users' lists are
+// reported as not found when private and forbidden when public.
+func (u *listUsecase) ownList(id uint, username string) (*model.ReadingList, error) {
+	list, err := u.listRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	user, err := u.userRepo.GetByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	if list.UserID != user.ID {
+		if list.Public {
+			return nil, ErrForbidden
+		}
+		return nil, gorm.ErrRecordNotFound
+	}
+	return list, nil
+}
+
+// setShareToken gives a public list a share token the first time it is made
+// public. The token is kept when the list goes private so that old links
+// work again if it is made public later.
+func setShareToken(list *model.ReadingList) error {
+	if !list.Public || list.ShareToken != nil {
+		return nil
+	}
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return err
+	}
+	token := hex.EncodeToString(buf)
+	list.ShareToken = &token
+	return nil
+}
@@ -0,0 +1,223 @@
+package usecase
+
+import (
+	"errors"
+	"time"
+
+	"go.test/model"
+	"go.test/repository"
+	util "go.test/utils"
+)
+
+var (
+	ErrCopyUnavailable = repository.ErrCopyUnavailable
+	ErrLoanReturned    = repository.ErrLoanReturned
+	ErrInvalidDueDate  = errors.New("due date must be in the future")
+	ErrForbidden       = errors.New("you don't have the necessary permissions to access this resource")
+	ErrLoanConflict    = repository.ErrLoanConflict
+	ErrRenewalLimit    = errors.New("loan has reached the maximum number of renewals")
+	ErrRenewalOnHold   = errors.New("another patron is waiting for this title")
+	ErrRenewalOverdue  = errors.New("overdue loans must be returned, not renewed")
+	ErrReasonRequired  = errors.New("a reason is required")
+)
+
+type LoanUsecase interface {
+	Checkout(req *model.CheckoutRequest, actor string) (*model.Loan, error)
+	Return(id uint, actor string) (*model.Loan, error)
+	Renew(id uint, req *model.RenewRequest, username, role string) (*model.Loan, error)
+	GetLoan(id uint, username, role string) (*model.Loan, error)
+	GetLoansByUser(userID uint) ([]model.Loan, error)
+	GetLoansByCopy(copyID uint) ([]model.Loan, error)
+	GetMyLoans(username string, activeOnly bool) ([]model.Loan, error)
+}
+
+type loanUsecase struct {
+	loanRepo      repository.LoanRepository
+	copyRepo      repository.CopyRepository
+	userRepo      repository.UserRepository
+	holdRepo      repository.HoldRepository
+	policyUsecase PolicyUsecase
+	fineUsecase   FineUsecase
+	holdPickup    time.Duration
+}
+
+func NewLoanUsecase(loanRepo repository.LoanRepository, copyRepo repository.CopyRepository, userRepo repository.UserRepository, holdRepo repository.HoldRepository, policyUsecase PolicyUsecase, fineUsecase FineUsecase, holdPickup time.Duration) LoanUsecase {
+	return &loanUsecase{loanRepo, copyRepo, userRepo, holdRepo, policyUsecase, fineUsecase, holdPickup}
+}
+
+// Checkout lends a copy to a user. The loan period and the maximum number of
+// active loans come from the loan policy for the borrower and the copy.
+// Users with too much in outstanding fines cannot borrow.
+func (u *loanUsecase) Checkout(req *model.CheckoutRequest, actor string) (*model.Loan, error) {
+	var bookCopy *model.Copy
+	var err error
+	if req.Barcode != "" {
+		bookCopy, err = u.copyRepo.GetByBarcode(req.Barcode)
+	} else {
+		bookCopy, err = u.copyRepo.GetByID(req.CopyID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	user, err := u.userRepo.GetByID(req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.fineUsecase.CanBorrow(user.ID); err != nil {
+		return nil, err
+	}
+	policy, err := u.policyUsecase.EvaluateCheckout(user, bookCopy)
+	if err != nil {
+		return nil, err
+	}
+	active, err := u.loanRepo.GetByUserID(user.ID, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(active) >= policy.MaxLoans {
+		return nil, ErrLoanLimitReached
+	}
+
+	now := time.Now()
+	dueAt := now.Add(policy.LoanPeriod())
+	if req.DueAt != nil {
+		if !req.DueAt.After(now) {
+			return nil, ErrInvalidDueDate
+		}
+		dueAt = *req.DueAt
+	}
+
+	loan := &model.Loan{
+		CopyID:       bookCopy.ID,
+		UserID:       user.ID,
+		CheckedOutAt: now,
+		DueAt:        dueAt,
+	}
+	if err := u.loanRepo.Checkout(loan, actor); err != nil {
+		return nil, err
+	}
+	return loan, nil
+}
+
+// Return closes a loan and charges the overdue fine, if any, in the same
+// transaction, so a loan is never returned without its fine.
+func (u *loanUsecase) Return(id uint, actor string) (*model.Loan, error) {
+	loan, err := u.loanRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if loan.ReturnedAt != nil {
+		return nil, ErrLoanReturned
+	}
+	returnedAt := time.Now()
+	charge, err := u.fineUsecase.OverdueCharge(loan, returnedAt)
+	if err != nil {
+		return nil, err
+	}
+	return u.loanRepo.Return(loan, returnedAt, actor, u.holdPickup, charge)
+}
+
+// Renew extends an active loan by the policy's loan period, counted from
+// now. Borrowers can renew their own loans up to the policy limit, only
+// while the loan is not overdue, nobody is waiting for the title and their
+// fines do not block borrowing. Supervisors can force a renewal past these
+// checks if they give a reason; a forced renewal of an overdue loan lets
+// the fine built up so far go, since fines are charged from the due date.
+func (u *loanUsecase) Renew(id uint, req *model.RenewRequest, username, role string) (*model.Loan, error) {
+	loan, err := u.GetLoan(id, username, role)
+	if err != nil {
+		return nil, err
+	}
+	if loan.ReturnedAt != nil {
+		return nil, ErrLoanReturned
+	}
+	if req.Force {
+		if !util.HasRole(role, "supervisor") {
+			return nil, ErrForbidden
+		}
+		if req.Reason == "" {
+			return nil, ErrReasonRequired
+		}
+	}
+
+	borrower, err := u.userRepo.GetByID(loan.UserID)
+	if err != nil {
+		return nil, err
+	}
+	bookCopy, err := u.copyRepo.GetByID(loan.CopyID)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := u.policyUsecase.EvaluateCheckout(borrower, bookCopy)
+	if err != nil {
+		return nil, err
+	}
+	if !req.Force {
+		if loan.RenewalCount >= policy.MaxRenewals {
+			return nil, ErrRenewalLimit
+		}
+		if loan.DueAt.Before(time.Now()) {
+			return nil, ErrRenewalOverdue
+		}
+		if err := u.fineUsecase.CanBorrow(loan.UserID); err != nil {
+			return nil, err
+		}
+		queue, err := u.holdRepo.GetQueue(bookCopy.BookID)
+		if err != nil {
+			return nil, err
+		}
+		for _, hold := range queue {
+			if hold.Status == model.HoldStatusWaiting {
+				return nil, ErrRenewalOnHold
+			}
+		}
+	}
+
+	dueAt := time.Now().Add(policy.LoanPeriod())
+	if dueAt.Before(loan.DueAt) {
+		dueAt = loan.DueAt
+	}
+	note := ""
+	if req.Force {
+		note = "forced: " + req.Reason
+	}
+	if err := u.loanRepo.Renew(loan, dueAt, username, note); err != nil {
+		return nil, err
+	}
+	return loan, nil
+}
+
+// GetLoan returns a loan to its borrower or to supervisors and above.
+func (u *loanUsecase) GetLoan(id uint, username, role string) (*model.Loan, error) {
+	loan, err := u.loanRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if util.HasRole(role, "supervisor") {
+		return loan, nil
+	}
+	user, err := u.userRepo.GetByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	if loan.UserID != user.ID {
+		return nil, ErrForbidden
+	}
+	return loan, nil
+}
+
+func (u *loanUsecase) GetLoansByUser(userID uint) ([]model.Loan, error) {
+	return u.loanRepo.GetByUserID(userID, false)
+}
+
+func (u *loanUsecase) GetLoansByCopy(copyID uint) ([]model.Loan, error) {
+	return u.loanRepo.GetByCopyID(copyID)
+}
+
+func (u *loanUsecase) GetMyLoans(username string, activeOnly bool) ([]model.Loan, error) {
+	user, err := u.userRepo.GetByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	return u.loanRepo.GetByUserID(user.ID, activeOnly)
+}
@@ -0,0 +1,229 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"go.test/model"
+	util "go.test/utils"
+)
+
+// marcMapping ties a book field to the subfield of the MARC field it is
+// read from and written to. Indicators are those of fields that are added.
+type marcMapping struct {
+	field      string
+	tag        string
+	code       byte
+	indicators [2]byte
+}
+
+var marcMappings = []marcMapping{
+	{"isbn", "020", 'a', [2]byte{' ', ' '}},
+	{"author", "100", 'a', [2]byte{'1', ' '}},
+	{"title", "245", 'a', [2]byte{'1', '0'}},
+	{"published_date", "264", 'c', [2]byte{' ', '1'}},
+}
+
+// find returns the field the book field is read from: the first with the
+// subfield, or else the first with the tag. Dates come from the publication
+// statement in 264, or from 260 in older records.
+func (m *marcMapping) find(record *util.MARCRecord) *util.MARCField {
+	tags := []string{m.tag}
+	if m.tag == "264" {
+		tags = append(tags, "260")
+	}
+	var first *util.MARCField
+	for _, tag := range tags {
+		for i := range record.Fields {
+			field := &record.Fields[i]
+			if field.Tag != tag || (tag == "264" && field.Indicators[1] != '1') {
+				continue
+			}
+			if field.Subfield(m.code) != "" {
+				return field
+			}
+			if first == nil {
+				first = field
+			}
+		}
+	}
+	return first
+}
+
+// read returns the value of the book field in a MARC field, without the
+// punctuation that MARC records put between subfields.
+func (m *marcMapping) read(field *util.MARCField) string {
+	value := field.Subfield(m.code)
+	switch m.field {
+	case "isbn":
+		// 020 $a may carry a qualifier, as in "0441172717 (pbk.)".
+		if tokens := strings.Fields(value); len(tokens) > 0 {
+			if isbn, ok := util.NormalizeISBN(tokens[0]); ok {
+				return isbn
+			}
+			return tokens[0]
+		}
+		return ""
+	case "title":
+		title := trimISBD(value)
+		if subtitle := trimISBD(field.Subfield('b')); subtitle != "" {
+			title += ": " + subtitle
+		}
+		return title
+	case "published_date":
+		value = strings.Trim(trimISBD(value), "[]©℗ ")
+		if date, err := model.ParseLegacyDate(value); err == nil {
+			return date.String()
+		}
+		return value
+	}
+	return trimISBD(value)
+}
+
+// write sets the book field in a record. Fields that already hold the value
+// are left as they are, punctuation and all.
+func (m *marcMapping) write(record *util.MARCRecord, value string) {
+	field := m.find(record)
+	if field == nil {
+		if value == "" {
+			return
+		}
+		added := util.MARCField{Tag: m.tag, Indicators: m.indicators}
+		if m.tag == "245" && record.Field("100") == nil {
+			added.Indicators[0] = '0'
+		}
+		added.SetSubfield(m.code, value)
+		record.AddField(added)
+		return
+	}
+	if m.read(field) == value {
+		return
+	}
+	field.SetSubfield(m.code, value)
+	if m.field == "title" {
+		field.SetSubfield('b', "")
+	}
+	if len(field.Subfields) == 0 {
+		record.RemoveField(field)
+	}
+}
+
+// marcFields reads the book fields of a MARC record.
+func marcFields(record *util.MARCRecord) map[string]string {
+	fields := make(map[string]string, len(marcMappings))
+	for _, mapping := range marcMappings {
+		if field := mapping.find(record); field != nil {
+			if value := mapping.read(field); value != "" {
+				fields[mapping.field] = value
+			}
+		}
+	}
+	return fields
+}
+
+// bookMARC returns the MARC record of a book: the record it was imported
+// from, with the mapped fields brought up to date, or else a new record.
+func bookMARC(book *model.Book) (*util.MARCRecord, error) {
+	record := &util.MARCRecord{Leader: util.MARCLeader}
+	if book.MARC != nil {
+		var err error
+		if record, err = util.UnmarshalMARC(book.MARC); err != nil {
+			return nil, fmt.Errorf("MARC record of book %d: %w", book.ID, err)
+		}
+	} else {
+		record.Fields = []util.MARCField{{Tag: "001", Value: strconv.FormatUint(uint64(book.ID), 10)}}
+	}
+	fields := book.Fields()
+	for _, mapping := range marcMappings {
+		value, _ := fields[mapping.field].(string)
+		mapping.write(record, value)
+	}
+	return record, nil
+}
+
+// trimISBD removes the punctuation that ends a subfield, such as the " /"
+// before a statement of responsibility. The full stop after an initial is
+// kept.
+func trimISBD(value string) string {
+	value = strings.TrimRight(strings.TrimSpace(value), " /:;,=")
+	if rest, ok := strings.CutSuffix(value, "."); ok && !endsWithInitial(rest) {
+		value = strings.TrimRight(rest, " ")
+	}
+	return value
+}
+
+func endsWithInitial(value string) bool {
+	last, size := utf8.DecodeLastRuneInString(value)
+	if !unicode.IsUpper(last) {
+		return false
+	}
+	before, _ := utf8.DecodeLastRuneInString(value[:len(value)-size])
+	return before == utf8.RuneError || before == ' ' || before == '.'
+}
+
+// marcReader reads MARC records for an import. The record last read is
+// kept in ISO 2709 to be saved with its book.
+type marcReader struct {
+	read   func() (*util.MARCRecord, error)
+	record []byte
+}
+
+func (r *marcReader) Read() (map[string]string, error) {
+	r.record = nil
+	record, err := r.read()
+	if errors.Is(err, io.EOF) {
+		return nil, io.EOF
+	}
+	if errors.Is(err, util.ErrMalformedMARC) {
+		return nil, &recordError{err.Error()}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedImport, err)
+	}
+	if r.record, err = util.MarshalMARC(record); err != nil {
+		return nil, &recordError{err.Error()}
+	}
+	return marcFields(record), nil
+}
+
+type marcBookWriter struct {
+	w io.Writer
+}
+
+func (b *marcBookWriter) Write(book *model.Book) error {
+	record, err := bookMARC(book)
+	if err != nil {
+		return err
+	}
+	data, err := util.MarshalMARC(record)
+	if err != nil {
+		return fmt.Errorf("MARC record of book %d: %w", book.ID, err)
+	}
+	_, err = b.w.Write(data)
+	return err
+}
+
+func (b *marcBookWriter) Close() error {
+	return nil
+}
+
+type marcXMLBookWriter struct {
+	w *util.MARCXMLWriter
+}
+
+func (b *marcXMLBookWriter) Write(book *model.Book) error {
+	record, err := bookMARC(book)
+	if err != nil {
+		return err
+	}
+	return b.w.Write(record)
+}
+
+func (b *marcXMLBookWriter) Close() error {
+	return b.w.Close()
+}
@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"testing"
+
+	"go.test/model"
+	util "go.test/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMARCFields(t *testing.T) {
+	record := &util.MARCRecord{Leader: util.MARCLeader, Fields: []util.MARCField{
+		{Tag: "020", Subfields: []util.MARCSubfield{{Code: 'z', Value: "0000000000"}}},
+		{Tag: "020", Subfields: []util.MARCSubfield{{Code: 'a', Value: "0-441-17271-7 (pbk.)"}}},
+		{Tag: "100", Indicators: [2]byte{'1', ' '}, Subfields: []util.MARCSubfield{{Code: 'a', Value: "Le Guin, Ursula K."}}},
+		{Tag: "245", Indicators: [2]byte{'1', '4'}, Subfields: []util.MARCSubfield{{Code: 'a', Value: "The dispossessed :"}, {Code: 'b', Value: "an ambiguous utopia /"}, {Code: 'c', Value: "Ursula K. Le Guin."}}},
+		{Tag: "264", Indicators: [2]byte{' ', '4'}, Subfields: []util.MARCSubfield{{Code: 'c', Value: "©1974"}}},
+		{Tag: "264", Indicators: [2]byte{' ', '1'}, Subfields: []util.MARCSubfield{{Code: 'a', Value: "New York :"}, {Code: 'c', Value: "[1974]"}}},
+	}}
+	assert.Equal(t, map[string]string{
+		"isbn":           "0441172717",
+		"author":         "Le Guin, Ursula K.",
+		"title":          "The dispossessed: an ambiguous utopia",
+		"published_date": "1974",
+	}, marcFields(record))
+
+	record.Fields = record.Fields[:4]
+	record.AddField(util.MARCField{Tag: "260", Subfields: []util.MARCSubfield{{Code: 'c', Value: "1974."}}})
+	assert.Equal(t, "1974", marcFields(record)["published_date"])
+}
+
+func TestBookMARC(t *testing.T) {
+	imported := &util.MARCRecord{Leader: "00000cam a2200000 i 4500", Fields: []util.MARCField{
+		{Tag: "001", Value: "ocm1"},
+		{Tag: "100", Indicators: [2]byte{'1', ' '}, Subfields: []util.MARCSubfield{{Code: 'a', Value: "Herbert, Frank,"}, {Code: 'e', Value: "author."}}},
+		{Tag: "245", Indicators: [2]byte{'1', '0'}, Subfields: []util.MARCSubfield{{Code: 'a', Value: "Dune /"}, {Code: 'c', Value: "Frank Herbert."}}},
+		{Tag: "264", Indicators: [2]byte{' ', '1'}, Subfields: []util.MARCSubfield{{Code: 'b', Value: "Chilton,"}, {Code: 'c', Value: "1965."}}},
+		{Tag: "650", Indicators: [2]byte{' ', '0'}, Subfields: []util.MARCSubfield{{Code: 'a', Value: "Arrakis (Imaginary place)"}}},
+	}}
+	data, _ := util.MarshalMARC(imported)
+	book := &model.Book{ID: 9, Title: "Dune", Author: "Herbert, Frank", PublishedDate: model.PartialDate{Year: 1965}, MARC: data}
+
+	record, err := bookMARC(book)
+	assert.NoError(t, err)
+	assert.Equal(t, imported.Fields, record.Fields, "unchanged books give back their record")
+
+	book.Title = "Dune Messiah"
+	book.ISBN = "0441172695"
+	book.PublishedDate = model.PartialDate{}
+	record, err = bookMARC(book)
+	assert.NoError(t, err)
+	assert.Equal(t, []util.MARCSubfield{{Code: 'a', Value: "0441172695"}}, record.Field("020").Subfields)
+	assert.Equal(t, []util.MARCSubfield{{Code: 'a', Value: "Dune Messiah"}, {Code: 'c', Value: "Frank Herbert."}}, record.Field("245").Subfields)
+	assert.Equal(t, []util.MARCSubfield{{Code: 'b', Value: "Chilton,"}}, record.Field("264").Subfields)
+	assert.Equal(t, "ocm1", record.Field("001").Value)
+	assert.NotNil(t, record.Field("650"))
+
+	record, err = bookMARC(&model.Book{ID: 3, Title: "Emma"})
+	assert.NoError(t, err)
+	assert.Equal(t, util.MARCLeader, record.Leader)
+	assert.Equal(t, "3", record.Field("001").Value)
+	assert.Equal(t, [2]byte{'0', '0'}, record.Field("245").Indicators)
+	assert.Len(t, record.Fields, 2)
+}
+
+func TestTrimISBD(t *testing.T) {
+	assert.Equal(t, "Dune", trimISBD("Dune /"))
+	assert.Equal(t, "Herbert, Frank", trimISBD("Herbert, Frank,"))
+	assert.Equal(t, "Fiction", trimISBD("Fiction."))
+	assert.Equal(t, "Tolkien, J. R. R.", trimISBD("Tolkien, J. R. R."))
+}
@@ -12,17 +12,17 @@ type BookUsecase struct {
 	mock.Mock
 }
 
-// CreateBook provides a mock function with given fields: book
-func (_m *BookUsecase) CreateBook(book *model.Book) error {
-	ret := _m.Called(book)
+// CreateBook provides a mock function with given fields: book, actor
+func (_m *BookUsecase) CreateBook(book *model.Book, actor string) error {
+	ret := _m.Called(book, actor)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateBook")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*model.Book) error); ok {
-		r0 = rf(book)
+	if rf, ok := ret.Get(0).(func(*model.Book, string) error); ok {
+		r0 = rf(book, actor)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -30,17 +30,17 @@ func (_m *BookUsecase) CreateBook(book *model.Book) error {
 	return r0
 }
 
-// DeleteBook provides a mock function with given fields: id
-func (_m *BookUsecase) DeleteBook(id uint) error {
-	ret := _m.Called(id)
+// DeleteBook provides a mock function with given fields: id, version, actor
+func (_m *BookUsecase) DeleteBook(id uint, version int, actor string) error {
+	ret := _m.Called(id, version, actor)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteBook")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(uint) error); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(uint, int, string) error); ok {
+		r0 = rf(id, version, actor)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -48,9 +48,9 @@ func (_m *BookUsecase) DeleteBook(id uint) error {
 	return r0
 }
 
-// GetAllBooks provides a mock function with given fields:
-func (_m *BookUsecase) GetAllBooks() ([]model.Book, error) {
-	ret := _m.Called()
+// GetAllBooks provides a mock function with given fields: filter
+func (_m *BookUsecase) GetAllBooks(filter *model.BookFilter) ([]model.Book, error) {
+	ret := _m.Called(filter)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetAllBooks")
@@ -58,19 +58,19 @@ func (_m *BookUsecase) GetAllBooks() ([]model.Book, error) {
 
 	var r0 []model.Book
 	var r1 error
-	if rf, ok := ret.Get(0).(func() ([]model.Book, error)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(*model.BookFilter) ([]model.Book, error)); ok {
+		return rf(filter)
 	}
-	if rf, ok := ret.Get(0).(func() []model.Book); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(*model.BookFilter) []model.Book); ok {
+		r0 = rf(filter)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]model.Book)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(*model.BookFilter) error); ok {
+		r1 = rf(filter)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -78,9 +78,9 @@ func (_m *BookUsecase) GetAllBooks() ([]model.Book, error) {
 	return r0, r1
 }
 
-// GetBookByID provides a mock function with given fields: id
-func (_m *BookUsecase) GetBookByID(id uint) (*model.Book, error) {
-	ret := _m.Called(id)
+// GetBookByID provides a mock function with given fields: id, username
+func (_m *BookUsecase) GetBookByID(id uint, username string) (*model.Book, error) {
+	ret := _m.Called(id, username)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetBookByID")
@@ -88,14 +88,44 @@ func (_m *BookUsecase) GetBookByID(id uint) (*model.Book, error) {
 
 	var r0 *model.Book
 	var r1 error
-	if rf, ok := ret.Get(0).(func(uint) (*model.Book, error)); ok {
+	if rf, ok := ret.Get(0).(func(uint, string) (*model.Book, error)); ok {
+		return rf(id, username)
+	}
+	if rf, ok := ret.Get(0).(func(uint, string) *model.Book); ok {
+		r0 = rf(id, username)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Book)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, string) error); ok {
+		r1 = rf(id, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetHistory provides a mock function with given fields: id
+func (_m *BookUsecase) GetHistory(id uint) ([]model.BookRevision, error) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetHistory")
+	}
+
+	var r0 []model.BookRevision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) ([]model.BookRevision, error)); ok {
 		return rf(id)
 	}
-	if rf, ok := ret.Get(0).(func(uint) *model.Book); ok {
+	if rf, ok := ret.Get(0).(func(uint) []model.BookRevision); ok {
 		r0 = rf(id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*model.Book)
+			r0 = ret.Get(0).([]model.BookRevision)
 		}
 	}
 
@@ -108,17 +138,125 @@ func (_m *BookUsecase) GetBookByID(id uint) (*model.Book, error) {
 	return r0, r1
 }
 
-// UpdateBook provides a mock function with given fields: book
-func (_m *BookUsecase) UpdateBook(book *model.Book) error {
-	ret := _m.Called(book)
+// MigrateISBNKeys provides a mock function with given fields:
+func (_m *BookUsecase) MigrateISBNKeys() error {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for MigrateISBNKeys")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MigratePublishedDates provides a mock function with given fields:
+func (_m *BookUsecase) MigratePublishedDates() ([]model.UnparsedDate, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for MigratePublishedDates")
+	}
+
+	var r0 []model.UnparsedDate
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]model.UnparsedDate, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []model.UnparsedDate); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.UnparsedDate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PatchBook provides a mock function with given fields: id, version, mediaType, patch, actor
+func (_m *BookUsecase) PatchBook(id uint, version int, mediaType string, patch []byte, actor string) (*model.Book, error) {
+	ret := _m.Called(id, version, mediaType, patch, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PatchBook")
+	}
+
+	var r0 *model.Book
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, int, string, []byte, string) (*model.Book, error)); ok {
+		return rf(id, version, mediaType, patch, actor)
+	}
+	if rf, ok := ret.Get(0).(func(uint, int, string, []byte, string) *model.Book); ok {
+		r0 = rf(id, version, mediaType, patch, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Book)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, int, string, []byte, string) error); ok {
+		r1 = rf(id, version, mediaType, patch, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevertBook provides a mock function with given fields: id, revision, actor
+func (_m *BookUsecase) RevertBook(id uint, revision int, actor string) (*model.Book, error) {
+	ret := _m.Called(id, revision, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevertBook")
+	}
+
+	var r0 *model.Book
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, int, string) (*model.Book, error)); ok {
+		return rf(id, revision, actor)
+	}
+	if rf, ok := ret.Get(0).(func(uint, int, string) *model.Book); ok {
+		r0 = rf(id, revision, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Book)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, int, string) error); ok {
+		r1 = rf(id, revision, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateBook provides a mock function with given fields: book, actor
+func (_m *BookUsecase) UpdateBook(book *model.Book, actor string) error {
+	ret := _m.Called(book, actor)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateBook")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*model.Book) error); ok {
-		r0 = rf(book)
+	if rf, ok := ret.Get(0).(func(*model.Book, string) error); ok {
+		r0 = rf(book, actor)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -12,17 +12,17 @@ type UserUsecase struct {
 	mock.Mock
 }
 
-// DeleteUser provides a mock function with given fields: id
-func (_m *UserUsecase) DeleteUser(id uint) error {
-	ret := _m.Called(id)
+// DeleteUser provides a mock function with given fields: id, version
+func (_m *UserUsecase) DeleteUser(id uint, version int) error {
+	ret := _m.Called(id, version)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteUser")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(uint) error); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(uint, int) error); ok {
+		r0 = rf(id, version)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -60,6 +60,36 @@ func (_m *UserUsecase) GetAllUsers() ([]model.User, error) {
 	return r0, r1
 }
 
+// GetHistory provides a mock function with given fields: id
+func (_m *UserUsecase) GetHistory(id uint) ([]model.UserRevision, error) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetHistory")
+	}
+
+	var r0 []model.UserRevision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) ([]model.UserRevision, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(uint) []model.UserRevision); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.UserRevision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetUserByID provides a mock function with given fields: id
 func (_m *UserUsecase) GetUserByID(id uint) (*model.User, error) {
 	ret := _m.Called(id)
@@ -118,6 +148,36 @@ func (_m *UserUsecase) LoginUser(username string, password string) (string, erro
 	return r0, r1
 }
 
+// PatchUser provides a mock function with given fields: id, version, mediaType, patch, actor
+func (_m *UserUsecase) PatchUser(id uint, version int, mediaType string, patch []byte, actor string) (*model.User, error) {
+	ret := _m.Called(id, version, mediaType, patch, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PatchUser")
+	}
+
+	var r0 *model.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, int, string, []byte, string) (*model.User, error)); ok {
+		return rf(id, version, mediaType, patch, actor)
+	}
+	if rf, ok := ret.Get(0).(func(uint, int, string, []byte, string) *model.User); ok {
+		r0 = rf(id, version, mediaType, patch, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, int, string, []byte, string) error); ok {
+		r1 = rf(id, version, mediaType, patch, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // RegisterUser provides a mock function with given fields: user
 func (_m *UserUsecase) RegisterUser(user *model.User) error {
 	ret := _m.Called(user)
@@ -136,17 +196,17 @@ func (_m *UserUsecase) RegisterUser(user *model.User) error {
 	return r0
 }
 
-// UpdateUser provides a mock function with given fields: user
-func (_m *UserUsecase) UpdateUser(user *model.User) error {
-	ret := _m.Called(user)
+// UpdateUser provides a mock function with given fields: user, actor
+func (_m *UserUsecase) UpdateUser(user *model.User, actor string) error {
+	ret := _m.Called(user, actor)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateUser")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*model.User) error); ok {
-		r0 = rf(user)
+	if rf, ok := ret.Get(0).(func(*model.User, string) error); ok {
+		r0 = rf(user, actor)
 	} else {
 		r0 = ret.Error(0)
 	}
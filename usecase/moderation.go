@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"errors"
+
+	"go.test/model"
+	"go.test/repository"
+)
+
+var (
+	ErrAlreadyReported   = errors.New("you have already reported this review")
+	ErrInvalidModeration = errors.New("action must be one of approve, hide or ban")
+	ErrReportOwnReview   = errors.New("you cannot report your own review")
+)
+
+type ModerationUsecase interface {
+	Report(reviewID uint, username, reason string) (*model.ReviewReport, error)
+	GetQueue() ([]model.ModerationItem, error)
+	Decide(reviewID uint, decision *model.ModerationDecision, actor string) (*model.Review, error)
+	GetLogs(reviewID uint) ([]model.ModerationLog, error)
+}
+
+type moderationUsecase struct {
+	moderationRepo repository.ModerationRepository
+	reviewRepo     repository.ReviewRepository
+	userRepo       repository.UserRepository
+}
+
+func NewModerationUsecase(moderationRepo repository.ModerationRepository, reviewRepo repository.ReviewRepository, userRepo repository.UserRepository) ModerationUsecase {
+	return &moderationUsecase{moderationRepo, reviewRepo, userRepo}
+}
+
+// Report flags a review for the moderation queue. The review stays
+// published until a moderator decides on it.
+func (u *moderationUsecase) Report(reviewID uint, username, reason string) (*model.ReviewReport, error) {
+	if reason == "" {
+		return nil, ErrReasonRequired
+	}
+	review, err := u.reviewRepo.GetByID(reviewID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := u.userRepo.GetByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	if review.UserID == user.ID {
+		return nil, ErrReportOwnReview
+	}
+	reports, err := u.moderationRepo.GetReports(reviewID, false)
+	if err != nil {
+		return nil, err
+	}
+	for _, report := range reports {
+		if report.UserID == user.ID {
+			return nil, ErrAlreadyReported
+		}
+	}
+
+	report := &model.ReviewReport{
+		ReviewID: reviewID,
+		UserID:   user.ID,
+		Reason:   reason,
+	}
+	if err := u.moderationRepo.CreateReport(report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (u *moderationUsecase) GetQueue() ([]model.ModerationItem, error) {
+	reviews, err := u.moderationRepo.GetQueue()
+	if err != nil {
+		return nil, err
+	}
+	items := make([]model.ModerationItem, len(reviews))
+	for i := range reviews {
+		reports, err := u.moderationRepo.GetReports(reviews[i].ID, true)
+		if err != nil {
+			return nil, err
+		}
+		items[i] = model.ModerationItem{Review: reviews[i], Reports: reports}
+	}
+	return items, nil
+}
+
+// Decide approves or hides a review, or hides it and bans its author from
+// reviewing. Any open reports on the review are resolved and the decision is
+// logged.
+func (u *moderationUsecase) Decide(reviewID uint, decision *model.ModerationDecision, actor string) (*model.Review, error) {
+	review, err := u.reviewRepo.GetByID(reviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ban *model.ReviewBan
+	switch decision.Action {
+	case model.ModerationActionApprove:
+		review.Status = model.ReviewStatusPublished
+	case model.ModerationActionHide:
+		review.Status = model.ReviewStatusHidden
+	case model.ModerationActionBan:
+		if decision.Reason == "" {
+			return nil, ErrReasonRequired
+		}
+		review.Status = model.ReviewStatusHidden
+		ban = &model.ReviewBan{
+			UserID: review.UserID,
+			Actor:  actor,
+			Reason: decision.Reason,
+		}
+	default:
+		return nil, ErrInvalidModeration
+	}
+
+	entry := &model.ModerationLog{
+		ReviewID: review.ID,
+		Action:   decision.Action,
+		Actor:    actor,
+		Reason:   decision.Reason,
+	}
+	if err := u.moderationRepo.ApplyDecision(review, entry, ban); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+func (u *moderationUsecase) GetLogs(reviewID uint) ([]model.ModerationLog, error) {
+	return u.moderationRepo.GetLogs(reviewID)
+}
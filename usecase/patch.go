@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	util "go.test/utils"
+)
+
+var (
+	ErrMalformedPatch   = util.ErrMalformedPatch
+	ErrUnsupportedPatch = util.ErrUnsupportedPatch
+	ErrPatchConflict    = util.ErrPatchConflict
+	ErrInvalidPatch     = errors.New("the patched record is not valid")
+)
+
+// patchFields applies a patch to the editable fields of a record, as
+// returned by its Fields method. The result must be an object with the same
+// fields, all strings; fields the patch removed come back empty.
+func patchFields(mediaType string, fields map[string]interface{}, patch []byte) (map[string]interface{}, error) {
+	doc, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	patched, err := util.ApplyPatch(mediaType, doc, patch)
+	if err != nil {
+		return nil, err
+	}
+	var result map[string]interface{}
+	if err := json.Unmarshal(patched, &result); err != nil {
+		return nil, fmt.Errorf("%w: the result is not an object", ErrInvalidPatch)
+	}
+	for name, value := range result {
+		if _, ok := fields[name]; !ok {
+			return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidPatch, name)
+		}
+		if _, ok := value.(string); !ok {
+			return nil, fmt.Errorf("%w: %s must be a string", ErrInvalidPatch, name)
+		}
+	}
+	for name := range fields {
+		if _, ok := result[name]; !ok {
+			result[name] = ""
+		}
+	}
+	return result, nil
+}
+
+// patchNote describes a patch in the change history.
+func patchNote(mediaType string) string {
+	return "patched with " + mediaType
+}
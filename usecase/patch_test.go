@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"testing"
+
+	util "go.test/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPatchFields(t *testing.T) {
+	fields := map[string]interface{}{"title": "Dune", "author": "Herbert", "isbn": "1"}
+
+	patched, err := patchFields(util.MergePatchType, fields, []byte(`{"author":"Frank Herbert","isbn":null}`))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"title": "Dune", "author": "Frank Herbert", "isbn": ""}, patched)
+
+	patched, err = patchFields(util.JSONPatchType, fields, []byte(`[
+		{"op":"test","path":"/title","value":"Dune"},
+		{"op":"copy","from":"/title","path":"/author"}
+	]`))
+	assert.NoError(t, err)
+	assert.Equal(t, "Dune", patched["author"])
+
+	_, err = patchFields(util.MergePatchType, fields, []byte(`{"id":7}`))
+	assert.ErrorIs(t, err, ErrInvalidPatch)
+
+	_, err = patchFields(util.MergePatchType, fields, []byte(`{"title":42}`))
+	assert.ErrorIs(t, err, ErrInvalidPatch)
+
+	_, err = patchFields(util.MergePatchType, fields, []byte(`["not","an","object"]`))
+	assert.ErrorIs(t, err, ErrInvalidPatch)
+
+	_, err = patchFields(util.JSONPatchType, fields, []byte(`[{"op":"test","path":"/title","value":"Emma"}]`))
+	assert.ErrorIs(t, err, ErrPatchConflict)
+
+	// The fields themselves are left alone.
+	assert.Equal(t, "Herbert", fields["author"])
+}
@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+var ErrPaymentDeclined = errors.New("payment was declined")
+
+// PaymentProvider takes online payments. Charge returns the provider's
+// reference for the payment.
+type PaymentProvider interface {
+	Charge(userID uint, amount int64, token string) (string, error)
+}
+
+// localPaymentProvider is a stand-in for a real payment service. It accepts
+// any token except the ones starting with "declined", which lets clients
+// exercise the failure path.
+type localPaymentProvider struct {
+	mu   sync.Mutex
+	next int
+}
+
+func NewLocalPaymentProvider() PaymentProvider {
+	return &localPaymentProvider{}
+}
+
+func (p *localPaymentProvider) Charge(userID uint, amount int64, token string) (string, error) {
+	if token == "" || strings.HasPrefix(token, "declined") {
+		return "", ErrPaymentDeclined
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.next++
+	return fmt.Sprintf("local-%d-%d", userID, p.next), nil
+}
@@ -0,0 +1,201 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.test/model"
+	"go.test/repository"
+	util "go.test/utils"
+)
+
+var (
+	ErrInvalidPolicy    = errors.New("loan period must be positive, limits cannot be negative and the role must be a known one")
+	ErrLoanLimitReached = errors.New("borrower has reached the maximum number of active loans")
+)
+
+type PolicyUsecase interface {
+	GetPolicies() ([]model.LoanPolicy, error)
+	CreatePolicy(policy *model.LoanPolicy) error
+	UpdatePolicy(policy *model.LoanPolicy) error
+	DeletePolicy(id uint) error
+	Evaluate(role, materialType, branch string) (*model.PolicyDecision, error)
+	EvaluateCheckout(user *model.User, bookCopy *model.Copy) (*model.PolicyDecision, error)
+	DryRun(userID, copyID uint) (*model.PolicyDryRun, error)
+}
+
+type policyUsecase struct {
+	policyRepo    repository.PolicyRepository
+	userRepo      repository.UserRepository
+	copyRepo      repository.CopyRepository
+	bookRepo      repository.BookRepository
+	loanRepo      repository.LoanRepository
+	configRules   []model.LoanPolicy
+	defaultPolicy model.LoanPolicy
+}
+
+// NewPolicyUsecase builds the loan policy engine. Rules are looked up in the
+// database first, then in configRules, and defaultPolicy applies when
+// nothing matches.
+func NewPolicyUsecase(policyRepo repository.PolicyRepository, userRepo repository.UserRepository, copyRepo repository.CopyRepository, bookRepo repository.BookRepository, loanRepo repository.LoanRepository, configRules []model.LoanPolicy, defaultPolicy model.LoanPolicy) PolicyUsecase {
+	return &policyUsecase{policyRepo, userRepo, copyRepo, bookRepo, loanRepo, configRules, defaultPolicy}
+}
+
+func (u *policyUsecase) GetPolicies() ([]model.LoanPolicy, error) {
+	return u.policyRepo.GetAll()
+}
+
+func (u *policyUsecase) CreatePolicy(policy *model.LoanPolicy) error {
+	if !validPolicy(policy) {
+		return ErrInvalidPolicy
+	}
+	return u.policyRepo.Create(policy)
+}
+
+func (u *policyUsecase) UpdatePolicy(policy *model.LoanPolicy) error {
+	if !validPolicy(policy) {
+		return ErrInvalidPolicy
+	}
+	if _, err := u.policyRepo.GetByID(policy.ID); err != nil {
+		return err
+	}
+	return u.policyRepo.Update(policy)
+}
+
+func (u *policyUsecase) DeletePolicy(id uint) error {
+	return u.policyRepo.Delete(id)
+}
+
+func (u *policyUsecase) Evaluate(role, materialType, branch string) (*model.PolicyDecision, error) {
+	if materialType == "" {
+		materialType = model.MaterialTypeBook
+	}
+	rules, err := u.policyRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	source := model.PolicySourceDatabase
+	rule := selectPolicy(rules, role, materialType, branch)
+	if rule == nil {
+		source = model.PolicySourceConfig
+		rule = selectPolicy(u.configRules, role, materialType, branch)
+	}
+	if rule == nil {
+		source = model.PolicySourceDefault
+		rule = &u.defaultPolicy
+	}
+
+	decision := &model.PolicyDecision{
+		Role:           role,
+		MaterialType:   materialType,
+		Branch:         branch,
+		LoanPeriodDays: rule.LoanPeriodDays,
+		MaxRenewals:    rule.MaxRenewals,
+		MaxLoans:       rule.MaxLoans,
+		GraceDays:      rule.GraceDays,
+		DailyFine:      rule.DailyFine,
+		MaxFine:        rule.MaxFine,
+		Source:         source,
+	}
+	if source == model.PolicySourceDatabase {
+		decision.RuleID = rule.ID
+	}
+	return decision, nil
+}
+
+// EvaluateCheckout evaluates the policy for a user borrowing a copy, using
+// the material type of the copy's book and the copy's branch.
+func (u *policyUsecase) EvaluateCheckout(user *model.User, bookCopy *model.Copy) (*model.PolicyDecision, error) {
+	book, err := u.bookRepo.GetByID(bookCopy.BookID)
+	if err != nil {
+		return nil, err
+	}
+	return u.Evaluate(user.Role, book.MaterialType, bookCopy.Branch)
+}
+
+func (u *policyUsecase) DryRun(userID, copyID uint) (*model.PolicyDryRun, error) {
+	user, err := u.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	bookCopy, err := u.copyRepo.GetByID(copyID)
+	if err != nil {
+		return nil, err
+	}
+	decision, err := u.EvaluateCheckout(user, bookCopy)
+	if err != nil {
+		return nil, err
+	}
+	active, err := u.loanRepo.GetByUserID(user.ID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.PolicyDryRun{
+		UserID:      user.ID,
+		CopyID:      bookCopy.ID,
+		Policy:      decision,
+		ActiveLoans: len(active),
+		DueAt:       time.Now().Add(decision.LoanPeriod()),
+		Allowed:     true,
+	}
+	switch {
+	case len(active) >= decision.MaxLoans:
+		result.Allowed = false
+		result.Reason = ErrLoanLimitReached.Error()
+	case bookCopy.Status != model.CopyStatusAvailable:
+		result.Allowed = false
+		result.Reason = ErrCopyUnavailable.Error()
+	}
+	return result, nil
+}
+
+// selectPolicy returns the most specific rule that matches, or nil. A rule
+// naming the role outranks one naming the material type, which outranks one
+// naming the branch. Among equally specific rules the first one wins.
+func selectPolicy(rules []model.LoanPolicy, role, materialType, branch string) *model.LoanPolicy {
+	var best *model.LoanPolicy
+	bestScore := -1
+	for i := range rules {
+		rule := &rules[i]
+		if !rule.Matches(role, materialType, branch) {
+			continue
+		}
+		score := 0
+		if rule.Role != "" {
+			score += 4
+		}
+		if rule.MaterialType != "" {
+			score += 2
+		}
+		if rule.Branch != "" {
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = rule, score
+		}
+	}
+	return best
+}
+
+// ValidatePolicies checks the default policy and the rule table taken from
+// the configuration the way rules created through the API are checked.
+func ValidatePolicies(defaultPolicy model.LoanPolicy, rules []model.LoanPolicy) error {
+	if !validPolicy(&defaultPolicy) || defaultPolicy.Role != "" || defaultPolicy.MaterialType != "" || defaultPolicy.Branch != "" {
+		return fmt.Errorf("default policy: %w", ErrInvalidPolicy)
+	}
+	for i := range rules {
+		if !validPolicy(&rules[i]) {
+			return fmt.Errorf("rule %d: %w", i+1, ErrInvalidPolicy)
+		}
+	}
+	return nil
+}
+
+func validPolicy(policy *model.LoanPolicy) bool {
+	return policy.LoanPeriodDays > 0 && policy.MaxRenewals >= 0 && policy.MaxLoans >= 0 && policy.GraceDays >= 0 &&
+		policy.DailyFine >= 0 && policy.MaxFine >= 0 &&
+		(policy.Role == "" || util.IsRole(policy.Role)) && len(policy.MaterialType) <= 32 && len(policy.Branch) <= 64
+}
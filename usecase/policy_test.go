@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"testing"
+
+	"go.test/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectPolicy(t *testing.T) {
+	rules := []model.LoanPolicy{
+		{ID: 1, LoanPeriodDays: 14},
+		{ID: 2, MaterialType: "dvd", LoanPeriodDays: 7},
+		{ID: 3, Role: "supervisor", LoanPeriodDays: 28},
+		{ID: 4, Role: "user", MaterialType: "dvd", Branch: "east", LoanPeriodDays: 3},
+		{ID: 5, Branch: "east", LoanPeriodDays: 10},
+	}
+
+	tests := []struct {
+		name         string
+		role         string
+		materialType string
+		branch       string
+		expectedID   uint
+	}{
+		{"Catch-all rule", "user", "book", "main", 1},
+		{"Material type outranks catch-all", "user", "dvd", "main", 2},
+		{"Role outranks material type", "supervisor", "dvd", "main", 3},
+		{"Fully specific rule wins", "user", "dvd", "east", 4},
+		{"Material type outranks branch", "manager", "dvd", "east", 2},
+		{"Branch outranks catch-all", "user", "book", "east", 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := selectPolicy(rules, tt.role, tt.materialType, tt.branch)
+			if assert.NotNil(t, rule) {
+				assert.Equal(t, tt.expectedID, rule.ID)
+			}
+		})
+	}
+}
+
+func TestSelectPolicyNoMatch(t *testing.T) {
+	rules := []model.LoanPolicy{{ID: 1, Role: "supervisor", LoanPeriodDays: 28}}
+
+	assert.Nil(t, selectPolicy(rules, "user", "book", ""))
+	assert.Nil(t, selectPolicy(nil, "user", "book", ""))
+}
+
+func TestValidatePolicies(t *testing.T) {
+	valid := model.LoanPolicy{LoanPeriodDays: 14, MaxRenewals: 2}
+
+	assert.NoError(t, ValidatePolicies(valid, []model.LoanPolicy{{Role: "supervisor", MaterialType: "dvd", LoanPeriodDays: 7}}))
+	assert.ErrorIs(t, ValidatePolicies(model.LoanPolicy{}, nil), ErrInvalidPolicy)
+	assert.ErrorIs(t, ValidatePolicies(valid, []model.LoanPolicy{{LoanPeriodDays: 7, MaxRenewals: -1}}), ErrInvalidPolicy)
+	assert.ErrorIs(t, ValidatePolicies(valid, []model.LoanPolicy{{Role: "librarian", LoanPeriodDays: 7}}), ErrInvalidPolicy)
+}
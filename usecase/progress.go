@@ -0,0 +1,297 @@
+package usecase
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"go.test/model"
+	"go.test/repository"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidProgress = errors.New("progress must be within the book and cannot finish before it started")
+	ErrInvalidGoal     = errors.New("a goal needs a year and a target of at least one book")
+)
+
+// popularBooksLimit caps how many books the reading report lists.
+const popularBooksLimit = 10
+
+type ProgressUsecase interface {
+	RecordProgress(bookID uint, req *model.ProgressRequest, username string) (*model.ReadingProgress, error)
+	GetMyProgress(username string, bookID uint) ([]model.ReadingProgress, error)
+	SetGoal(year, target int, username string) (*model.YearSummary, error)
+	GetMySummaries(username string, year int) ([]model.YearSummary, error)
+	GetReport(year int) (*model.ReadingReport, error)
+}
+
+type progressUsecase struct {
+	progressRepo repository.ProgressRepository
+	bookRepo     repository.BookRepository
+	userRepo     repository.UserRepository
+	minReaders   int
+}
+
+func NewProgressUsecase(progressRepo repository.ProgressRepository, bookRepo repository.BookRepository, userRepo repository.UserRepository, minReaders int) ProgressUsecase {
+	return &progressUsecase{progressRepo, bookRepo, userRepo, minReaders}
+}
+
+// RecordProgress updates the current user's read-through of a book.
+func (u *progressUsecase) RecordProgress(bookID uint, req *model.ProgressRequest, username string) (*model.ReadingProgress, error) {
+	if _, err := u.bookRepo.GetByID(bookID); err != nil {
+		return nil, err
+	}
+	user, err := u.userRepo.GetByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	return u.progressRepo.Record(user.ID, bookID, func(progress *model.ReadingProgress) error {
+		return applyProgress(progress, req, now)
+	})
+}
+
+func (u *progressUsecase) GetMyProgress(username string, bookID uint) ([]model.ReadingProgress, error) {
+	user, err := u.userRepo.GetByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	return u.progressRepo.GetByUserID(user.ID, bookID)
+}
+
+func (u *progressUsecase) SetGoal(year, target int, username string) (*model.YearSummary, error) {
+	if year < 1 || target < 1 {
+		return nil, ErrInvalidGoal
+	}
+	user, err := u.userRepo.GetByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.progressRepo.SaveGoal(&model.ReadingGoal{UserID: user.ID, Year: year, Target: target}); err != nil {
+		return nil, err
+	}
+	summaries, err := u.summaries(user.ID, year)
+	if err != nil {
+		return nil, err
+	}
+	return &summaries[0], nil
+}
+
+// GetMySummaries returns the current user's reading per year, newest first,
+// for every year with a goal or a finished book. A non-zero year returns
+// that year only.
+func (u *progressUsecase) GetMySummaries(username string, year int) ([]model.YearSummary, error) {
+	user, err := u.userRepo.GetByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	return u.summaries(user.ID, year)
+}
+
+func (u *progressUsecase) summaries(userID uint, year int) ([]model.YearSummary, error) {
+	progress, err := u.progressRepo.GetByUserID(userID, 0)
+	if err != nil {
+		return nil, err
+	}
+	goals, err := u.progressRepo.GetGoals(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	byYear := make(map[int]*model.YearSummary)
+	summary := func(y int) *model.YearSummary {
+		if byYear[y] == nil {
+			byYear[y] = &model.YearSummary{Year: y, Books: []model.ReadingProgress{}}
+		}
+		return byYear[y]
+	}
+	if year != 0 {
+		summary(year)
+	}
+	for _, goal := range goals {
+		if year == 0 || goal.Year == year {
+			summary(goal.Year).Target = goal.Target
+		}
+	}
+	for _, p := range progress {
+		if p.FinishedAt == nil {
+			continue
+		}
+		y := p.FinishedAt.UTC().Year()
+		if year != 0 && y != year {
+			continue
+		}
+		p.Updates = nil
+		s := summary(y)
+		s.Books = append(s.Books, p)
+		s.Finished++
+	}
+
+	now := time.Now().UTC()
+	summaries := make([]model.YearSummary, 0, len(byYear))
+	for _, s := range byYear {
+		measureGoal(s, now)
+		summaries = append(summaries, *s)
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Year > summaries[j].Year })
+	return summaries, nil
+}
+
+// GetReport summarizes everyone's reading in a year without identifying
+// anyone.
+func (u *progressUsecase) GetReport(year int) (*model.ReadingReport, error) {
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(1, 0, 0)
+	progress, err := u.progressRepo.GetBetween(from, to)
+	if err != nil {
+		return nil, err
+	}
+	goals, err := u.progressRepo.GetGoalsByYear(year)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &model.ReadingReport{Year: year, MinReaders: u.minReaders, PopularBooks: []model.BookReadCount{}}
+	readers := make(map[uint]bool)
+	finishedBy := make(map[uint]int)
+	bookReaders := make(map[uint]map[uint]bool)
+	var days float64
+	for _, p := range progress {
+		readers[p.UserID] = true
+		if !p.StartedAt.Before(from) && p.StartedAt.Before(to) {
+			report.Started++
+		}
+		if p.FinishedAt == nil || p.FinishedAt.Before(from) || !p.FinishedAt.Before(to) {
+			continue
+		}
+		report.Finished++
+		report.FinishedByMonth[p.FinishedAt.UTC().Month()-1]++
+		days += p.FinishedAt.Sub(p.StartedAt).Hours() / 24
+		finishedBy[p.UserID]++
+		if bookReaders[p.BookID] == nil {
+			bookReaders[p.BookID] = make(map[uint]bool)
+		}
+		bookReaders[p.BookID][p.UserID] = true
+	}
+	report.Readers = len(readers)
+	if report.Finished > 0 {
+		report.AverageDaysToFinish = round2(days / float64(report.Finished))
+	}
+
+	var targets int
+	for _, goal := range goals {
+		report.GoalsSet++
+		targets += goal.Target
+		if finishedBy[goal.UserID] >= goal.Target {
+			report.GoalsMet++
+		}
+	}
+	if report.GoalsSet > 0 {
+		report.AverageTarget = round2(float64(targets) / float64(report.GoalsSet))
+	}
+
+	for bookID, users := range bookReaders {
+		if len(users) >= u.minReaders {
+			report.PopularBooks = append(report.PopularBooks, model.BookReadCount{BookID: bookID, Readers: len(users)})
+		}
+	}
+	sort.Slice(report.PopularBooks, func(i, j int) bool {
+		a, b := report.PopularBooks[i], report.PopularBooks[j]
+		if a.Readers != b.Readers {
+			return a.Readers > b.Readers
+		}
+		return a.BookID < b.BookID
+	})
+	if len(report.PopularBooks) > popularBooksLimit {
+		report.PopularBooks = report.PopularBooks[:popularBooksLimit]
+	}
+	for i := range report.PopularBooks {
+		book, err := u.bookRepo.GetByID(report.PopularBooks[i].BookID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		if book != nil {
+			report.PopularBooks[i].Title = book.Title
+		}
+	}
+	return report, nil
+}
+
+// applyProgress moves a read-through to where the request says the reader
+// is. Pages and percentages are kept in step when the page count is known.
+func applyProgress(progress *model.ReadingProgress, req *model.ProgressRequest, now time.Time) error {
+	if req.StartedAt != nil {
+		progress.StartedAt = *req.StartedAt
+	} else if progress.ID == 0 {
+		progress.StartedAt = now
+	}
+	if req.TotalPages != nil {
+		if *req.TotalPages < 0 {
+			return ErrInvalidProgress
+		}
+		progress.TotalPages = *req.TotalPages
+	}
+	if req.Page != nil {
+		if *req.Page < 0 || (progress.TotalPages > 0 && *req.Page > progress.TotalPages) {
+			return ErrInvalidProgress
+		}
+		progress.Page = *req.Page
+		if progress.TotalPages > 0 {
+			progress.Percent = round2(100 * float64(progress.Page) / float64(progress.TotalPages))
+		}
+	}
+	if req.Percent != nil {
+		if *req.Percent < 0 || *req.Percent > 100 {
+			return ErrInvalidProgress
+		}
+		progress.Percent = *req.Percent
+		if req.Page == nil && progress.TotalPages > 0 {
+			progress.Page = int(*req.Percent * float64(progress.TotalPages) / 100)
+		}
+	}
+
+	if req.Finished || req.FinishedAt != nil || progress.Percent >= 100 {
+		finishedAt := now
+		if req.FinishedAt != nil {
+			finishedAt = *req.FinishedAt
+		}
+		if finishedAt.Before(progress.StartedAt) {
+			return ErrInvalidProgress
+		}
+		progress.Percent = 100
+		if progress.TotalPages > 0 {
+			progress.Page = progress.TotalPages
+		}
+		progress.FinishedAt = &finishedAt
+	}
+	return nil
+}
+
+// measureGoal fills in how far a year's reading is towards its goal. During
+// the year a reader is on track when they have finished at least the share
+// of the target that matches the share of the year gone by.
+func measureGoal(summary *model.YearSummary, now time.Time) {
+	if summary.Target == 0 {
+		return
+	}
+	summary.Remaining = max(summary.Target-summary.Finished, 0)
+	summary.Percent = round2(100 * float64(summary.Finished) / float64(summary.Target))
+	switch {
+	case summary.Year < now.Year():
+		summary.OnTrack = summary.Finished >= summary.Target
+	case summary.Year == now.Year():
+		start := time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+		elapsed := now.Sub(start).Hours() / (start.AddDate(1, 0, 0).Sub(start).Hours())
+		summary.Expected = int(float64(summary.Target) * elapsed)
+		summary.OnTrack = summary.Finished >= summary.Expected
+	default:
+		summary.OnTrack = true
+	}
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"go.test/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyProgress(t *testing.T) {
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	page := func(v int) *int { return &v }
+
+	progress := &model.ReadingProgress{}
+	assert.NoError(t, applyProgress(progress, &model.ProgressRequest{Page: page(50), TotalPages: page(200)}, now))
+	assert.Equal(t, now, progress.StartedAt)
+	assert.Equal(t, 25.0, progress.Percent)
+	assert.Nil(t, progress.FinishedAt)
+
+	assert.ErrorIs(t, applyProgress(progress, &model.ProgressRequest{Page: page(201)}, now), ErrInvalidProgress)
+
+	percent := 100.0
+	assert.NoError(t, applyProgress(progress, &model.ProgressRequest{Percent: &percent}, now))
+	assert.Equal(t, 200, progress.Page)
+	assert.Equal(t, &now, progress.FinishedAt)
+
+	early := now.AddDate(0, 0, -1)
+	fresh := &model.ReadingProgress{}
+	assert.ErrorIs(t, applyProgress(fresh, &model.ProgressRequest{FinishedAt: &early}, now), ErrInvalidProgress)
+}
+
+func TestMeasureGoal(t *testing.T) {
+	now := time.Date(2026, 7, 2, 12, 0, 0, 0, time.UTC)
+
+	current := &model.YearSummary{Year: 2026, Target: 24, Finished: 11}
+	measureGoal(current, now)
+	assert.Equal(t, 12, current.Expected)
+	assert.Equal(t, 13, current.Remaining)
+	assert.False(t, current.OnTrack)
+
+	past := &model.YearSummary{Year: 2025, Target: 10, Finished: 12}
+	measureGoal(past, now)
+	assert.Equal(t, 0, past.Remaining)
+	assert.Equal(t, 120.0, past.Percent)
+	assert.True(t, past.OnTrack)
+}
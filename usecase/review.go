@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"errors"
+
+	"go.test/model"
+	"go.test/repository"
+	util "go.test/utils"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidRating = errors.New("rating must be between 1 and 5")
+	ErrReviewExists  = errors.New("you have already reviewed this book")
+	ErrReviewBanned  = errors.New("you are not allowed to write reviews")
+)
+
+type ReviewUsecase interface {
+	GetReviews(bookID uint, role string) ([]model.Review, error)
+	CreateReview(review *model.Review, username string) error
+	UpdateReview(review *model.Review, username string) error
+	DeleteReview(id uint, username string) error
+}
+
+type reviewUsecase struct {
+	reviewRepo     repository.ReviewRepository
+	bookRepo       repository.BookRepository
+	userRepo       repository.UserRepository
+	moderationRepo repository.ModerationRepository
+	filter         *ContentFilter
+}
+
+func NewReviewUsecase(reviewRepo repository.ReviewRepository, bookRepo repository.BookRepository, userRepo repository.UserRepository, moderationRepo repository.ModerationRepository, filter *ContentFilter) ReviewUsecase {
+	return &reviewUsecase{reviewRepo, bookRepo, userRepo, moderationRepo, filter}
+}
+
+// GetReviews lists the reviews of a book. Hidden reviews are only shown to
+// supervisors and above.
+func (u *reviewUsecase) GetReviews(bookID uint, role string) ([]model.Review, error) {
+	if _, err := u.bookRepo.GetByID(bookID); err != nil {
+		return nil, err
+	}
+	return u.reviewRepo.GetByBookID(bookID, util.HasRole(role, "supervisor"))
+}
+
+// CreateReview publishes a review by the current user, unless the content
+// filter holds it for moderation.
+func (u *reviewUsecase) CreateReview(review *model.Review, username string) error {
+	if review.Rating < 1 || review.Rating > 5 {
+		return ErrInvalidRating
+	}
+	user, err := u.userRepo.GetByUsername(username)
+	if err != nil {
+		return err
+	}
+	if err := u.checkBan(user.ID); err != nil {
+		return err
+	}
+	if _, err := u.bookRepo.GetByID(review.BookID); err != nil {
+		return err
+	}
+	_, err = u.reviewRepo.GetByBookAndUser(review.BookID, user.ID)
+	if err == nil {
+		return ErrReviewExists
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	review.UserID = user.ID
+	review.Status = model.ReviewStatusPublished
+	flagged := u.filter.Check(review.Text)
+	if flagged != "" {
+		review.Status = model.ReviewStatusPending
+	}
+	if err := u.reviewRepo.Create(review); err != nil {
+		return err
+	}
+	return u.logHold(review, flagged)
+}
+
+// UpdateReview changes the rating and text of the caller's own review. The
+// new text goes through the content filter again; a hidden review stays
+// hidden.
+func (u *reviewUsecase) UpdateReview(review *model.Review, username string) error {
+	if review.Rating < 1 || review.Rating > 5 {
+		return ErrInvalidRating
+	}
+	existing, err := u.ownReview(review.ID, username)
+	if err != nil {
+		return err
+	}
+	if err := u.checkBan(existing.UserID); err != nil {
+		return err
+	}
+	existing.Rating = review.Rating
+	existing.Text = review.Text
+	flagged := u.filter.Check(existing.Text)
+	if existing.Status != model.ReviewStatusHidden {
+		existing.Status = model.ReviewStatusPublished
+		if flagged != "" {
+			existing.Status = model.ReviewStatusPending
+		}
+	}
+	if err := u.reviewRepo.Update(existing); err != nil {
+		return err
+	}
+	*review = *existing
+	if existing.Status != model.ReviewStatusPending {
+		return nil
+	}
+	return u.logHold(existing, flagged)
+}
+
+func (u *reviewUsecase) DeleteReview(id uint, username string) error {
+	if _, err := u.ownReview(id, username); err != nil {
+		return err
+	}
+	return u.reviewRepo.Delete(id)
+}
+
+func (u *reviewUsecase) ownReview(id uint, username string) (*model.Review, error) {
+	review, err := u.reviewRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	user, err := u.userRepo.GetByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	if review.UserID != user.ID {
+		return nil, ErrForbidden
+	}
+	return review, nil
+}
+
+func (u *reviewUsecase) checkBan(userID uint) error {
+	banned, err := u.moderationRepo.IsBanned(userID)
+	if err != nil {
+		return err
+	}
+	if banned {
+		return ErrReviewBanned
+	}
+	return nil
+}
+
+// logHold records that the content filter held a review back.
+func (u *reviewUsecase) logHold(review *model.Review, reason string) error {
+	if reason == "" {
+		return nil
+	}
+	return u.moderationRepo.CreateLog(&model.ModerationLog{
+		ReviewID: review.ID,
+		Action:   model.ModerationActionHold,
+		Actor:    "filter",
+		Reason:   reason,
+	})
+}
@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ContentFilter holds back reviews whose text contains a blocked word or
+// matches a blocked pattern. Words match case-insensitively and only as
+// whole words; patterns are regular expressions.
+type ContentFilter struct {
+	words    *regexp.Regexp
+	patterns []*regexp.Regexp
+}
+
+func NewContentFilter(words, patterns []string) (*ContentFilter, error) {
+	filter := &ContentFilter{}
+	var quoted []string
+	for _, word := range words {
+		if word = strings.TrimSpace(word); word != "" {
+			quoted = append(quoted, regexp.QuoteMeta(word))
+		}
+	}
+	if len(quoted) > 0 {
+		filter.words = regexp.MustCompile(`(?i)\b(` + strings.Join(quoted, "|") + `)\b`)
+	}
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		filter.patterns = append(filter.patterns, re)
+	}
+	return filter, nil
+}
+
+// Check returns why the text was flagged, or an empty string if it is clean.
+func (f *ContentFilter) Check(text string) string {
+	if f == nil {
+		return ""
+	}
+	if f.words != nil {
+		if word := f.words.FindString(text); word != "" {
+			return "blocked word: " + strings.ToLower(word)
+		}
+	}
+	for _, re := range f.patterns {
+		if re.MatchString(text) {
+			return "blocked pattern: " + re.String()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,23 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentFilter(t *testing.T) {
+	filter, err := NewContentFilter([]string{"spoiler", " "}, []string{`https?://`})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "", filter.Check("A gripping read"))
+	assert.Equal(t, "blocked word: spoiler", filter.Check("SPOILER: the butler did it"))
+	assert.Equal(t, "", filter.Check("no spoilers here"))
+	assert.Equal(t, "blocked pattern: https?://", filter.Check("buy it at http://example.com"))
+
+	var none *ContentFilter
+	assert.Equal(t, "", none.Check("spoiler"))
+
+	_, err = NewContentFilter(nil, []string{"("})
+	assert.Error(t, err)
+}
@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"errors"
+	"time"
+
+	"go.test/model"
+	"go.test/repository"
+	"go.test/storage"
+)
+
+var (
+	ErrBookHasCopies      = repository.ErrBookHasCopies
+	ErrUserHasObligations = repository.ErrUserHasObligations
+)
+
+type TrashUsecase interface {
+	GetTrash() (*model.Trash, error)
+	RestoreBook(id uint, actor string) error
+	PurgeBook(id uint) error
+	RestoreUser(id uint) error
+	PurgeUser(id uint) error
+	PurgeExpired() (books, users int, err error)
+}
+
+type trashUsecase struct {
+	bookRepo  repository.BookRepository
+	userRepo  repository.UserRepository
+	ebookRepo repository.EbookRepository
+	store     storage.BlobStore
+	files     *storage.ContentStore
+	retention time.Duration
+}
+
+// NewTrashUsecase returns a TrashUsecase that purges deleted books and users
+// once they have been in the trash for longer than retention. A retention of
+// zero keeps them until they are purged by hand. Purged books take their
+// cover images in store and their ebook files in files with them.
+func NewTrashUsecase(bookRepo repository.BookRepository, userRepo repository.UserRepository, ebookRepo repository.EbookRepository,
+	store storage.BlobStore, files *storage.ContentStore, retention time.Duration) TrashUsecase {
+	return &trashUsecase{bookRepo, userRepo, ebookRepo, store, files, retention}
+}
+
+func (u *trashUsecase) GetTrash() (*model.Trash, error) {
+	books, err := u.bookRepo.GetDeleted()
+	if err != nil {
+		return nil, err
+	}
+	users, err := u.userRepo.GetDeleted()
+	if err != nil {
+		return nil, err
+	}
+	return &model.Trash{Books: books, Users: users}, nil
+}
+
+func (u *trashUsecase) RestoreBook(id uint, actor string) error {
+	return u.bookRepo.Restore(id, actor)
+}
+
+func (u *trashUsecase) PurgeBook(id uint) error {
+	return u.purgeBook(id)
+}
+
+func (u *trashUsecase) RestoreUser(id uint) error {
+	return u.userRepo.Restore(id)
+}
+
+func (u *trashUsecase) PurgeUser(id uint) error {
+	return u.userRepo.Purge(id)
+}
+
+// PurgeExpired purges the books and users that have been in the trash for
+// longer than the retention period. Records that cannot be purged yet, such
+// as books with copies, are left for a later run.
+func (u *trashUsecase) PurgeExpired() (books, users int, err error) {
+	if u.retention <= 0 {
+		return 0, 0, nil
+	}
+	cutoff := time.Now().Add(-u.retention)
+
+	deletedBooks, err := u.bookRepo.GetDeleted()
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, book := range deletedBooks {
+		if !book.DeletedAt.Time.Before(cutoff) {
+			continue
+		}
+		if err := u.purgeBook(book.ID); err != nil {
+			if errors.Is(err, ErrBookHasCopies) {
+				continue
+			}
+			return books, users, err
+		}
+		books++
+	}
+
+	deletedUsers, err := u.userRepo.GetDeleted()
+	if err != nil {
+		return books, 0, err
+	}
+	for _, user := range deletedUsers {
+		if !user.DeletedAt.Time.Before(cutoff) {
+			continue
+		}
+		if err := u.userRepo.Purge(user.ID); err != nil {
+			if errors.Is(err, ErrUserHasObligations) {
+				continue
+			}
+			return books, users, err
+		}
+		users++
+	}
+	return books, users, nil
+}
+
+// purgeBook purges a book and then deletes its files. Cover images are only
+// ever used by their book; an ebook file is kept while another ebook has the
+// same one.
+func (u *trashUsecase) purgeBook(id uint) error {
+	coverKeys, checksums, err := u.bookRepo.Purge(id)
+	if err != nil {
+		return err
+	}
+	for _, key := range coverKeys {
+		u.store.Delete(key)
+	}
+	for _, sum := range checksums {
+		shared, err := u.ebookRepo.CountByChecksum(sum)
+		if err != nil {
+			return err
+		}
+		if shared > 0 {
+			continue
+		}
+		if err := u.files.Delete(sum); err != nil {
+			return err
+		}
+	}
+	return nil
+}
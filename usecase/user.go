@@ -2,19 +2,28 @@ package usecase
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 
 	"go.test/model"
 	"go.test/repository"
 	util "go.test/utils"
 )
 
+var (
+	ErrUsernameTaken   = repository.ErrUsernameTaken
+	ErrUsernameInTrash = repository.ErrUsernameInTrash
+)
+
 type UserUsecase interface {
 	RegisterUser(user *model.User) error
 	LoginUser(username, password string) (string, error)
 	GetAllUsers() ([]model.User, error)
 	GetUserByID(id uint) (*model.User, error)
-	UpdateUser(user *model.User) error
-	DeleteUser(id uint) error
+	UpdateUser(user *model.User, actor string) error
+	PatchUser(id uint, version int, mediaType string, patch []byte, actor string) (*model.User, error)
+	GetHistory(id uint) ([]model.UserRevision, error)
+	DeleteUser(id uint, version int) error
 }
 
 type userUsecase struct {
@@ -57,10 +66,40 @@ func (u *userUsecase) GetUserByID(id uint) (*model.User, error) {
 	return u.userRepo.GetByID(id)
 }
 
-func (u *userUsecase) UpdateUser(user *model.User) error {
-	return u.userRepo.Update(user)
+// UpdateUser saves changes to an existing user. Users in the trash have to
+// be restored first; saving them directly would bring them back. A non-zero
+// user.Version has to match the stored version.
+func (u *userUsecase) UpdateUser(user *model.User, actor string) error {
+	return u.userRepo.Update(user, actor)
+}
+
+// PatchUser applies a JSON Merge Patch or JSON Patch to a user's name and
+// role. The patched user must keep a name and have a known role.
+func (u *userUsecase) PatchUser(id uint, version int, mediaType string, patch []byte, actor string) (*model.User, error) {
+	return u.userRepo.Patch(id, version, actor, patchNote(mediaType), func(user *model.User) error {
+		fields, err := patchFields(mediaType, user.Fields(), patch)
+		if err != nil {
+			return err
+		}
+		user.SetFields(fields)
+		if strings.TrimSpace(user.Username) == "" {
+			return fmt.Errorf("%w: username is required", ErrInvalidPatch)
+		}
+		if !util.IsRole(user.Role) {
+			return fmt.Errorf("%w: unknown role %q", ErrInvalidPatch, user.Role)
+		}
+		return nil
+	})
+}
+
+// GetHistory returns the revisions of a user, newest first.
+func (u *userUsecase) GetHistory(id uint) ([]model.UserRevision, error) {
+	if _, err := u.userRepo.GetByID(id); err != nil {
+		return nil, err
+	}
+	return u.userRepo.GetRevisions(id)
 }
 
-func (u *userUsecase) DeleteUser(id uint) error {
-	return u.userRepo.Delete(id)
+func (u *userUsecase) DeleteUser(id uint, version int) error {
+	return u.userRepo.Delete(id, version)
 }
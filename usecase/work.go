@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"errors"
+	"strings"
+
+	"go.test/model"
+	"go.test/repository"
+)
+
+var (
+	ErrTitleRequired         = errors.New("a title is required")
+	ErrInvalidSeriesPosition = errors.New("position must be a number of 0 or more")
+	ErrNotEdition            = repository.ErrNotEdition
+	ErrNotInSeries           = repository.ErrNotInSeries
+)
+
+type WorkUsecase interface {
+	GetWork(id uint) (*model.Work, error)
+	CreateWork(work *model.Work) error
+	UpdateWork(work *model.Work) error
+	DeleteWork(id uint) error
+	AddEdition(workID uint, req *model.EditionRequest) (*model.Work, error)
+	RemoveEdition(workID, bookID uint) error
+	GetAllSeries() ([]model.Series, error)
+	GetSeries(id uint) (*model.Series, error)
+	CreateSeries(series *model.Series) error
+	UpdateSeries(series *model.Series) error
+	DeleteSeries(id uint) error
+	SetSeriesEntry(seriesID, workID uint, req *model.SeriesEntryRequest) (*model.Series, error)
+	RemoveSeriesEntry(seriesID, workID uint) error
+}
+
+type workUsecase struct {
+	workRepo repository.WorkRepository
+}
+
+func NewWorkUsecase(workRepo repository.WorkRepository) WorkUsecase {
+	return &workUsecase{workRepo}
+}
+
+// GetWork returns a work with its editions, oldest first, and the works
+// before and after it in its series.
+func (u *workUsecase) GetWork(id uint) (*model.Work, error) {
+	work, err := u.workRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if work.Previous, work.Next, err = u.workRepo.GetNeighbors(work); err != nil {
+		return nil, err
+	}
+	return work, nil
+}
+
+func (u *workUsecase) CreateWork(work *model.Work) error {
+	if strings.TrimSpace(work.Title) == "" {
+		return ErrTitleRequired
+	}
+	work.ID = 0
+	work.SeriesID, work.SeriesPosition = nil, nil
+	return u.workRepo.Create(work)
+}
+
+func (u *workUsecase) UpdateWork(work *model.Work) error {
+	if strings.TrimSpace(work.Title) == "" {
+		return ErrTitleRequired
+	}
+	return u.workRepo.Update(work)
+}
+
+func (u *workUsecase) DeleteWork(id uint) error {
+	return u.workRepo.Delete(id)
+}
+
+// AddEdition makes a book an edition of a work and returns the work. A
+// book can only be an edition of one work, so it leaves its old one.
+func (u *workUsecase) AddEdition(workID uint, req *model.EditionRequest) (*model.Work, error) {
+	if err := u.workRepo.AddEdition(workID, req.BookID); err != nil {
+		return nil, err
+	}
+	return u.GetWork(workID)
+}
+
+func (u *workUsecase) RemoveEdition(workID, bookID uint) error {
+	return u.workRepo.RemoveEdition(workID, bookID)
+}
+
+func (u *workUsecase) GetAllSeries() ([]model.Series, error) {
+	return u.workRepo.GetAllSeries()
+}
+
+// GetSeries returns a series with its works in order, each with its
+// editions.
+func (u *workUsecase) GetSeries(id uint) (*model.Series, error) {
+	return u.workRepo.GetSeries(id)
+}
+
+func (u *workUsecase) CreateSeries(series *model.Series) error {
+	if strings.TrimSpace(series.Title) == "" {
+		return ErrTitleRequired
+	}
+	series.ID = 0
+	return u.workRepo.CreateSeries(series)
+}
+
+func (u *workUsecase) UpdateSeries(series *model.Series) error {
+	if strings.TrimSpace(series.Title) == "" {
+		return ErrTitleRequired
+	}
+	return u.workRepo.UpdateSeries(series)
+}
+
+func (u *workUsecase) DeleteSeries(id uint) error {
+	return u.workRepo.DeleteSeries(id)
+}
+
+// SetSeriesEntry puts a work at a position in a series and returns the
+// series. A work is in at most one series, so it leaves its old one.
+func (u *workUsecase) SetSeriesEntry(seriesID, workID uint, req *model.SeriesEntryRequest) (*model.Series, error) {
+	if req.Position == nil || *req.Position < 0 {
+		return nil, ErrInvalidSeriesPosition
+	}
+	if err := u.workRepo.SetSeriesEntry(seriesID, workID, *req.Position); err != nil {
+		return nil, err
+	}
+	return u.workRepo.GetSeries(seriesID)
+}
+
+func (u *workUsecase) RemoveSeriesEntry(seriesID, workID uint) error {
+	return u.workRepo.RemoveSeriesEntry(seriesID, workID)
+}
@@ -0,0 +1,47 @@
+package util
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// ErrMalformedEbook is returned for EPUB and PDF files whose metadata cannot
+// be found.
+var ErrMalformedEbook = errors.New("malformed ebook file")
+
+// EbookMetadata is the catalog information embedded in an ebook file.
+// Fields the file does not give are empty.
+type EbookMetadata struct {
+	Title     string
+	Authors   []string
+	ISBN      string
+	Published string // a date as in "1965", "1965-08" or "1965-08-01"
+	Cover     []byte
+}
+
+var (
+	isbnPrefix    = regexp.MustCompile(`(?i)^\s*(urn:)?isbn[:\s]*`)
+	datePrefix    = regexp.MustCompile(`^\d{4}(-\d{2}(-\d{2})?)?`)
+	spaceSequence = regexp.MustCompile(`\s+`)
+)
+
+// ebookISBN returns the ISBN in an identifier such as "urn:isbn:978-0-441-
+// 17271-9", or "" if it is not one.
+func ebookISBN(identifier string) string {
+	isbn, ok := NormalizeISBN(isbnPrefix.ReplaceAllString(identifier, ""))
+	if !ok {
+		return ""
+	}
+	return isbn
+}
+
+// ebookDate keeps the date of a timestamp such as "1965-08-01T00:00:00Z".
+func ebookDate(value string) string {
+	return datePrefix.FindString(strings.TrimSpace(value))
+}
+
+// cleanText collapses the white space in a metadata value.
+func cleanText(value string) string {
+	return strings.TrimSpace(spaceSequence.ReplaceAllString(value, " "))
+}
@@ -0,0 +1,187 @@
+package util
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// maxEPUBEntry limits how much of an archive entry is read, so that a
+// small archive cannot unpack into a huge one.
+const maxEPUBEntry = 16 << 20
+
+type epubContainer struct {
+	Rootfiles []struct {
+		FullPath  string `xml:"full-path,attr"`
+		MediaType string `xml:"media-type,attr"`
+	} `xml:"rootfiles>rootfile"`
+}
+
+type opfPackage struct {
+	Metadata struct {
+		Titles      []string        `xml:"http://purl.org/dc/elements/1.1/ title"`
+		Creators    []opfCreator    `xml:"http://purl.org/dc/elements/1.1/ creator"`
+		Identifiers []opfIdentifier `xml:"http://purl.org/dc/elements/1.1/ identifier"`
+		Dates       []opfDate       `xml:"http://purl.org/dc/elements/1.1/ date"`
+		Metas       []opfMeta       `xml:"meta"`
+	} `xml:"metadata"`
+	Manifest []opfItem `xml:"manifest>item"`
+}
+
+type opfCreator struct {
+	ID   string `xml:"id,attr"`
+	Role string `xml:"http://www.idpf.org/2007/opf role,attr"`
+	Name string `xml:",chardata"`
+}
+
+type opfIdentifier struct {
+	Scheme string `xml:"http://www.idpf.org/2007/opf scheme,attr"`
+	Value  string `xml:",chardata"`
+}
+
+type opfDate struct {
+	Event string `xml:"http://www.idpf.org/2007/opf event,attr"`
+	Value string `xml:",chardata"`
+}
+
+// opfMeta is an EPUB 2 <meta name content> or an EPUB 3 <meta property
+// refines>value</meta>.
+type opfMeta struct {
+	Name     string `xml:"name,attr"`
+	Content  string `xml:"content,attr"`
+	Property string `xml:"property,attr"`
+	Refines  string `xml:"refines,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type opfItem struct {
+	ID         string `xml:"id,attr"`
+	Href       string `xml:"href,attr"`
+	MediaType  string `xml:"media-type,attr"`
+	Properties string `xml:"properties,attr"`
+}
+
+// ReadEPUBMetadata reads the package metadata of an EPUB 2 or 3 file, and
+// its cover image.
+func ReadEPUBMetadata(r io.ReaderAt, size int64) (*EbookMetadata, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedEbook, err)
+	}
+	var container epubContainer
+	if err := decodeEntry(archive, "META-INF/container.xml", &container); err != nil {
+		return nil, err
+	}
+	if len(container.Rootfiles) == 0 {
+		return nil, fmt.Errorf("%w: no package document", ErrMalformedEbook)
+	}
+	opfPath := container.Rootfiles[0].FullPath
+	var pkg opfPackage
+	if err := decodeEntry(archive, opfPath, &pkg); err != nil {
+		return nil, err
+	}
+
+	metadata := &EbookMetadata{}
+	if len(pkg.Metadata.Titles) > 0 {
+		metadata.Title = cleanText(pkg.Metadata.Titles[0])
+	}
+	roles := make(map[string]string)
+	for _, meta := range pkg.Metadata.Metas {
+		if meta.Property == "role" && strings.HasPrefix(meta.Refines, "#") {
+			roles[meta.Refines[1:]] = meta.Value
+		}
+	}
+	for _, creator := range pkg.Metadata.Creators {
+		role := creator.Role
+		if role == "" {
+			role = roles[creator.ID]
+		}
+		if name := cleanText(creator.Name); name != "" && (role == "" || role == "aut") {
+			metadata.Authors = append(metadata.Authors, name)
+		}
+	}
+	for _, identifier := range pkg.Metadata.Identifiers {
+		if isbn := ebookISBN(identifier.Value); isbn != "" {
+			metadata.ISBN = isbn
+			break
+		}
+	}
+	for _, date := range pkg.Metadata.Dates {
+		if date.Event == "" || date.Event == "publication" {
+			metadata.Published = ebookDate(date.Value)
+			break
+		}
+	}
+	if cover := epubCover(&pkg); cover != nil {
+		// The manifest gives paths relative to the package document.
+		name := path.Join(path.Dir(opfPath), cover.Href)
+		if data, err := readEntry(archive, name); err == nil {
+			metadata.Cover = data
+		}
+	}
+	return metadata, nil
+}
+
+// epubCover finds the cover image in the manifest: the item with the
+// cover-image property in EPUB 3, or the one the cover meta names in EPUB
+// 2.
+func epubCover(pkg *opfPackage) *opfItem {
+	coverID := ""
+	for _, meta := range pkg.Metadata.Metas {
+		if meta.Name == "cover" {
+			coverID = meta.Content
+		}
+	}
+	for i, item := range pkg.Manifest {
+		if !strings.HasPrefix(item.MediaType, "image/") {
+			continue
+		}
+		if strings.Contains(" "+item.Properties+" ", " cover-image ") || (coverID != "" && item.ID == coverID) {
+			return &pkg.Manifest[i]
+		}
+	}
+	return nil
+}
+
+func decodeEntry(archive *zip.Reader, name string, v interface{}) error {
+	data, err := readEntry(archive, name)
+	if err != nil {
+		return err
+	}
+	decoder := xml.NewDecoder(strings.NewReader(string(data)))
+	decoder.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		if strings.EqualFold(charset, "utf-8") || strings.EqualFold(charset, "us-ascii") {
+			return input, nil
+		}
+		return nil, fmt.Errorf("unsupported charset %s", charset)
+	}
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrMalformedEbook, name, err)
+	}
+	return nil
+}
+
+func readEntry(archive *zip.Reader, name string) ([]byte, error) {
+	for _, file := range archive.File {
+		if file.Name != name {
+			continue
+		}
+		entry, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrMalformedEbook, name, err)
+		}
+		defer entry.Close()
+		data, err := io.ReadAll(io.LimitReader(entry, maxEPUBEntry+1))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrMalformedEbook, name, err)
+		}
+		if len(data) > maxEPUBEntry {
+			return nil, fmt.Errorf("%w: %s is too large", ErrMalformedEbook, name)
+		}
+		return data, nil
+	}
+	return nil, fmt.Errorf("%w: no %s", ErrMalformedEbook, name)
+}
@@ -0,0 +1,107 @@
+package util
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func buildEPUB(t *testing.T, opf string, files map[string]string) []byte {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	entries := map[string]string{
+		"mimetype": "application/epub+zip",
+		"META-INF/container.xml": `<?xml version="1.0"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles><rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/></rootfiles>
+</container>`,
+		"OEBPS/content.opf": opf,
+	}
+	for name, content := range files {
+		entries[name] = content
+	}
+	for name, content := range entries {
+		w, err := archive.Create(name)
+		assert.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, archive.Close())
+	return buf.Bytes()
+}
+
+func TestReadEPUBMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		opf      string
+		expected EbookMetadata
+	}{
+		{
+			name: "epub3",
+			opf: `<?xml version="1.0" encoding="UTF-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+    <dc:title>  Dune
+      </dc:title>
+    <dc:creator id="c1">Frank Herbert</dc:creator>
+    <meta refines="#c1" property="role">aut</meta>
+    <dc:creator id="c2">John Schoenherr</dc:creator>
+    <meta refines="#c2" property="role">ill</meta>
+    <dc:identifier>urn:uuid:6f1a3c1e</dc:identifier>
+    <dc:identifier>urn:isbn:978-0-441-17271-9</dc:identifier>
+    <dc:date>1965-08-01T00:00:00Z</dc:date>
+  </metadata>
+  <manifest>
+    <item id="img" href="images/front.jpg" media-type="image/jpeg" properties="cover-image"/>
+  </manifest>
+</package>`,
+			expected: EbookMetadata{Title: "Dune", Authors: []string{"Frank Herbert"}, ISBN: "9780441172719", Published: "1965-08-01", Cover: []byte("jpeg")},
+		},
+		{
+			name: "epub2",
+			opf: `<?xml version="1.0"?>
+<package xmlns="http://www.idpf.org/2007/opf" xmlns:opf="http://www.idpf.org/2007/opf" version="2.0">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+    <dc:title>Good Omens</dc:title>
+    <dc:creator opf:role="aut">Terry Pratchett</dc:creator>
+    <dc:creator opf:role="aut">Neil Gaiman</dc:creator>
+    <dc:identifier opf:scheme="ISBN">0-441-17271-7</dc:identifier>
+    <dc:date opf:event="modification">2010-01-01</dc:date>
+    <dc:date opf:event="publication">1990-05</dc:date>
+    <meta name="cover" content="cover-jpg"/>
+  </metadata>
+  <manifest>
+    <item id="cover" href="cover.xhtml" media-type="application/xhtml+xml"/>
+    <item id="cover-jpg" href="images/front.jpg" media-type="image/jpeg"/>
+  </manifest>
+</package>`,
+			expected: EbookMetadata{Title: "Good Omens", Authors: []string{"Terry Pratchett", "Neil Gaiman"}, ISBN: "0441172717", Published: "1990-05", Cover: []byte("jpeg")},
+		},
+		{
+			name: "sparse",
+			opf: `<package xmlns="http://www.idpf.org/2007/opf" version="3.0">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/"><dc:identifier>isbn:1234</dc:identifier></metadata>
+</package>`,
+			expected: EbookMetadata{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := buildEPUB(t, tt.opf, map[string]string{"OEBPS/images/front.jpg": "jpeg"})
+			metadata, err := ReadEPUBMetadata(bytes.NewReader(data), int64(len(data)))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, *metadata)
+		})
+	}
+}
+
+func TestReadEPUBMetadataMalformed(t *testing.T) {
+	_, err := ReadEPUBMetadata(bytes.NewReader([]byte("not a zip")), 9)
+	assert.ErrorIs(t, err, ErrMalformedEbook)
+
+	data := buildEPUB(t, "<package", nil)
+	_, err = ReadEPUBMetadata(bytes.NewReader(data), int64(len(data)))
+	assert.ErrorIs(t, err, ErrMalformedEbook)
+}
@@ -0,0 +1,42 @@
+package util
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ETag returns the strong entity tag of a resource at the given version.
+func ETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// ParseIfMatch returns the version an If-Match header asks for. An empty
+// header or "*" give 0, meaning any version. ok is false when the header is
+// not a single strong tag of the form ETag returns, which can never match.
+func ParseIfMatch(header string) (version int, ok bool) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return 0, true
+	}
+	if len(header) < 3 || header[0] != '"' || header[len(header)-1] != '"' {
+		return 0, false
+	}
+	version, err := strconv.Atoi(header[1 : len(header)-1])
+	if err != nil || version < 1 {
+		return 0, false
+	}
+	return version, true
+}
+
+// MatchesETag reports whether an If-None-Match header names etag, or is
+// "*". Weak tags match their strong counterparts, as the header's weak
+// comparison calls for.
+func MatchesETag(ifNoneMatch, etag string) bool {
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package util
+
+import (
+	"strconv"
+	"strings"
+)
+
+// NormalizeISBN strips hyphens and spaces from an ISBN and reports whether
+// what is left is a valid ISBN-10 or ISBN-13.
+func NormalizeISBN(isbn string) (string, bool) {
+	isbn = strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(isbn))
+	switch len(isbn) {
+	case 10:
+		sum := 0
+		for i, r := range isbn {
+			var digit int
+			switch {
+			case r >= '0' && r <= '9':
+				digit = int(r - '0')
+			case r == 'X' && i == 9:
+				digit = 10
+			default:
+				return isbn, false
+			}
+			sum += (10 - i) * digit
+		}
+		return isbn, sum%11 == 0
+	case 13:
+		sum := 0
+		for i, r := range isbn {
+			if r < '0' || r > '9' {
+				return isbn, false
+			}
+			weight := 1
+			if i%2 == 1 {
+				weight = 3
+			}
+			sum += weight * int(r-'0')
+		}
+		return isbn, sum%10 == 0
+	}
+	return isbn, false
+}
+
+// ISBN13 returns a valid ISBN in its 13-digit form, so that the ISBN-10 and
+// the ISBN-13 of a book compare equal.
+func ISBN13(isbn string) (string, bool) {
+	isbn, ok := NormalizeISBN(isbn)
+	if !ok || len(isbn) == 13 {
+		return isbn, ok
+	}
+	isbn = "978" + isbn[:9]
+	sum := 0
+	for i, r := range isbn {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += weight * int(r-'0')
+	}
+	return isbn + strconv.Itoa((10-sum%10)%10), true
+}
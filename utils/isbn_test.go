@@ -0,0 +1,43 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeISBN(t *testing.T) {
+	tests := []struct {
+		isbn     string
+		expected string
+		valid    bool
+	}{
+		{"978-0-441-17271-9", "9780441172719", true},
+		{"0-441-17271-7", "0441172717", true},
+		{"0 8044 2957 x", "080442957X", true},
+		{"978-0-441-17271-8", "9780441172718", false},
+		{"X441172717", "X441172717", false},
+		{"12345", "12345", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.isbn, func(t *testing.T) {
+			isbn, valid := NormalizeISBN(tt.isbn)
+			assert.Equal(t, tt.expected, isbn)
+			assert.Equal(t, tt.valid, valid)
+		})
+	}
+}
+
+func TestISBN13(t *testing.T) {
+	isbn, ok := ISBN13("0-441-17271-7")
+	assert.True(t, ok)
+	assert.Equal(t, "9780441172719", isbn)
+	isbn, ok = ISBN13("0 8044 2957 x")
+	assert.True(t, ok)
+	assert.Equal(t, "9780804429573", isbn)
+	isbn, ok = ISBN13("978-0-441-17271-9")
+	assert.True(t, ok)
+	assert.Equal(t, "9780441172719", isbn)
+	_, ok = ISBN13("0-441-17271-8")
+	assert.False(t, ok)
+}
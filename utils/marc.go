@@ -0,0 +1,294 @@
+package util
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Delimiters of ISO 2709 records.
+const (
+	marcSubfieldDelimiter = 0x1F
+	marcFieldTerminator   = 0x1E
+	marcRecordTerminator  = 0x1D
+)
+
+// marcMaxLength is the longest record ISO 2709 can describe.
+const marcMaxLength = 99999
+
+// MARCLeader is the leader of records that are made from scratch: a new
+// record of a monograph of language material, in Unicode.
+const MARCLeader = "00000nam a2200000   4500"
+
+var (
+	ErrMalformedMARC = errors.New("malformed MARC record")
+	ErrMARCTooLong   = errors.New("MARC record is longer than 99999 bytes")
+)
+
+// MARCRecord is a MARC 21 record. Fields keep the order they were read in.
+type MARCRecord struct {
+	Leader string
+	Fields []MARCField
+}
+
+// MARCField is a control field, which has a tag from 001 to 009 and only a
+// value, or a data field with indicators and subfields.
+type MARCField struct {
+	Tag        string
+	Indicators [2]byte
+	Value      string
+	Subfields  []MARCSubfield
+}
+
+type MARCSubfield struct {
+	Code  byte
+	Value string
+}
+
+// IsControl reports whether the field is a control field.
+func (f *MARCField) IsControl() bool {
+	return len(f.Tag) == 3 && f.Tag[0] == '0' && f.Tag[1] == '0'
+}
+
+// Subfield returns the value of the first subfield with the code, or "".
+func (f *MARCField) Subfield(code byte) string {
+	for _, subfield := range f.Subfields {
+		if subfield.Code == code {
+			return subfield.Value
+		}
+	}
+	return ""
+}
+
+// SetSubfield sets the first subfield with the code, or adds one. An empty
+// value removes every subfield with the code.
+func (f *MARCField) SetSubfield(code byte, value string) {
+	subfields := f.Subfields[:0:0]
+	set := false
+	for _, subfield := range f.Subfields {
+		if subfield.Code != code {
+			subfields = append(subfields, subfield)
+		} else if value != "" && !set {
+			subfields = append(subfields, MARCSubfield{code, value})
+			set = true
+		}
+	}
+	if value != "" && !set {
+		subfields = append(subfields, MARCSubfield{code, value})
+	}
+	f.Subfields = subfields
+}
+
+// Field returns the first field with the tag, or nil.
+func (r *MARCRecord) Field(tag string) *MARCField {
+	for i := range r.Fields {
+		if r.Fields[i].Tag == tag {
+			return &r.Fields[i]
+		}
+	}
+	return nil
+}
+
+// AddField adds a field after the fields with the same or a lower tag.
+func (r *MARCRecord) AddField(field MARCField) {
+	i := len(r.Fields)
+	for i > 0 && r.Fields[i-1].Tag > field.Tag {
+		i--
+	}
+	r.Fields = append(r.Fields, MARCField{})
+	copy(r.Fields[i+1:], r.Fields[i:])
+	r.Fields[i] = field
+}
+
+// RemoveField removes a field that the record holds.
+func (r *MARCRecord) RemoveField(field *MARCField) {
+	for i := range r.Fields {
+		if &r.Fields[i] == field {
+			r.Fields = append(r.Fields[:i], r.Fields[i+1:]...)
+			return
+		}
+	}
+}
+
+// UnmarshalMARC reads a record in ISO 2709, the exchange format of MARC 21.
+// Only records in Unicode, or in MARC-8 with nothing but ASCII, can be read.
+// The leader of an ASCII record is changed to say that it is in Unicode.
+func UnmarshalMARC(data []byte) (*MARCRecord, error) {
+	if len(data) < 25 || data[len(data)-1] != marcRecordTerminator {
+		return nil, fmt.Errorf("%w: too short or not terminated", ErrMalformedMARC)
+	}
+	leader := []byte(string(data[:24]))
+	base, ok := marcNumber(leader[12:17])
+	if !ok || base < 25 || base > len(data) || data[base-1] != marcFieldTerminator {
+		return nil, fmt.Errorf("%w: bad base address of data", ErrMalformedMARC)
+	}
+	if leader[9] != 'a' {
+		if !isASCII(data) {
+			return nil, fmt.Errorf("%w: only Unicode records are supported", ErrMalformedMARC)
+		}
+		leader[9] = 'a'
+	}
+	if !utf8.Valid(data) {
+		return nil, fmt.Errorf("%w: invalid UTF-8", ErrMalformedMARC)
+	}
+
+	directory := data[24 : base-1]
+	if len(directory)%12 != 0 {
+		return nil, fmt.Errorf("%w: bad directory", ErrMalformedMARC)
+	}
+	record := &MARCRecord{Leader: string(leader)}
+	for entry := directory; len(entry) > 0; entry = entry[12:] {
+		tag := string(entry[:3])
+		length, ok1 := marcNumber(entry[3:7])
+		start, ok2 := marcNumber(entry[7:12])
+		end := base + start + length
+		if !ok1 || !ok2 || length < 1 || base+start < base || end > len(data)-1 || data[end-1] != marcFieldTerminator {
+			return nil, fmt.Errorf("%w: bad directory entry for %s", ErrMalformedMARC, tag)
+		}
+		field, err := unmarshalMARCField(tag, data[base+start:end-1])
+		if err != nil {
+			return nil, err
+		}
+		record.Fields = append(record.Fields, field)
+	}
+	return record, nil
+}
+
+// marcNumber reads a number of the leader or directory, which is written
+// with digits only.
+func marcNumber(digits []byte) (int, bool) {
+	for _, b := range digits {
+		if b < '0' || b > '9' {
+			return 0, false
+		}
+	}
+	n, err := strconv.Atoi(string(digits))
+	return n, err == nil
+}
+
+func unmarshalMARCField(tag string, data []byte) (MARCField, error) {
+	field := MARCField{Tag: tag}
+	if field.IsControl() {
+		field.Value = string(data)
+		return field, nil
+	}
+	if len(data) < 2 {
+		return field, fmt.Errorf("%w: field %s has no indicators", ErrMalformedMARC, tag)
+	}
+	field.Indicators = [2]byte{data[0], data[1]}
+	parts := bytes.Split(data[2:], []byte{marcSubfieldDelimiter})
+	if len(parts[0]) > 0 {
+		return field, fmt.Errorf("%w: field %s has data outside subfields", ErrMalformedMARC, tag)
+	}
+	for _, part := range parts[1:] {
+		if len(part) == 0 {
+			return field, fmt.Errorf("%w: field %s has a subfield without a code", ErrMalformedMARC, tag)
+		}
+		field.Subfields = append(field.Subfields, MARCSubfield{part[0], string(part[1:])})
+	}
+	return field, nil
+}
+
+// MarshalMARC writes a record in ISO 2709. The record length and base
+// address in the leader are filled in.
+func MarshalMARC(record *MARCRecord) ([]byte, error) {
+	leader := []byte(record.Leader)
+	if len(leader) != 24 {
+		leader = []byte(MARCLeader)
+	}
+	var directory, data bytes.Buffer
+	for _, field := range record.Fields {
+		start := data.Len()
+		if field.IsControl() {
+			data.WriteString(field.Value)
+		} else {
+			data.Write(marcIndicators(field.Indicators))
+			for _, subfield := range field.Subfields {
+				data.WriteByte(marcSubfieldDelimiter)
+				data.WriteByte(subfield.Code)
+				data.WriteString(subfield.Value)
+			}
+		}
+		data.WriteByte(marcFieldTerminator)
+		if len(field.Tag) != 3 {
+			return nil, fmt.Errorf("%w: bad tag %q", ErrMalformedMARC, field.Tag)
+		}
+		fmt.Fprintf(&directory, "%s%04d%05d", field.Tag, data.Len()-start, start)
+	}
+	directory.WriteByte(marcFieldTerminator)
+	data.WriteByte(marcRecordTerminator)
+
+	base := 24 + directory.Len()
+	length := base + data.Len()
+	if length > marcMaxLength {
+		return nil, ErrMARCTooLong
+	}
+	copy(leader[0:5], fmt.Sprintf("%05d", length))
+	copy(leader[12:17], fmt.Sprintf("%05d", base))
+	out := make([]byte, 0, length)
+	out = append(out, leader...)
+	out = append(out, directory.Bytes()...)
+	return append(out, data.Bytes()...), nil
+}
+
+func marcIndicators(indicators [2]byte) []byte {
+	out := []byte{indicators[0], indicators[1]}
+	for i, indicator := range out {
+		if indicator == 0 {
+			out[i] = ' '
+		}
+	}
+	return out
+}
+
+// MARCReader reads a stream of ISO 2709 records. Line breaks between
+// records are skipped.
+type MARCReader struct {
+	reader *bufio.Reader
+}
+
+func NewMARCReader(r io.Reader) *MARCReader {
+	return &MARCReader{bufio.NewReader(r)}
+}
+
+// Read returns the next record, or io.EOF after the last one. Errors that
+// wrap ErrMalformedMARC reject one record and reading can go on; others
+// mean that the stream cannot be read any further.
+func (r *MARCReader) Read() (*MARCRecord, error) {
+	for {
+		b, err := r.reader.Peek(1)
+		if err != nil {
+			return nil, err
+		}
+		if b[0] != '\n' && b[0] != '\r' {
+			break
+		}
+		r.reader.Discard(1)
+	}
+	prefix, err := r.reader.Peek(5)
+	if err != nil {
+		return nil, fmt.Errorf("record is cut short: %w", io.ErrUnexpectedEOF)
+	}
+	length, err := strconv.Atoi(string(prefix))
+	if err != nil || length < 25 {
+		return nil, fmt.Errorf("bad record length %q", prefix)
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r.reader, data); err != nil {
+		return nil, fmt.Errorf("record is cut short: %w", io.ErrUnexpectedEOF)
+	}
+	return UnmarshalMARC(data)
+}
+
+func isASCII(data []byte) bool {
+	for _, b := range data {
+		if b >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
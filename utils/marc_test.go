@@ -0,0 +1,139 @@
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func duneRecord() *MARCRecord {
+	return &MARCRecord{
+		Leader: "00000cam a2200000 i 4500",
+		Fields: []MARCField{
+			{Tag: "001", Value: "ocm00123456"},
+			{Tag: "008", Value: "650101s1965    nyu           000 1 eng d"},
+			{Tag: "020", Indicators: [2]byte{' ', ' '}, Subfields: []MARCSubfield{{'a', "0441172717"}, {'q', "(pbk.)"}}},
+			{Tag: "100", Indicators: [2]byte{'1', ' '}, Subfields: []MARCSubfield{{'a', "Herbert, Frank,"}, {'e', "author."}}},
+			{Tag: "245", Indicators: [2]byte{'1', '0'}, Subfields: []MARCSubfield{{'a', "Dune /"}, {'c', "Frank Herbert."}}},
+			{Tag: "650", Indicators: [2]byte{' ', '0'}, Subfields: []MARCSubfield{{'a', "Arrakis (Imaginary place)"}, {'v', "Fiction."}}},
+		},
+	}
+}
+
+func TestMARCRoundTrip(t *testing.T) {
+	record := duneRecord()
+	data, err := MarshalMARC(record)
+	assert.NoError(t, err)
+	assert.Equal(t, byte(marcRecordTerminator), data[len(data)-1])
+	assert.Equal(t, fmt.Sprintf("%05d", len(data)), string(data[:5]))
+
+	decoded, err := UnmarshalMARC(data)
+	assert.NoError(t, err)
+	assert.Equal(t, record.Fields, decoded.Fields)
+	assert.Equal(t, record.Leader[5:12], decoded.Leader[5:12])
+
+	var xmlData bytes.Buffer
+	writer := NewMARCXMLWriter(&xmlData)
+	assert.NoError(t, writer.Write(decoded))
+	assert.NoError(t, writer.Close())
+	assert.Contains(t, xmlData.String(), `<collection xmlns="http://www.loc.gov/MARC21/slim">`)
+	assert.Contains(t, xmlData.String(), `<datafield tag="245" ind1="1" ind2="0">`)
+
+	fromXML, err := NewMARCXMLReader(&xmlData).Read()
+	assert.NoError(t, err)
+	assert.Equal(t, decoded, fromXML)
+}
+
+func TestMARCReader(t *testing.T) {
+	good, _ := MarshalMARC(duneRecord())
+	bad := append([]byte(nil), good...)
+	bad[30] = 'x' // in the length of the first directory entry
+
+	reader := NewMARCReader(bytes.NewReader(bytes.Join([][]byte{good, bad, good}, []byte("\r\n"))))
+	record, err := reader.Read()
+	assert.NoError(t, err)
+	assert.Equal(t, "Dune /", record.Field("245").Subfield('a'))
+	_, err = reader.Read()
+	assert.ErrorIs(t, err, ErrMalformedMARC)
+	_, err = reader.Read()
+	assert.NoError(t, err)
+	_, err = reader.Read()
+	assert.ErrorIs(t, err, io.EOF)
+
+	_, err = NewMARCReader(bytes.NewReader(good[:40])).Read()
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestUnmarshalMARCEncoding(t *testing.T) {
+	record := duneRecord()
+	record.Leader = "00000cam  2200000 i 4500"
+	data, _ := MarshalMARC(record)
+	decoded, err := UnmarshalMARC(data)
+	assert.NoError(t, err)
+	assert.Equal(t, byte('a'), decoded.Leader[9])
+
+	record.Fields[4].Subfields[0].Value = "Düne"
+	data, _ = MarshalMARC(record)
+	_, err = UnmarshalMARC(data)
+	assert.ErrorIs(t, err, ErrMalformedMARC)
+}
+
+func TestUnmarshalMARCRejectsBadNumbers(t *testing.T) {
+	for _, tc := range []struct {
+		at    int
+		value string
+	}{
+		{24 + 7, "-9999"},
+		{24 + 7, "+0001"},
+		{24 + 3, "-001"},
+		{12, "-0001"},
+	} {
+		data, _ := MarshalMARC(duneRecord())
+		copy(data[tc.at:], tc.value)
+		_, err := UnmarshalMARC(data)
+		assert.ErrorIs(t, err, ErrMalformedMARC, tc.value)
+	}
+}
+
+func TestMARCXMLReaderRejectsBadRecords(t *testing.T) {
+	doc := `<?xml version="1.0"?>
+<marc:collection xmlns:marc="http://www.loc.gov/MARC21/slim">
+  <marc:record><marc:leader>short</marc:leader></marc:record>
+  <marc:record>
+    <marc:leader>00000nam a2200000   4500</marc:leader>
+    <marc:datafield tag="245" ind1="0" ind2="0"><marc:subfield code="a">Emma</marc:subfield></marc:datafield>
+  </marc:record>
+</marc:collection>`
+	reader := NewMARCXMLReader(strings.NewReader(doc))
+	_, err := reader.Read()
+	assert.ErrorIs(t, err, ErrMalformedMARC)
+	record, err := reader.Read()
+	assert.NoError(t, err)
+	assert.Equal(t, "Emma", record.Field("245").Subfield('a'))
+	_, err = reader.Read()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestMARCFieldEditing(t *testing.T) {
+	record := duneRecord()
+	record.AddField(MARCField{Tag: "264", Subfields: []MARCSubfield{{'c', "1965"}}})
+	record.AddField(MARCField{Tag: "001"})
+	tags := make([]string, len(record.Fields))
+	for i, field := range record.Fields {
+		tags[i] = field.Tag
+	}
+	assert.Equal(t, []string{"001", "001", "008", "020", "100", "245", "264", "650"}, tags)
+
+	field := record.Field("245")
+	field.SetSubfield('a', "Dune Messiah")
+	field.SetSubfield('c', "")
+	field.SetSubfield('b', "a novel")
+	assert.Equal(t, []MARCSubfield{{'a', "Dune Messiah"}, {'b', "a novel"}}, field.Subfields)
+
+	record.RemoveField(record.Field("650"))
+	assert.Nil(t, record.Field("650"))
+}
@@ -0,0 +1,170 @@
+package util
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// MARCXMLNamespace is the namespace of MARCXML documents.
+const MARCXMLNamespace = "http://www.loc.gov/MARC21/slim"
+
+type marcXMLRecord struct {
+	XMLName       xml.Name              `xml:"record"`
+	Leader        string                `xml:"leader"`
+	ControlFields []marcXMLControlField `xml:"controlfield"`
+	DataFields    []marcXMLDataField    `xml:"datafield"`
+}
+
+type marcXMLControlField struct {
+	Tag   string `xml:"tag,attr"`
+	Value string `xml:",chardata"`
+}
+
+type marcXMLDataField struct {
+	Tag       string            `xml:"tag,attr"`
+	Ind1      string            `xml:"ind1,attr"`
+	Ind2      string            `xml:"ind2,attr"`
+	Subfields []marcXMLSubfield `xml:"subfield"`
+}
+
+type marcXMLSubfield struct {
+	Code  string `xml:"code,attr"`
+	Value string `xml:",chardata"`
+}
+
+// MARCXMLReader reads the records of a MARCXML collection, or a single
+// record, one at a time.
+type MARCXMLReader struct {
+	decoder *xml.Decoder
+}
+
+func NewMARCXMLReader(r io.Reader) *MARCXMLReader {
+	return &MARCXMLReader{xml.NewDecoder(r)}
+}
+
+// Read returns the next record, or io.EOF after the last one. Errors that
+// wrap ErrMalformedMARC reject one record and reading can go on; others
+// mean that the document cannot be read any further.
+func (r *MARCXMLReader) Read() (*MARCRecord, error) {
+	for {
+		token, err := r.decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "record" {
+			continue
+		}
+		var element marcXMLRecord
+		if err := r.decoder.DecodeElement(&element, &start); err != nil {
+			return nil, err
+		}
+		return element.record()
+	}
+}
+
+func (x *marcXMLRecord) record() (*MARCRecord, error) {
+	record := &MARCRecord{Leader: x.Leader}
+	if len(record.Leader) != 24 {
+		return nil, fmt.Errorf("%w: the leader is not 24 characters long", ErrMalformedMARC)
+	}
+	leader := []byte(record.Leader)
+	leader[9] = 'a'
+	record.Leader = string(leader)
+	for _, control := range x.ControlFields {
+		field := MARCField{Tag: control.Tag, Value: control.Value}
+		if len(field.Tag) != 3 || !field.IsControl() {
+			return nil, fmt.Errorf("%w: bad control field tag %q", ErrMalformedMARC, control.Tag)
+		}
+		record.Fields = append(record.Fields, field)
+	}
+	for _, data := range x.DataFields {
+		field := MARCField{Tag: data.Tag}
+		if len(field.Tag) != 3 || field.IsControl() || len(data.Ind1) > 1 || len(data.Ind2) > 1 {
+			return nil, fmt.Errorf("%w: bad data field %q", ErrMalformedMARC, data.Tag)
+		}
+		field.Indicators = [2]byte{indicator(data.Ind1), indicator(data.Ind2)}
+		for _, subfield := range data.Subfields {
+			if len(subfield.Code) != 1 {
+				return nil, fmt.Errorf("%w: bad subfield code %q in %s", ErrMalformedMARC, subfield.Code, data.Tag)
+			}
+			field.Subfields = append(field.Subfields, MARCSubfield{subfield.Code[0], subfield.Value})
+		}
+		record.Fields = append(record.Fields, field)
+	}
+	return record, nil
+}
+
+func indicator(value string) byte {
+	if value == "" {
+		return ' '
+	}
+	return value[0]
+}
+
+// MARCXMLWriter writes records as a MARCXML collection. Close ends the
+// collection but leaves the underlying writer open.
+type MARCXMLWriter struct {
+	encoder *xml.Encoder
+	started bool
+}
+
+func NewMARCXMLWriter(w io.Writer) *MARCXMLWriter {
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	return &MARCXMLWriter{encoder: encoder}
+}
+
+func (w *MARCXMLWriter) Write(record *MARCRecord) error {
+	if err := w.start(); err != nil {
+		return err
+	}
+	element := marcXMLRecord{Leader: record.Leader}
+	if len(element.Leader) != 24 {
+		element.Leader = MARCLeader
+	}
+	for _, field := range record.Fields {
+		if field.IsControl() {
+			element.ControlFields = append(element.ControlFields, marcXMLControlField{field.Tag, field.Value})
+			continue
+		}
+		indicators := marcIndicators(field.Indicators)
+		data := marcXMLDataField{Tag: field.Tag, Ind1: string(indicators[0]), Ind2: string(indicators[1])}
+		for _, subfield := range field.Subfields {
+			data.Subfields = append(data.Subfields, marcXMLSubfield{string(subfield.Code), subfield.Value})
+		}
+		element.DataFields = append(element.DataFields, data)
+	}
+	return w.encoder.Encode(element)
+}
+
+func (w *MARCXMLWriter) Close() error {
+	if err := w.start(); err != nil {
+		return err
+	}
+	if err := w.encoder.EncodeToken(xml.EndElement{Name: xml.Name{Local: "collection"}}); err != nil {
+		return err
+	}
+	if err := w.encoder.EncodeToken(xml.CharData("\n")); err != nil {
+		return err
+	}
+	return w.encoder.Flush()
+}
+
+func (w *MARCXMLWriter) start() error {
+	if w.started {
+		return nil
+	}
+	w.started = true
+	if err := w.encoder.EncodeToken(xml.ProcInst{Target: "xml", Inst: []byte(`version="1.0" encoding="UTF-8"`)}); err != nil {
+		return err
+	}
+	if err := w.encoder.EncodeToken(xml.CharData("\n")); err != nil {
+		return err
+	}
+	return w.encoder.EncodeToken(xml.StartElement{
+		Name: xml.Name{Local: "collection"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: MARCXMLNamespace}},
+	})
+}
@@ -0,0 +1,80 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		name, doc, patch, expected string
+	}{
+		{"Replace", `{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{"Add", `{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{"Remove", `{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{"Nested", `{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{"Arrays are replaced", `{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{"Non-object patch", `{"a":"foo"}`, `["c"]`, `["c"]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patched, err := MergePatch([]byte(tt.doc), []byte(tt.patch))
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(patched))
+		})
+	}
+
+	_, err := MergePatch([]byte(`{}`), []byte(`{`))
+	assert.ErrorIs(t, err, ErrMalformedPatch)
+}
+
+func TestJSONPatch(t *testing.T) {
+	tests := []struct {
+		name, doc, patch, expected string
+	}{
+		{"Add member", `{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux"}]`, `{"baz":"qux","foo":"bar"}`},
+		{"Add array element", `{"foo":["bar","baz"]}`, `[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}`},
+		{"Append", `{"foo":["bar"]}`, `[{"op":"add","path":"/foo/-","value":"baz"}]`, `{"foo":["bar","baz"]}`},
+		{"Remove", `{"baz":"qux","foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, `{"foo":"bar"}`},
+		{"Replace", `{"baz":"qux","foo":"bar"}`, `[{"op":"replace","path":"/baz","value":"boo"}]`, `{"baz":"boo","foo":"bar"}`},
+		{"Move", `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`, `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`, `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`},
+		{"Copy", `{"foo":["a"]}`, `[{"op":"copy","from":"/foo","path":"/bar"},{"op":"add","path":"/bar/-","value":"b"}]`, `{"foo":["a"],"bar":["a","b"]}`},
+		{"Escaped path", `{"a/b":1,"m~n":2}`, `[{"op":"remove","path":"/a~1b"},{"op":"test","path":"/m~0n","value":2}]`, `{"m~n":2}`},
+		{"Null value", `{"foo":"bar"}`, `[{"op":"replace","path":"/foo","value":null}]`, `{"foo":null}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patched, err := JSONPatch([]byte(tt.doc), []byte(tt.patch))
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(patched))
+		})
+	}
+}
+
+func TestJSONPatchErrors(t *testing.T) {
+	tests := []struct {
+		name, patch string
+		expected    error
+	}{
+		{"Failed test", `[{"op":"test","path":"/foo","value":"baz"}]`, ErrPatchConflict},
+		{"Missing member", `[{"op":"replace","path":"/nope","value":1}]`, ErrPatchConflict},
+		{"Missing parent", `[{"op":"add","path":"/a/b","value":1}]`, ErrPatchConflict},
+		{"Index out of range", `[{"op":"add","path":"/list/5","value":1}]`, ErrPatchConflict},
+		{"Unknown operation", `[{"op":"frobnicate","path":"/foo"}]`, ErrMalformedPatch},
+		{"Missing value", `[{"op":"add","path":"/foo"}]`, ErrMalformedPatch},
+		{"Move into itself", `[{"op":"move","from":"/list","path":"/list/0"}]`, ErrMalformedPatch},
+		{"Not an array", `{"op":"add"}`, ErrMalformedPatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := JSONPatch([]byte(`{"foo":"bar","list":[1]}`), []byte(tt.patch))
+			assert.ErrorIs(t, err, tt.expected)
+		})
+	}
+}
+
+func TestApplyPatchRejectsUnknownType(t *testing.T) {
+	_, err := ApplyPatch("application/xml", []byte(`{}`), []byte(`<a/>`))
+	assert.ErrorIs(t, err, ErrUnsupportedPatch)
+}
@@ -0,0 +1,408 @@
+package util
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+const (
+	// maxPDFScan is the largest file read whole; of larger files only the
+	// head and tail are scanned, which hold the metadata in nearly all
+	// writers.
+	maxPDFScan = 32 << 20
+	pdfWindow  = 4 << 20
+	// maxObjStm limits an inflated object stream.
+	maxObjStm = 8 << 20
+)
+
+var (
+	pdfInfoRef   = regexp.MustCompile(`/Info\s+(\d+)\s+(\d+)\s+R`)
+	pdfObjStm    = regexp.MustCompile(`(?s)(\d+)\s+\d+\s+obj\s*<<((?:[^>]|>[^>])*?/Type\s*/ObjStm(?:[^>]|>[^>])*?)>>\s*stream\r?\n`)
+	pdfStreamInt = regexp.MustCompile(`/(N|First|Length)\s+(\d+)`)
+)
+
+// ReadPDFMetadata reads the XMP packet and the document information
+// dictionary of a PDF file, preferring XMP where both give a field.
+func ReadPDFMetadata(r io.ReaderAt, size int64) (*EbookMetadata, error) {
+	data, err := pdfScan(r, size)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(data, []byte("%PDF-")) {
+		return nil, fmt.Errorf("%w: not a PDF file", ErrMalformedEbook)
+	}
+	metadata := &EbookMetadata{}
+	if xmp := pdfXMP(data); xmp != nil {
+		*metadata = *xmp
+	}
+	if info := pdfInfo(data); info != nil {
+		if metadata.Title == "" {
+			metadata.Title = cleanText(info["Title"])
+		}
+		if len(metadata.Authors) == 0 {
+			metadata.Authors = splitAuthors(info["Author"])
+		}
+		if metadata.Published == "" {
+			metadata.Published = pdfDate(info["CreationDate"])
+		}
+		if metadata.ISBN == "" {
+			for _, key := range []string{"ISBN", "Subject", "Keywords"} {
+				if isbn := ebookISBN(info[key]); isbn != "" {
+					metadata.ISBN = isbn
+					break
+				}
+			}
+		}
+	}
+	return metadata, nil
+}
+
+func pdfScan(r io.ReaderAt, size int64) ([]byte, error) {
+	if size <= maxPDFScan {
+		data := make([]byte, size)
+		if _, err := r.ReadAt(data, 0); err != nil && err != io.EOF {
+			return nil, err
+		}
+		return data, nil
+	}
+	data := make([]byte, 2*pdfWindow)
+	if _, err := r.ReadAt(data[:pdfWindow], 0); err != nil && err != io.EOF {
+		return nil, err
+	}
+	if _, err := r.ReadAt(data[pdfWindow:], size-pdfWindow); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return data, nil
+}
+
+// xmpMeta holds the Dublin Core and PRISM properties of an XMP packet.
+// encoding/xml cannot put namespaces on a path, so the elements are matched
+// by local name.
+type xmpMeta struct {
+	Descriptions []struct {
+		Title      []string `xml:"title>Alt>li"`
+		Creator    []string `xml:"creator>Seq>li"`
+		Identifier []string `xml:"identifier"`
+		Date       []string `xml:"date>Seq>li"`
+		ISBN       []string `xml:"isbn"`
+	} `xml:"RDF>Description"`
+}
+
+func pdfXMP(data []byte) *EbookMetadata {
+	start := bytes.Index(data, []byte("<x:xmpmeta"))
+	if start < 0 {
+		return nil
+	}
+	end := bytes.Index(data[start:], []byte("</x:xmpmeta>"))
+	if end < 0 {
+		return nil
+	}
+	var meta xmpMeta
+	if err := xml.Unmarshal(data[start:start+end+len("</x:xmpmeta>")], &meta); err != nil {
+		return nil
+	}
+	metadata := &EbookMetadata{}
+	for _, description := range meta.Descriptions {
+		if metadata.Title == "" && len(description.Title) > 0 {
+			metadata.Title = cleanText(description.Title[0])
+		}
+		for _, creator := range description.Creator {
+			if name := cleanText(creator); name != "" {
+				metadata.Authors = append(metadata.Authors, name)
+			}
+		}
+		for _, identifier := range append(description.ISBN, description.Identifier...) {
+			if isbn := ebookISBN(identifier); metadata.ISBN == "" && isbn != "" {
+				metadata.ISBN = isbn
+			}
+		}
+		if metadata.Published == "" && len(description.Date) > 0 {
+			metadata.Published = ebookDate(description.Date[0])
+		}
+	}
+	return metadata
+}
+
+// pdfInfo finds the document information dictionary from the trailer's
+// /Info reference. The last reference wins, since incremental updates
+// append trailers.
+func pdfInfo(data []byte) map[string]string {
+	refs := pdfInfoRef.FindAllSubmatch(data, -1)
+	if len(refs) == 0 {
+		return nil
+	}
+	ref := refs[len(refs)-1]
+	number, _ := strconv.Atoi(string(ref[1]))
+	object := pdfObject(data, number, string(ref[2]))
+	if object == nil {
+		return nil
+	}
+	lexer := &pdfLexer{data: object}
+	if lexer.next() != "<<" {
+		return nil
+	}
+	return lexer.dictionary()
+}
+
+// pdfObject returns the body of an indirect object, looking in object
+// streams when it is not stored directly.
+func pdfObject(data []byte, number int, generation string) []byte {
+	header := regexp.MustCompile(fmt.Sprintf(`(?:^|[^\d])%d\s+%s\s+obj\b`, number, generation))
+	if found := header.FindAllIndex(data, -1); len(found) > 0 {
+		return data[found[len(found)-1][1]:]
+	}
+	for _, match := range pdfObjStm.FindAllSubmatchIndex(data, -1) {
+		dictionary := data[match[4]:match[5]]
+		if !bytes.Contains(dictionary, []byte("/FlateDecode")) {
+			continue
+		}
+		values := map[string]int{}
+		for _, field := range pdfStreamInt.FindAllSubmatch(dictionary, -1) {
+			values[string(field[1])], _ = strconv.Atoi(string(field[2]))
+		}
+		start := match[1]
+		if values["Length"] <= 0 || values["Length"] > len(data)-start {
+			continue
+		}
+		stream, err := inflate(data[start : start+values["Length"]])
+		if err != nil || values["First"] > len(stream) {
+			continue
+		}
+		if object := objStmEntry(stream, values["N"], values["First"], number); object != nil {
+			return object
+		}
+	}
+	return nil
+}
+
+// objStmEntry finds an object in a decoded object stream, which starts
+// with n pairs of object number and offset from first.
+func objStmEntry(stream []byte, n, first, number int) []byte {
+	fields := strings.Fields(string(stream[:first]))
+	for i := 0; i+1 < len(fields) && i/2 < n; i += 2 {
+		if fields[i] != strconv.Itoa(number) {
+			continue
+		}
+		offset, err := strconv.Atoi(fields[i+1])
+		if err != nil || offset < 0 || offset > len(stream)-first {
+			return nil
+		}
+		return stream[first+offset:]
+	}
+	return nil
+}
+
+func inflate(data []byte) ([]byte, error) {
+	reader, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	out, err := io.ReadAll(io.LimitReader(reader, maxObjStm))
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	return out, nil
+}
+
+// pdfDate converts a date such as "D:19650801120000Z" to "1965-08-01".
+func pdfDate(value string) string {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "D:")
+	digits := 0
+	for digits < len(value) && digits < 8 && value[digits] >= '0' && value[digits] <= '9' {
+		digits++
+	}
+	switch {
+	case digits >= 8:
+		return value[:4] + "-" + value[4:6] + "-" + value[6:8]
+	case digits >= 6:
+		return value[:4] + "-" + value[4:6]
+	case digits >= 4:
+		return value[:4]
+	}
+	return ""
+}
+
+// splitAuthors splits an Author entry, which writers fill with one or more
+// names separated by semicolons or " and ".
+func splitAuthors(value string) []string {
+	var authors []string
+	for _, part := range strings.Split(strings.ReplaceAll(value, " and ", ";"), ";") {
+		if name := cleanText(part); name != "" {
+			authors = append(authors, name)
+		}
+	}
+	return authors
+}
+
+// pdfLexer reads the tokens of a dictionary: names, strings, numbers and
+// nested arrays and dictionaries.
+type pdfLexer struct {
+	data []byte
+	pos  int
+}
+
+// dictionary reads entries until ">>", keeping those with string values.
+func (l *pdfLexer) dictionary() map[string]string {
+	entries := make(map[string]string)
+	for {
+		key := l.next()
+		if key == "" || key == ">>" {
+			return entries
+		}
+		if !strings.HasPrefix(key, "/") {
+			continue
+		}
+		value := l.next()
+		switch {
+		case value == "":
+			return entries
+		case value == "<<":
+			l.dictionary()
+		case value == "[":
+			for token := l.next(); token != "]" && token != ""; token = l.next() {
+			}
+		case strings.HasPrefix(value, "("):
+			entries[key[1:]] = pdfText([]byte(value[1:]))
+		}
+	}
+}
+
+// next returns the next token. Strings are returned decoded and prefixed
+// with "(", whichever syntax they were written in.
+func (l *pdfLexer) next() string {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		switch {
+		case c == '%':
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\f' || c == 0:
+			l.pos++
+		case c == '(':
+			return "(" + string(l.literal())
+		case c == '<' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '<':
+			l.pos += 2
+			return "<<"
+		case c == '>' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '>':
+			l.pos += 2
+			return ">>"
+		case c == '<':
+			return "(" + string(l.hex())
+		case c == '[' || c == ']':
+			l.pos++
+			return string(c)
+		default:
+			start := l.pos
+			l.pos++
+			for l.pos < len(l.data) && !bytes.ContainsRune([]byte(" \t\r\n\f\x00()<>[]{}/%"), rune(l.data[l.pos])) {
+				l.pos++
+			}
+			return string(l.data[start:l.pos])
+		}
+	}
+	return ""
+}
+
+func (l *pdfLexer) literal() []byte {
+	var out []byte
+	depth := 0
+	for l.pos++; l.pos < len(l.data); l.pos++ {
+		c := l.data[l.pos]
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				l.pos++
+				return out
+			}
+			depth--
+		case '\\':
+			l.pos++
+			if l.pos >= len(l.data) {
+				return out
+			}
+			c = l.data[l.pos]
+			switch c {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r', '\n':
+				// A backslash at the end of a line continues the string.
+				if c == '\r' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '\n' {
+					l.pos++
+				}
+				continue
+			default:
+				if c >= '0' && c <= '7' {
+					value := 0
+					for i := 0; i < 3 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+						value = value*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					l.pos--
+					c = byte(value)
+				}
+			}
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+func (l *pdfLexer) hex() []byte {
+	var digits []byte
+	for l.pos++; l.pos < len(l.data) && l.data[l.pos] != '>'; l.pos++ {
+		if c := l.data[l.pos]; bytes.IndexByte([]byte("0123456789abcdefABCDEF"), c) >= 0 {
+			digits = append(digits, c)
+		}
+	}
+	l.pos++
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	out := make([]byte, len(digits)/2)
+	for i := range out {
+		value, _ := strconv.ParseUint(string(digits[2*i:2*i+2]), 16, 8)
+		out[i] = byte(value)
+	}
+	return out
+}
+
+// pdfText decodes a text string, which is UTF-16BE when it starts with a
+// byte order mark and PDFDocEncoding, close enough to Latin-1 for
+// metadata, otherwise.
+func pdfText(data []byte) string {
+	if len(data) >= 2 && data[0] == 0xFE && data[1] == 0xFF {
+		units := make([]uint16, (len(data)-2)/2)
+		for i := range units {
+			units[i] = binary.BigEndian.Uint16(data[2+2*i:])
+		}
+		return string(utf16.Decode(units))
+	}
+	if bytes.HasPrefix(data, []byte{0xEF, 0xBB, 0xBF}) {
+		return string(data[3:])
+	}
+	runes := make([]rune, len(data))
+	for i, c := range data {
+		runes[i] = rune(c)
+	}
+	return string(runes)
+}
@@ -0,0 +1,89 @@
+package util
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadPDFMetadata(t *testing.T) {
+	var objStm bytes.Buffer
+	z := zlib.NewWriter(&objStm)
+	z.Write([]byte("7 0 << /Title (Children of Dune) /Author (Frank Herbert) /CreationDate (D:197604) >>"))
+	z.Close()
+	var badOffset bytes.Buffer
+	z = zlib.NewWriter(&badOffset)
+	z.Write([]byte("7 -9 << /Title (Children of Dune) >>"))
+	z.Close()
+
+	tests := []struct {
+		name     string
+		pdf      string
+		expected EbookMetadata
+	}{
+		{
+			name: "info",
+			pdf: "%PDF-1.4\n1 0 obj\n<< /Type /Catalog >>\nendobj\n" +
+				"5 0 obj\n<< /Title <FEFF00440075006E0065> /Author (Frank Herbert; Brian Herbert)\n" +
+				"/Subject (ISBN 978-0-441-17271-9) /CreationDate (D:19650801120000Z) /Custom << /A (b) >> /Keys [(x) 1] >>\nendobj\n" +
+				"trailer\n<< /Root 1 0 R /Info 5 0 R >>\n%%EOF\n",
+			expected: EbookMetadata{Title: "Dune", Authors: []string{"Frank Herbert", "Brian Herbert"}, ISBN: "9780441172719", Published: "1965-08-01"},
+		},
+		{
+			name: "incremental update",
+			pdf: "%PDF-1.4\n5 0 obj\n<< /Title (Draft) >>\nendobj\ntrailer\n<< /Info 5 0 R >>\n" +
+				"6 0 obj\n<< /Title (Caf\\351 \\(Paris\\)) /Author (A and B) >>\nendobj\ntrailer\n<< /Info 6 0 R >>\n%%EOF\n",
+			expected: EbookMetadata{Title: "Café (Paris)", Authors: []string{"A", "B"}},
+		},
+		{
+			name: "xmp",
+			pdf: "%PDF-1.7\n3 0 obj\n<< /Type /Metadata /Subtype /XML >>\nstream\n" +
+				`<x:xmpmeta xmlns:x="adobe:ns:meta/"><rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">` +
+				`<rdf:Description xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:prism="http://prismstandard.org/namespaces/basic/2.0/">` +
+				`<dc:title><rdf:Alt><rdf:li xml:lang="x-default">Dune Messiah</rdf:li></rdf:Alt></dc:title>` +
+				`<dc:creator><rdf:Seq><rdf:li>Frank Herbert</rdf:li></rdf:Seq></dc:creator>` +
+				`<dc:date><rdf:Seq><rdf:li>1969-10</rdf:li></rdf:Seq></dc:date>` +
+				`<prism:isbn>0-441-17271-7</prism:isbn></rdf:Description></rdf:RDF></x:xmpmeta>` +
+				"\nendstream\nendobj\n5 0 obj\n<< /Title (Ignored) /Author (Ignored) >>\nendobj\ntrailer\n<< /Info 5 0 R >>\n%%EOF\n",
+			expected: EbookMetadata{Title: "Dune Messiah", Authors: []string{"Frank Herbert"}, ISBN: "0441172717", Published: "1969-10"},
+		},
+		{
+			name: "object stream",
+			pdf: fmt.Sprintf("%%PDF-1.5\n9 0 obj\n<< /Type /ObjStm /N 1 /First 4 /Filter /FlateDecode /Length %d >>\nstream\n%s\nendstream\nendobj\n"+
+				"10 0 obj\n<< /Type /XRef /Info 7 0 R >>\nendobj\n%%%%EOF\n", objStm.Len(), objStm.String()),
+			expected: EbookMetadata{Title: "Children of Dune", Authors: []string{"Frank Herbert"}, Published: "1976-04"},
+		},
+		{
+			name: "object stream with a negative offset",
+			pdf: fmt.Sprintf("%%PDF-1.5\n9 0 obj\n<< /Type /ObjStm /N 1 /First 5 /Filter /FlateDecode /Length %d >>\nstream\n%s\nendstream\nendobj\n"+
+				"10 0 obj\n<< /Type /XRef /Info 7 0 R >>\nendobj\n%%%%EOF\n", badOffset.Len(), badOffset.String()),
+			expected: EbookMetadata{},
+		},
+		{
+			name: "object stream with an oversized length",
+			pdf: "%PDF-1.5\n9 0 obj\n<< /Type /ObjStm /N 1 /First 5 /Filter /FlateDecode /Length 9223372036854775807 >>\nstream\nx\nendstream\nendobj\n" +
+				"10 0 obj\n<< /Type /XRef /Info 7 0 R >>\nendobj\n%%EOF\n",
+			expected: EbookMetadata{},
+		},
+		{
+			name:     "no metadata",
+			pdf:      "%PDF-1.4\ntrailer\n<< /Root 1 0 R >>\n%%EOF\n",
+			expected: EbookMetadata{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metadata, err := ReadPDFMetadata(bytes.NewReader([]byte(tt.pdf)), int64(len(tt.pdf)))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, *metadata)
+		})
+	}
+}
+
+func TestReadPDFMetadataNotPDF(t *testing.T) {
+	_, err := ReadPDFMetadata(bytes.NewReader([]byte("PK\x03\x04")), 4)
+	assert.ErrorIs(t, err, ErrMalformedEbook)
+}
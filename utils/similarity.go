@@ -0,0 +1,64 @@
+package util
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// FoldText reduces text to lower-case letters and digits separated by
+// single spaces, without accents, so that "Les Misérables." and "les
+// miserables" fold to the same string.
+func FoldText(s string) string {
+	folded, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn))), s)
+	if err != nil {
+		folded = s
+	}
+	return strings.Join(strings.FieldsFunc(strings.ToLower(folded), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+// SortWords returns the words of s in sorted order, so that "Herbert,
+// Frank" and "Frank Herbert" compare equal once folded.
+func SortWords(s string) string {
+	words := strings.Fields(s)
+	sort.Strings(words)
+	return strings.Join(words, " ")
+}
+
+// Similarity returns how alike two strings are, from 0 for nothing in
+// common to 1 for equal strings: one less the edit distance between them
+// over the length of the longer.
+func Similarity(a, b string) float64 {
+	x, y := []rune(a), []rune(b)
+	longer := max(len(x), len(y))
+	if longer == 0 {
+		return 1
+	}
+	return 1 - float64(editDistance(x, y))/float64(longer)
+}
+
+// editDistance is the Levenshtein distance between two strings.
+func editDistance(x, y []rune) int {
+	row := make([]int, len(y)+1)
+	for j := range row {
+		row[j] = j
+	}
+	for i := 1; i <= len(x); i++ {
+		diagonal := row[0]
+		row[0] = i
+		for j := 1; j <= len(y); j++ {
+			cost := 1
+			if x[i-1] == y[j-1] {
+				cost = 0
+			}
+			diagonal, row[j] = row[j], min(row[j]+1, row[j-1]+1, diagonal+cost)
+		}
+	}
+	return row[len(y)]
+}
@@ -0,0 +1,25 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFoldText(t *testing.T) {
+	assert.Equal(t, "les miserables", FoldText("  Les Misérables. "))
+	assert.Equal(t, "herbert frank", FoldText("Herbert, Frank"))
+	assert.Equal(t, "dune 2", FoldText("DUNE (#2)"))
+	assert.Equal(t, "", FoldText(" -- "))
+	assert.Equal(t, "frank herbert", SortWords(FoldText("Herbert, Frank")))
+}
+
+func TestSimilarity(t *testing.T) {
+	assert.Equal(t, 1.0, Similarity("", ""))
+	assert.Equal(t, 1.0, Similarity("dune", "dune"))
+	assert.Equal(t, 0.0, Similarity("dune", ""))
+	assert.Equal(t, 0.75, Similarity("dune", "dume"))
+	assert.Equal(t, 0.5, Similarity("dune", "dunedune"))
+	assert.InDelta(t, 0.57, Similarity("kitten", "sitting"), 0.01)
+	assert.Equal(t, Similarity("café", "cafe"), Similarity("cafe", "café"))
+}
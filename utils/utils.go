@@ -5,3 +5,20 @@ const (
 	SUPERVISOR_ROLE = "SUPERVISOR"
 	USER_ROLE       = "USER"
 )
+
+var roleHierarchy = map[string]int{
+	"user":       1,
+	"supervisor": 2,
+	"manager":    3,
+}
+
+// HasRole reports whether role is at least as privileged as requiredRole.
+func HasRole(role, requiredRole string) bool {
+	return roleHierarchy[role] >= roleHierarchy[requiredRole]
+}
+
+// IsRole reports whether role is one of the known roles.
+func IsRole(role string) bool {
+	_, ok := roleHierarchy[role]
+	return ok
+}